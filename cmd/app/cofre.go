@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/auth"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/cofre"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slack"
+	"github.com/manifoldco/promptui"
+)
+
+// tentativasSenhaCofre limita as tentativas de senha ao desbloquear o cofre
+const tentativasSenhaCofre = 3
+
+func comandoCofre(args []string) int {
+	if len(args) == 0 {
+		exibirAjuda()
+		return 2
+	}
+
+	cfg, err := config.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar configuração:", err)
+		return 1
+	}
+
+	relogio := common.RelogioSistema
+	c := cofre.Abrir(config.Diretorio())
+
+	switch args[0] {
+	case "criar":
+		return criarCofre(c)
+
+	case "desbloquear":
+		duracao := time.Duration(cfg.Cofre.TempoDesbloqueio)
+		if len(args) > 1 {
+			duracao, err = time.ParseDuration(args[1])
+			if err != nil || duracao <= 0 {
+				fmt.Printf("Duração inválida %q: use, por exemplo, 30m ou 8h\n", args[1])
+				return 2
+			}
+		}
+		if err := desbloquearCofre(c); err != nil {
+			fmt.Println("Erro ao desbloquear cofre:", err)
+			return 1
+		}
+		expira := relogio.Agora().Add(duracao)
+		if err := c.GuardarDesbloqueio(expira); err != nil {
+			fmt.Println("Erro ao manter o cofre desbloqueado:", err)
+			return 1
+		}
+		fmt.Printf("✓ Cofre desbloqueado até %s\n", expira.Format("02/01 15:04"))
+		return 0
+
+	case "bloquear":
+		if err := cofre.EsquecerDesbloqueio(); err != nil {
+			fmt.Println("Erro ao bloquear cofre:", err)
+			return 1
+		}
+		fmt.Println("✓ Cofre bloqueado")
+		return 0
+
+	case "alterar-senha":
+		if err := desbloquearCofre(c); err != nil {
+			fmt.Println("Erro ao desbloquear cofre:", err)
+			return 1
+		}
+		nova, err := solicitarNovaSenhaCofre()
+		if err != nil {
+			fmt.Println("Erro ao ler senha:", err)
+			return 1
+		}
+		if err := c.AlterarSenha(nova); err != nil {
+			fmt.Println("Erro ao alterar senha do cofre:", err)
+			return 1
+		}
+		// A chave em cache pertence à senha anterior
+		if err := cofre.EsquecerDesbloqueio(); err != nil {
+			fmt.Printf("\n⚠️  Aviso: %v\n", err)
+		}
+		fmt.Println("✓ Senha do cofre alterada")
+		return 0
+
+	case "status":
+		if !c.Existe() {
+			fmt.Println("Cofre não criado; credenciais e cookies ficam em arquivos sem cifra")
+			return 0
+		}
+		fmt.Println("Cofre:", c.Caminho())
+		if expira := cofre.ExpiracaoDesbloqueio(relogio.Agora()); !expira.IsZero() {
+			fmt.Printf("Desbloqueado em cache até %s\n", expira.Format("02/01 15:04"))
+		} else {
+			fmt.Println("Bloqueado")
+		}
+		return 0
+
+	default:
+		fmt.Printf("Subcomando desconhecido: cofre %s\n\n", args[0])
+		exibirAjuda()
+		return 2
+	}
+}
+
+// criarCofre cria o cofre e migra para ele as credenciais do .env e os cookies do Slack
+func criarCofre(c *cofre.Cofre) int {
+	if c.Existe() {
+		fmt.Printf("O cofre já existe em %s\n", c.Caminho())
+		return 1
+	}
+
+	senha, err := solicitarNovaSenhaCofre()
+	if err != nil {
+		fmt.Println("Erro ao ler senha:", err)
+		return 1
+	}
+	if err := c.Criar(senha); err != nil {
+		fmt.Println("Erro ao criar cofre:", err)
+		return 1
+	}
+	fmt.Println("✓ Cofre criado em", c.Caminho())
+
+	codigo := 0
+	migrado, err := auth.MigrarCredenciais(config.Diretorio(), c)
+	if err != nil {
+		fmt.Println("Erro ao migrar credenciais:", err)
+		codigo = 1
+	} else if migrado {
+		fmt.Println("✓ Credenciais do .env movidas para o cofre")
+	}
+
+	migrado, err = slack.MigrarCookies(config.Diretorio(), c)
+	if err != nil {
+		fmt.Println("Erro ao migrar cookies do Slack:", err)
+		codigo = 1
+	} else if migrado {
+		fmt.Println("✓ Cookies do Slack movidos para o cofre")
+	}
+	return codigo
+}
+
+// abrirCofre retorna o cofre desbloqueado pelo cache ou pela senha solicitada. Sem cofre
+// criado, retorna um cofre inexistente, e os módulos usam os arquivos sem cifra.
+func abrirCofre(relogio common.Relogio) (*cofre.Cofre, error) {
+	c := cofre.Abrir(config.Diretorio())
+	if !c.Existe() {
+		return c, nil
+	}
+	if err := c.DesbloquearPorCache(relogio.Agora()); err == nil {
+		return c, nil
+	}
+	if err := desbloquearCofre(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// desbloquearCofre pede a senha até acertar ou esgotar as tentativas
+func desbloquearCofre(c *cofre.Cofre) error {
+	if !c.Existe() {
+		return cofre.ErrInexistente
+	}
+	for tentativa := 1; ; tentativa++ {
+		prompt := promptui.Prompt{Label: "Senha do cofre", Mask: '*'}
+		senha, err := prompt.Run()
+		if err != nil {
+			return fmt.Errorf("erro ao ler senha: %w", err)
+		}
+
+		err = c.Desbloquear(senha)
+		if !errors.Is(err, cofre.ErrSenhaIncorreta) || tentativa == tentativasSenhaCofre {
+			return err
+		}
+		fmt.Println("Senha incorreta, tente novamente")
+	}
+}
+
+// solicitarNovaSenhaCofre pede a nova senha duas vezes
+func solicitarNovaSenhaCofre() (string, error) {
+	prompt := promptui.Prompt{
+		Label: "Nova senha do cofre",
+		Mask:  '*',
+		Validate: func(input string) error {
+			if len(input) < 8 {
+				return fmt.Errorf("a senha do cofre deve ter pelo menos 8 caracteres")
+			}
+			return nil
+		},
+	}
+	senha, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	confirmacao := promptui.Prompt{Label: "Confirme a senha", Mask: '*'}
+	repetida, err := confirmacao.Run()
+	if err != nil {
+		return "", err
+	}
+	if repetida != senha {
+		return "", errors.New("as senhas não conferem")
+	}
+	return senha, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+)
+
+// executarComando trata os subcomandos da linha de comando e retorna o código de saída
+func executarComando(args []string) int {
+	switch args[0] {
+	case "seletores":
+		return comandoSeletores(args[1:])
+	case "snapshots":
+		return comandoSnapshots(args[1:])
+	case "localizacao":
+		return comandoLocalizacao(args[1:])
+	case "cofre":
+		return comandoCofre(args[1:])
+	case "credenciais":
+		return comandoCredenciais(args[1:])
+	case "lembretes":
+		return comandoLembretes(args[1:])
+	case "provedores":
+		exibirProvedores()
+		return 0
+	case "ajuda", "-h", "--help":
+		exibirAjuda()
+		return 0
+	default:
+		fmt.Printf("Comando desconhecido: %s\n\n", args[0])
+		exibirAjuda()
+		return 2
+	}
+}
+
+func exibirAjuda() {
+	fmt.Println("Uso: batponto [opções] [comando]")
+	fmt.Println()
+	fmt.Println("Sem comando, inicia o menu interativo.")
+	fmt.Println()
+	fmt.Println("Opções:")
+	fmt.Println("  --debug-browser[=atraso]     abre o Chromium visível com o DevTools, espera o atraso (padrão depuracao.atraso)")
+	fmt.Println("                               entre as ações, destaca os elementos clicados e para nos erros até o Enter")
+	fmt.Println()
+	fmt.Println("Comandos:")
+	fmt.Println("  seletores validar            verifica o mapa de seletores nas páginas do Softtrade e do Slack")
+	fmt.Println("  seletores exportar           exibe o mapa de seletores em uso, como base para ~/.batedorponto/seletores.json")
+	fmt.Println("  snapshots listar [dir]       lista os snapshots gravados das páginas")
+	fmt.Println("  snapshots reproduzir [dir]   executa os extratores nos snapshots e compara com o resultado gravado")
+	fmt.Println("  localizacao detectar         exibe a rede atual e a localização detectada pelas regras de deteccao")
+	fmt.Println("  cofre criar                  cria o cofre cifrado e move para ele as credenciais do .env e os cookies do Slack")
+	fmt.Println("  cofre desbloquear [d]        mantém o cofre desbloqueado por d (padrão cofre.tempo_desbloqueio) para outros processos")
+	fmt.Println("  cofre bloquear               descarta o desbloqueio em cache")
+	fmt.Println("  cofre alterar-senha          recifra o cofre com uma nova senha")
+	fmt.Println("  cofre status                 exibe se o cofre existe e se está desbloqueado")
+	fmt.Println("  credenciais show             exibe o usuário mascarado, a origem das credenciais e o armazenamento")
+	fmt.Println("  credenciais test             faz o login headless no Softtrade e informa o tipo do erro, se houver")
+	fmt.Println("  credenciais update           solicita e grava novas credenciais no armazenamento")
+	fmt.Println("  credenciais totp             salva o segredo do aplicativo autenticador para gerar o código de verificação")
+	fmt.Println("  credenciais slack-token      salva o token usado pelo backend \"api\" do Slack")
+	fmt.Println("  credenciais delete           apaga as credenciais do armazenamento")
+	fmt.Println("  lembretes verificar          envia os alertas de marcações atrasadas de hoje, se houver")
+	fmt.Println("  lembretes monitorar          verifica as marcações periodicamente e alerta até ser interrompido")
+	fmt.Println("  lembretes adiar <tipo> [d]   silencia o alerta de entrada, almoco ou saida por d (padrão lembretes.adiamento)")
+	fmt.Println("  provedores                   lista os provedores de ponto disponíveis para ponto.provedor")
+	fmt.Println("  ajuda                        exibe esta mensagem")
+}
+
+// exibirProvedores lista os provedores de ponto registrados e seus recursos
+func exibirProvedores() {
+	for _, p := range clockin.Provedores() {
+		var recursos []string
+		if p.Capacidades.Localizacoes {
+			recursos = append(recursos, "localizações")
+		}
+		if p.Capacidades.Historico {
+			recursos = append(recursos, "histórico")
+		}
+		if p.Capacidades.Modal {
+			recursos = append(recursos, "intervalo opcional")
+		}
+		if p.UsaNavegador {
+			recursos = append(recursos, "navegador")
+		}
+		fmt.Printf("  %-16s %s [%s]\n", p.Nome, p.Descricao, strings.Join(recursos, ", "))
+	}
+}
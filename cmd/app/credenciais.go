@@ -0,0 +1,325 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/auth"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/cofre"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slack"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/ui"
+	"github.com/manifoldco/promptui"
+)
+
+// abrirArmazenamento retorna o armazenamento de credenciais configurado, pedindo a senha do
+// cofre apenas quando ele será usado
+func abrirArmazenamento(cfg config.Credenciais, relogio common.Relogio) (auth.CredentialStore, error) {
+	var vault *cofre.Cofre
+	switch cfg.Armazenamento {
+	case "", auth.StoreAuto, auth.StoreVault:
+		var err error
+		vault, err = abrirCofre(relogio)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return auth.OpenCredentialStore(cfg.Armazenamento, config.Diretorio(), vault)
+}
+
+// fontesCredenciais monta, na ordem configurada, as fontes consultadas antes de pedir as
+// credenciais ao usuário
+func fontesCredenciais(cfg config.Credenciais, armazenamento auth.CredentialStore) ([]auth.CredentialSource, error) {
+	var fontes []auth.CredentialSource
+	for _, nome := range cfg.OrdemFontes() {
+		switch nome {
+		case config.FonteAmbiente:
+			fontes = append(fontes, auth.EnvSource{})
+		case config.FonteComando:
+			if cfg.Comando == "" {
+				return nil, errors.New("a fonte \"comando\" exige credenciais.comando na configuração")
+			}
+			fontes = append(fontes, auth.CommandSource{
+				Command:  cfg.Comando,
+				Username: cfg.Usuario,
+				Timeout:  time.Duration(cfg.TempoLimite),
+			})
+		case config.FonteStdin:
+			fontes = append(fontes, auth.StdinSource{
+				Username: cfg.Usuario,
+				Timeout:  time.Duration(cfg.TempoLimite),
+			})
+		case config.FonteArmazenamento:
+			fontes = append(fontes, auth.StoreSource{Store: armazenamento})
+		default:
+			return nil, fmt.Errorf("fonte de credenciais desconhecida %q (use %s, %s, %s ou %s)",
+				nome, config.FonteAmbiente, config.FonteComando, config.FonteStdin, config.FonteArmazenamento)
+		}
+	}
+	return fontes, nil
+}
+
+// segredoTOTP retorna o segredo do segundo fator salvo com "credenciais totp", ou vazio
+func segredoTOTP(armazenamento auth.CredentialStore) string {
+	segredo, err := armazenamento.LoadSecret(auth.SecretTOTP)
+	if err != nil {
+		if !errors.Is(err, auth.ErrSecretNotFound) {
+			fmt.Printf("\n⚠️  Aviso: Segredo TOTP indisponível: %v\n", err)
+		}
+		return ""
+	}
+	return string(segredo)
+}
+
+// tentativasTrocaSenha limita as novas senhas recusadas pelo Softtrade na troca guiada
+const tentativasTrocaSenha = 3
+
+// trocarSenha conduz a troca de senha exigida pelo Softtrade e, se as credenciais
+// vieram do armazenamento, o atualiza com a nova senha. fonte é nil para credenciais digitadas.
+func trocarSenha(authModule auth.Module, creds auth.Credentials, fonte auth.CredentialSource) (auth.Credentials, error) {
+	trocador, ok := authModule.(auth.PasswordChanger)
+	if !ok {
+		return creds, errors.New("este provedor não permite a troca de senha; troque-a pelo site do Softtrade e execute o batedor novamente")
+	}
+
+	fmt.Println("\n🔑 O Softtrade exige uma nova senha.")
+	for tentativa := 1; ; tentativa++ {
+		nova, err := auth.SolicitarNovaSenha()
+		if err != nil {
+			return creds, err
+		}
+
+		err = trocador.ChangePassword(creds.Password, nova)
+		if err == nil {
+			creds.Password = nova
+			break
+		}
+		if errors.Is(err, auth.ErrPasswordFormAbsent) {
+			return creds, fmt.Errorf("%w; troque a senha pelo site do Softtrade e execute o batedor novamente", err)
+		}
+		if tipoErroLogin(err) != "password_change" || tentativa == tentativasTrocaSenha {
+			return creds, err
+		}
+		fmt.Printf("\n⚠️  Aviso: %v\n", err)
+	}
+	fmt.Println("✓ Senha trocada no Softtrade")
+
+	switch f := fonte.(type) {
+	case nil:
+	case auth.StoreSource:
+		if err := f.Store.Save(creds); err != nil {
+			fmt.Printf("\n⚠️  Aviso: A nova senha não foi salva: %v\n", err)
+		} else {
+			fmt.Printf("✓ Credenciais atualizadas (%s)\n", f.Store.Name())
+		}
+	default:
+		fmt.Printf("\n⚠️  Aviso: Atualize a nova senha em %s\n", f.Name())
+	}
+	return creds, nil
+}
+
+func comandoCredenciais(args []string) int {
+	if len(args) == 0 {
+		exibirAjuda()
+		return 2
+	}
+
+	cfg, err := config.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar configuração:", err)
+		return 1
+	}
+
+	armazenamento, err := abrirArmazenamento(cfg.Credenciais, common.RelogioSistema)
+	if err != nil {
+		fmt.Println("Erro ao abrir armazenamento de credenciais:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "show":
+		fontes, err := fontesCredenciais(cfg.Credenciais, armazenamento)
+		if err != nil {
+			fmt.Println("Erro na configuração de credenciais:", err)
+			return 1
+		}
+		fmt.Println("Armazenamento:", armazenamento.Name())
+		creds, fonte, err := auth.CarregarCredenciais(fontes...)
+		if err != nil {
+			fmt.Println("Nenhuma credencial encontrada; elas serão solicitadas na próxima execução")
+			return 0
+		}
+		fmt.Println("Usuário:", creds.MaskedUsername())
+		fmt.Println("Origem:", fonte.Name())
+		return 0
+
+	case "test":
+		return testarCredenciais(cfg, armazenamento)
+
+	case "update":
+		creds, err := auth.SolicitarCredenciais()
+		if err != nil {
+			fmt.Println("Erro ao obter credenciais:", err)
+			return 1
+		}
+		if err := armazenamento.Save(creds); err != nil {
+			fmt.Println("Erro ao salvar credenciais:", err)
+			return 1
+		}
+		fmt.Printf("✓ Credenciais atualizadas (%s)\n", armazenamento.Name())
+		return 0
+
+	case "totp":
+		return salvarSegredoTOTP(armazenamento)
+
+	case "slack-token":
+		return salvarTokenSlack(armazenamento)
+
+	case "delete":
+		confirmacao := promptui.Prompt{
+			Label:     fmt.Sprintf("Apagar as credenciais de %s", armazenamento.Name()),
+			IsConfirm: true,
+		}
+		if _, err := confirmacao.Run(); err != nil {
+			fmt.Println("Credenciais mantidas")
+			return 0
+		}
+		if err := armazenamento.Delete(); err != nil {
+			fmt.Println("Erro ao apagar credenciais:", err)
+			return 1
+		}
+		fmt.Printf("✓ Credenciais apagadas (%s)\n", armazenamento.Name())
+		return 0
+
+	default:
+		fmt.Printf("Subcomando desconhecido: credenciais %s\n\n", args[0])
+		exibirAjuda()
+		return 2
+	}
+}
+
+// testarCredenciais faz o login headless com as credenciais encontradas (ou digitadas) e
+// informa o tipo do erro de login. A sessão salva não é reaproveitada, para que a senha
+// seja de fato verificada.
+func testarCredenciais(cfg *config.Config, armazenamento auth.CredentialStore) int {
+	uiModule := ui.NewModule()
+
+	fontes, err := fontesCredenciais(cfg.Credenciais, armazenamento)
+	if err != nil {
+		fmt.Println("Erro na configuração de credenciais:", err)
+		return 1
+	}
+	creds, fonte, err := auth.CarregarCredenciais(fontes...)
+	if err != nil {
+		creds, err = auth.SolicitarCredenciais()
+		if err != nil {
+			fmt.Println("Erro ao obter credenciais:", err)
+			return 1
+		}
+	} else {
+		fmt.Printf("Testando %s (%s)\n", creds.MaskedUsername(), fonte.Name())
+	}
+
+	seletores, err := selectors.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar seletores:", err)
+		return 1
+	}
+
+	navegador := novoNavegador(cfg)
+	defer navegador.Close()
+
+	loading := uiModule.ShowSpinner("Realizando login")
+	loading.Start()
+	authModule, err := auth.NewModule(auth.Config{
+		Headless:    !depuracaoNavegador.ativa,
+		Navegador:   navegador,
+		URLBase:     cfg.Ponto.URLBase,
+		Seletores:   &seletores.Softtrade,
+		SegredoTOTP: segredoTOTP(armazenamento),
+		SolicitanteCodigo: auth.OTPPrompterFunc(func() (string, error) {
+			loading.Stop()
+			defer loading.Start()
+			return uiModule.SolicitarCodigoVerificacao()
+		}),
+	})
+	if err != nil {
+		loading.Error(err)
+		return 1
+	}
+	defer authModule.Close()
+
+	if err := authModule.Login(creds); err != nil {
+		loading.Error(err)
+		tipo := tipoErroLogin(err)
+		if tipo == "" {
+			tipo = "desconhecido"
+		}
+		fmt.Printf("\n✖ Login falhou (%s): %v\n", tipo, err)
+		if tipo == "auth" || tipo == "password_expired" || tipo == "password_change" {
+			fmt.Println("Atualize as credenciais com \"batponto credenciais update\"")
+		}
+		return 1
+	}
+	loading.Success()
+	fmt.Println("\n✓ Login aceito pelo Softtrade")
+	return 0
+}
+
+// salvarSegredoTOTP grava o segredo do aplicativo autenticador, exibindo o código atual
+// para conferência
+func salvarSegredoTOTP(armazenamento auth.CredentialStore) int {
+	prompt := promptui.Prompt{
+		Label: "Segredo TOTP (base32 ou URI otpauth://)",
+		Mask:  '*',
+		Validate: func(input string) error {
+			_, err := auth.TOTPCode(input, time.Now())
+			return err
+		},
+	}
+	segredo, err := prompt.Run()
+	if err != nil {
+		fmt.Println("Erro ao ler segredo:", err)
+		return 1
+	}
+
+	codigo, _ := auth.TOTPCode(segredo, time.Now())
+	fmt.Printf("Código atual: %s (confira com o aplicativo autenticador)\n", codigo)
+
+	if err := armazenamento.SaveSecret(auth.SecretTOTP, []byte(strings.TrimSpace(segredo))); err != nil {
+		fmt.Println("Erro ao salvar segredo TOTP:", err)
+		return 1
+	}
+	fmt.Printf("✓ Segredo TOTP salvo (%s)\n", armazenamento.Name())
+	return 0
+}
+
+// salvarTokenSlack grava o token usado pelo backend "api" do Slack
+func salvarTokenSlack(armazenamento auth.CredentialStore) int {
+	prompt := promptui.Prompt{
+		Label: "Token do Slack (xoxp-... ou xoxc-...)",
+		Mask:  '*',
+		Validate: func(input string) error {
+			if !strings.HasPrefix(strings.TrimSpace(input), "xox") {
+				return fmt.Errorf("o token deve começar com xox")
+			}
+			return nil
+		},
+	}
+	token, err := prompt.Run()
+	if err != nil {
+		fmt.Println("Erro ao ler token:", err)
+		return 1
+	}
+
+	if err := armazenamento.SaveSecret(slack.ChaveToken, []byte(strings.TrimSpace(token))); err != nil {
+		fmt.Println("Erro ao salvar token do Slack:", err)
+		return 1
+	}
+	fmt.Printf("✓ Token do Slack salvo (%s)\n", armazenamento.Name())
+	return 0
+}
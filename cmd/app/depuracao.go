@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+)
+
+// opcaoDepuracao é a opção global que abre o navegador visível para depurar seletores
+const opcaoDepuracao = "--debug-browser"
+
+// depuracaoNavegador guarda a opção --debug-browser[=<atraso>] da linha de comando
+var depuracaoNavegador struct {
+	ativa  bool
+	atraso time.Duration
+}
+
+// extrairOpcoes remove as opções globais dos argumentos, em qualquer posição, e retorna
+// os argumentos restantes
+func extrairOpcoes(args []string) ([]string, error) {
+	var restantes []string
+	for _, arg := range args {
+		if arg != opcaoDepuracao && !strings.HasPrefix(arg, opcaoDepuracao+"=") {
+			restantes = append(restantes, arg)
+			continue
+		}
+
+		depuracaoNavegador.ativa = true
+		if valor, ok := strings.CutPrefix(arg, opcaoDepuracao+"="); ok {
+			atraso, err := time.ParseDuration(valor)
+			if err != nil || atraso < 0 {
+				return nil, fmt.Errorf("atraso inválido %q em %s: use, por exemplo, 500ms ou 2s", valor, opcaoDepuracao)
+			}
+			depuracaoNavegador.atraso = atraso
+		}
+	}
+	return restantes, nil
+}
+
+// novoNavegador cria a instância compartilhada do Chromium: headless ou, com
+// --debug-browser, visível com o DevTools, o atraso entre as ações e a parada nos erros.
+// cfg nil usa o atraso padrão.
+func novoNavegador(cfg *config.Config) *common.GerenciadorNavegador {
+	if !depuracaoNavegador.ativa {
+		return common.NovoGerenciadorNavegador(true)
+	}
+
+	if cfg == nil {
+		cfg = config.Padrao()
+	}
+	atraso := time.Duration(cfg.Depuracao.Atraso)
+	if depuracaoNavegador.atraso > 0 {
+		atraso = depuracaoNavegador.atraso
+	}
+	fmt.Printf("🐞 Navegador em modo de depuração (%s entre as ações)\n", atraso)
+	return common.NovoGerenciadorDepuracao(common.Depuracao{Atraso: atraso})
+}
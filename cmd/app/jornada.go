@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/jornada"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/journal"
+	"github.com/manifoldco/promptui"
+)
+
+// marcacoesDoDia retorna as marcações de hoje, do histórico do provedor quando disponível
+// ou, caso contrário, do diário local
+func marcacoesDoDia(pontoModule clockin.Module, provedor clockin.Provedor, diario *journal.Diario, agora time.Time) ([]clockin.Marcacao, error) {
+	if historico, ok := pontoModule.(clockin.Historico); ok && provedor.Capacidades.Historico {
+		marcacoes, err := historico.ObterMarcacoes()
+		if err != nil {
+			return nil, err
+		}
+
+		var hoje []clockin.Marcacao
+		ano, mes, dia := agora.Date()
+		for _, marcacao := range marcacoes {
+			if a, m, d := marcacao.Momento.In(agora.Location()).Date(); a == ano && m == mes && d == dia {
+				hoje = append(hoje, marcacao)
+			}
+		}
+		return hoje, nil
+	}
+
+	registros, err := diario.Ler(agora)
+	if err != nil {
+		return nil, err
+	}
+	return jornada.DoDiario(registros), nil
+}
+
+// validarMarcacao confere a operação contra as marcações do dia. Retorna false se a
+// marcação foi recusada ou se o usuário desistiu após um aviso.
+func validarMarcacao(cfg config.Jornada, pontoModule clockin.Module, provedor clockin.Provedor, diario *journal.Diario, relogio common.Relogio, operacao clockin.TipoOperacao) (bool, error) {
+	if !cfg.Validar {
+		return true, nil
+	}
+
+	agora := relogio.Agora()
+	marcacoes, err := marcacoesDoDia(pontoModule, provedor, diario, agora)
+	if err != nil {
+		fmt.Printf("\n⚠️  Aviso: Não foi possível conferir as marcações do dia: %v\n", err)
+		return true, nil
+	}
+
+	dia := jornada.Nova(jornada.Regras{
+		JanelaDuplicidade: time.Duration(cfg.JanelaDuplicidade),
+		IntervaloMinimo:   time.Duration(cfg.IntervaloMinimo),
+	}, marcacoes)
+
+	violacao := dia.Validar(operacao, agora)
+	if violacao == nil {
+		return true, nil
+	}
+
+	if violacao.Gravidade == jornada.Bloqueio {
+		fmt.Printf("\n✖ Marcação recusada (%s): %s\n", dia.Estado(), violacao.Motivo)
+		return false, nil
+	}
+
+	fmt.Printf("\n⚠️  Aviso (%s): %s\n", dia.Estado(), violacao.Motivo)
+	prompt := promptui.Prompt{
+		Label:     "Marcar mesmo assim",
+		IsConfirm: true,
+		Default:   "n",
+	}
+	if _, err := prompt.Run(); err != nil {
+		if err == promptui.ErrAbort {
+			fmt.Println("\n✖ Operação cancelada")
+			return false, nil
+		}
+		return false, fmt.Errorf("erro na confirmação: %w", err)
+	}
+	return true, nil
+}
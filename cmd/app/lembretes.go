@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/jornada"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/journal"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/lembretes"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slack"
+)
+
+func comandoLembretes(args []string) int {
+	if len(args) == 0 {
+		exibirAjuda()
+		return 2
+	}
+
+	cfg, err := config.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar configuração:", err)
+		return 1
+	}
+
+	regras, err := regrasLembretes(cfg.Lembretes)
+	if err != nil {
+		fmt.Println("Erro na configuração dos lembretes:", err)
+		return 1
+	}
+
+	relogio := common.RelogioSistema
+	adiamentos := lembretes.AbrirAdiamentos(config.Diretorio())
+
+	switch args[0] {
+	case "verificar":
+		diario := journal.Abrir(config.Diretorio(), relogio)
+		alertas, err := alertasAtivos(regras, diario, adiamentos, relogio.Agora())
+		if err != nil {
+			fmt.Println("Erro ao verificar marcações:", err)
+			return 1
+		}
+		if len(alertas) == 0 {
+			fmt.Println("✓ Nenhuma marcação atrasada")
+			return 0
+		}
+
+		notificadores, fechar := notificadoresLembrete(context.Background(), cfg, relogio)
+		defer fechar()
+		for _, alerta := range alertas {
+			if err := lembretes.Notificar(notificadores, alerta); err != nil {
+				fmt.Printf("\n⚠️  Aviso: Falha ao enviar alerta: %v\n", err)
+			}
+		}
+		return 0
+
+	case "monitorar":
+		return monitorarLembretes(cfg, regras, adiamentos, relogio)
+
+	case "adiar":
+		if len(args) < 2 {
+			fmt.Printf("Informe o alerta a adiar: %v\n", lembretes.Tipos)
+			return 2
+		}
+		duracao := time.Duration(cfg.Lembretes.Adiamento)
+		if len(args) > 2 {
+			duracao, err = time.ParseDuration(args[2])
+			if err != nil || duracao <= 0 {
+				fmt.Printf("Duração inválida %q: use, por exemplo, 15m ou 1h\n", args[2])
+				return 2
+			}
+		}
+
+		ate := relogio.Agora().Add(duracao)
+		if err := adiamentos.Adiar(args[1], ate); err != nil {
+			fmt.Println("Erro ao adiar alerta:", err)
+			return 1
+		}
+		fmt.Printf("✓ Alerta de %s adiado até %s\n", args[1], ate.Format("15:04"))
+		return 0
+
+	default:
+		exibirAjuda()
+		return 2
+	}
+}
+
+// regrasLembretes converte a configuração nas regras dos alertas
+func regrasLembretes(cfg config.Lembretes) (lembretes.Regras, error) {
+	entradaAte, err := cfg.HorarioEntrada()
+	if err != nil {
+		return lembretes.Regras{}, err
+	}
+	diasUteis, err := cfg.DiasDaSemana()
+	if err != nil {
+		return lembretes.Regras{}, err
+	}
+	folgas, err := cfg.DatasFolga()
+	if err != nil {
+		return lembretes.Regras{}, err
+	}
+	return lembretes.Regras{
+		EntradaAte:    entradaAte,
+		DuracaoAlmoco: time.Duration(cfg.DuracaoAlmoco),
+		CargaHoraria:  time.Duration(cfg.CargaHoraria),
+		DiasUteis:     diasUteis,
+		Folgas:        folgas,
+	}, nil
+}
+
+// alertasAtivos calcula os alertas a partir das marcações do dia no diário, sem os adiados
+func alertasAtivos(regras lembretes.Regras, diario *journal.Diario, adiamentos *lembretes.Adiamentos, agora time.Time) ([]lembretes.Alerta, error) {
+	registros, err := diario.Ler(agora)
+	if err != nil {
+		return nil, err
+	}
+	alertas := lembretes.Verificar(regras, jornada.DoDiario(registros), agora)
+	return adiamentos.Filtrar(alertas, agora)
+}
+
+// monitorarLembretes verifica as marcações periodicamente até ser interrompido, repetindo
+// cada alerta ativo no máximo uma vez por lembretes.repeticao
+func monitorarLembretes(cfg *config.Config, regras lembretes.Regras, adiamentos *lembretes.Adiamentos, relogio common.Relogio) int {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	diario := journal.Abrir(config.Diretorio(), relogio)
+	notificadores, fechar := notificadoresLembrete(ctx, cfg, relogio)
+	defer fechar()
+
+	intervalo := time.Duration(cfg.Lembretes.Intervalo)
+	if intervalo <= 0 {
+		intervalo = time.Minute
+	}
+	repeticao := time.Duration(cfg.Lembretes.Repeticao)
+
+	fmt.Printf("Monitorando marcações a cada %s (Ctrl+C para encerrar)\n", intervalo)
+	ultimoAviso := make(map[string]time.Time)
+	for {
+		agora := relogio.Agora()
+		alertas, err := alertasAtivos(regras, diario, adiamentos, agora)
+		if err != nil {
+			fmt.Printf("\n⚠️  Aviso: Não foi possível verificar as marcações: %v\n", err)
+		}
+
+		ativos := make(map[string]bool)
+		for _, alerta := range alertas {
+			ativos[alerta.Tipo] = true
+			if ultimo, ok := ultimoAviso[alerta.Tipo]; ok && agora.Sub(ultimo) < repeticao {
+				continue
+			}
+			if err := lembretes.Notificar(notificadores, alerta); err != nil {
+				fmt.Printf("\n⚠️  Aviso: Falha ao enviar alerta: %v\n", err)
+			}
+			ultimoAviso[alerta.Tipo] = agora
+		}
+		// Um alerta resolvido volta a ser avisado imediatamente se reaparecer
+		for tipo := range ultimoAviso {
+			if !ativos[tipo] {
+				delete(ultimoAviso, tipo)
+			}
+		}
+
+		if err := common.Dormir(ctx, relogio, intervalo); err != nil {
+			fmt.Println("\nMonitoramento encerrado")
+			return 0
+		}
+	}
+}
+
+// notificadoresLembrete monta os canais habilitados; o Slack é iniciado apenas se configurado,
+// e uma falha ao iniciá-lo apenas o remove da lista
+func notificadoresLembrete(ctx context.Context, cfg *config.Config, relogio common.Relogio) ([]lembretes.Notificador, func()) {
+	var notificadores []lembretes.Notificador
+	fechar := func() {}
+
+	canais := cfg.Lembretes.Canais
+	if canais.Sino {
+		notificadores = append(notificadores, lembretes.Sino{Saida: os.Stdout})
+	}
+	if canais.Desktop {
+		notificadores = append(notificadores, lembretes.Desktop{})
+	}
+	if canais.Slack {
+		seletores, err := selectors.Carregar(config.Diretorio())
+		if err != nil {
+			fmt.Printf("\n⚠️  Aviso: Alertas pelo Slack desabilitados: %v\n", err)
+			return notificadores, fechar
+		}
+
+		armazenamento, err := abrirArmazenamento(cfg.Credenciais, relogio)
+		if err != nil {
+			fmt.Printf("\n⚠️  Aviso: Alertas pelo Slack desabilitados: %v\n", err)
+			return notificadores, fechar
+		}
+
+		navegador := novoNavegador(cfg)
+		slackModule, err := slack.NewModulo(ctx, slack.Configuracao{
+			DiretorioConfig: config.Diretorio(),
+			ModoSilencioso:  true,
+			Navegador:       navegador,
+			Retentativas:    config.Politicas(cfg.Slack.Retentativas),
+			Seletores:       &seletores.Slack,
+			Relogio:         relogio,
+			Segredos:        armazenamento,
+			Backend:         cfg.Slack.Backend,
+			URLBase:         cfg.Slack.URLBase,
+			Destinos:        cfg.Slack.Destinos,
+		})
+		if err != nil {
+			navegador.Close()
+			fmt.Printf("\n⚠️  Aviso: Alertas pelo Slack desabilitados: %v\n", err)
+			return notificadores, fechar
+		}
+
+		notificadores = append(notificadores, lembretes.Mensagem(func(texto string) error {
+			_, err := slackModule.EnviarMensagem(slack.TipoLembrete, texto)
+			return err
+		}))
+		fechar = func() {
+			slackModule.Close()
+			navegador.Close()
+		}
+	}
+
+	if len(notificadores) == 0 {
+		fmt.Println("\n⚠️  Aviso: Nenhum canal de lembrete habilitado (lembretes.canais); os alertas serão exibidos no terminal")
+		notificadores = append(notificadores, lembretes.Sino{Saida: os.Stdout})
+	}
+	return notificadores, fechar
+}
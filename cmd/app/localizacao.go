@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/deteccao"
+)
+
+// variavelLocalizacao força a localização, ignorando a detecção pela rede
+const variavelLocalizacao = "LOCALIZACAO_PONTO"
+
+func comandoLocalizacao(args []string) int {
+	if len(args) == 0 || args[0] != "detectar" {
+		exibirAjuda()
+		return 2
+	}
+
+	cfg, err := config.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar configuração:", err)
+		return 1
+	}
+
+	ambiente, resultado, err := detectarLocalizacao(cfg.Deteccao)
+	if err != nil {
+		fmt.Println("Erro ao detectar localização:", err)
+		return 1
+	}
+
+	fmt.Println("Ambiente de rede:")
+	fmt.Printf("  gateway:    %s %s\n", ambiente.GatewayIP, ambiente.GatewayMAC)
+	fmt.Printf("  endereços:  %s\n", juntarIPs(ambiente))
+	fmt.Printf("  wi-fi:      %s\n", strings.Join(ambiente.SSIDs, ", "))
+	fmt.Printf("  vpn:        %s\n", strings.Join(ambiente.InterfacesVPN, ", "))
+	for _, aviso := range ambiente.Avisos {
+		fmt.Printf("\n⚠️  Aviso: %s\n", aviso)
+	}
+	fmt.Println()
+	fmt.Println("Localização:", resultado.Relatorio())
+	if !cfg.Deteccao.Habilitada {
+		fmt.Println("\n⚠️  Aviso: A detecção está desabilitada (deteccao.habilitada)")
+	}
+	return 0
+}
+
+func juntarIPs(ambiente deteccao.Ambiente) string {
+	var ips []string
+	for _, ip := range ambiente.IPs {
+		ips = append(ips, ip.String())
+	}
+	return strings.Join(ips, ", ")
+}
+
+// detectarLocalizacao coleta o ambiente de rede e aplica as regras configuradas
+func detectarLocalizacao(cfg config.Deteccao) (deteccao.Ambiente, deteccao.Resultado, error) {
+	if err := deteccao.Validar(cfg.Regras); err != nil {
+		return deteccao.Ambiente{}, deteccao.Resultado{}, err
+	}
+
+	ambiente, err := deteccao.ColetarAmbiente("")
+	if err != nil {
+		return ambiente, deteccao.Resultado{}, err
+	}
+	return ambiente, deteccao.Detectar(cfg.Regras, ambiente), nil
+}
+
+// sugerirLocalizacao retorna a localização definida em LOCALIZACAO_PONTO ou, com a detecção
+// habilitada, a detectada pela rede, e se a escolha deve ser confirmada pelo usuário
+func sugerirLocalizacao(cfg config.Deteccao) (string, bool) {
+	if nome := strings.TrimSpace(os.Getenv(variavelLocalizacao)); nome != "" {
+		fmt.Printf("\n📍 Localização definida em %s: %s\n", variavelLocalizacao, nome)
+		return nome, false
+	}
+
+	if !cfg.Habilitada {
+		return "", false
+	}
+
+	_, resultado, err := detectarLocalizacao(cfg)
+	if err != nil {
+		fmt.Printf("\n⚠️  Aviso: Falha ao detectar localização: %v\n", err)
+		return "", false
+	}
+
+	fmt.Printf("\n📡 Localização detectada: %s\n", resultado.Relatorio())
+	if resultado.Localizacao == "" {
+		return "", false
+	}
+	if resultado.Confianca < cfg.ConfiancaMinima {
+		fmt.Printf("Confiança abaixo do mínimo configurado (%.0f%%); escolha a localização manualmente\n", cfg.ConfiancaMinima*100)
+		return "", false
+	}
+	return resultado.Localizacao, !cfg.Automatica
+}
@@ -6,89 +6,262 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/auth"
 	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/journal"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/sessao"
 	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slack"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/snapshot"
 	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/ui"
 	"github.com/manifoldco/promptui"
 )
 
-const timeoutGlobal = 10 * time.Minute
+// maxRecusasLogin é o número de logins recusados aceitos antes de encerrar
+const maxRecusasLogin = 3
 
 func main() {
+	args, err := extrairOpcoes(os.Args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	if len(args) > 0 {
+		os.Exit(executarComando(args))
+	}
+
 	fmt.Println("\nBatedor de Ponto - Oliveira Trust")
 	fmt.Println("==================================")
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeoutGlobal)
+	// Sem prazo global: cada módulo limita as próprias chamadas e a sessão do Softtrade é
+	// mantida aberta pelo gerenciador de sessão enquanto o menu estiver em uso
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	// Inicializa o módulo de UI
 	uiModule := ui.NewModule()
 
+	// Carrega a configuração do usuário
+	cfg, err := config.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar configuração:", err)
+		os.Exit(1)
+	}
+
+	seletores, err := selectors.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar seletores:", err)
+		os.Exit(1)
+	}
+
+	politicaIntervalo, err := clockin.ParsePoliticaIntervalo(cfg.Ponto.IntervaloOpcional.Politica)
+	if err != nil {
+		fmt.Println("Erro na configuração do ponto:", err)
+		os.Exit(1)
+	}
+
+	catalogo, err := clockin.NovoCatalogo(cfg.Ponto.Localizacoes)
+	if err != nil {
+		fmt.Println("Erro na configuração do ponto:", err)
+		os.Exit(1)
+	}
+
+	// Relógio compartilhado pelos módulos; testes e simulações podem substituí-lo
+	relogio := common.RelogioSistema
+
+	// Cenário opcional que torna os mocks reproduzíveis
+	cenarioAuthMock, cenarioPontoMock, err := carregarCenariosMock(cfg.Ponto.CenarioMock)
+	if err != nil {
+		fmt.Println("Erro na configuração do ponto:", err)
+		os.Exit(1)
+	}
+
+	// Armazenamento de credenciais e cookies (arquivos, cofre ou keyring)
+	armazenamento, err := abrirArmazenamento(cfg.Credenciais, relogio)
+	if err != nil {
+		fmt.Println("Erro ao abrir armazenamento de credenciais:", err)
+		os.Exit(1)
+	}
+
+	// Diário com o histórico das operações realizadas
+	diario := journal.Abrir(config.Diretorio(), relogio)
+
+	// Gravação opcional das páginas lidas, para reprodução com "batponto snapshots reproduzir"
+	var gravador *snapshot.Gravador
+	if cfg.Snapshots.Gravar {
+		ocultacao, err := snapshot.NovaOcultacao(cfg.Snapshots.Ocultar, cfg.Snapshots.PadroesOcultos)
+		if err != nil {
+			fmt.Println("Erro na configuração de snapshots:", err)
+			os.Exit(1)
+		}
+		gravador = snapshot.NovoGravador(cfg.DiretorioSnapshots(), ocultacao)
+	}
+
 	// Define se usará mock para desenvolvimento
 	const mocarPonto = false // Altere para false para usar o sistema real
 
+	// Fontes consultadas antes de pedir as credenciais (ambiente, comando, stdin, armazenamento)
+	fontes, err := fontesCredenciais(cfg.Credenciais, armazenamento)
+	if err != nil {
+		fmt.Println("Erro na configuração de credenciais:", err)
+		os.Exit(1)
+	}
+
 	// Carrega credenciais
 	loading := uiModule.ShowSpinner("Carregando credenciais")
 	loading.Start()
-	creds, err := auth.CarregarCredenciais()
+	creds, fonte, err := auth.CarregarCredenciais(fontes...)
 	credenciaisNaoSalvas := false
 	if err != nil {
-		if err == auth.ErrCredenciaisNaoEncontradas {
-			loading.Stop() // Para o spinner antes de solicitar as credenciais
-			credenciaisNaoSalvas = true
-			creds, err = auth.SolicitarCredenciais()
-			if err != nil {
-				fmt.Println("Erro ao obter credenciais:", err)
-				os.Exit(1)
-			}
-		} else {
-			loading.Error(err)
-			fmt.Println("Erro ao carregar credenciais:", err)
+		loading.Stop() // Para o spinner antes de solicitar as credenciais
+		credenciaisNaoSalvas = true
+		creds, err = auth.SolicitarCredenciais()
+		if err != nil {
+			fmt.Println("Erro ao obter credenciais:", err)
 			os.Exit(1)
 		}
 	} else {
 		loading.Success()
 	}
 
-	// Inicializa o módulo de autenticação
-	loading = uiModule.ShowSpinner("Inicializando autenticação")
-	loading.Start()
-	authModule, err := auth.NewModule(auth.Config{
-		Headless: true,
-		UseMock:  mocarPonto,
-	})
+	// Uma única instância do Chromium atende o Softtrade e o Slack
+	navegador := novoNavegador(cfg)
+	defer navegador.Close()
+
+	configPonto := clockin.Config{
+		UseMock:     mocarPonto,
+		CenarioMock: cenarioPontoMock,
+		Provedor:    cfg.Ponto.Provedor,
+		URLBase:     cfg.Ponto.URLBase,
+		Intervalo: clockin.ConfigIntervalo{
+			Politica:       politicaIntervalo,
+			MinutosMinimos: cfg.Ponto.IntervaloOpcional.MinutosMinimos,
+			Confirmador:    uiModule,
+		},
+		Retentativas: config.Politicas(cfg.Ponto.Retentativas),
+		Seletores:    &seletores.Softtrade,
+		Gravador:     gravador,
+		Catalogo:     catalogo,
+		Relogio:      relogio,
+	}
+
+	provedor, err := clockin.ObterProvedor(configPonto.NomeProvedor())
 	if err != nil {
-		loading.Error(err)
-		fmt.Println("Erro ao iniciar módulo de autenticação:", err)
+		fmt.Println("Erro na configuração do ponto:", err)
 		os.Exit(1)
 	}
-	loading.Success()
+	if !provedor.Capacidades.Modal {
+		if politicaIntervalo != clockin.IntervaloPerguntar {
+			fmt.Printf("\n⚠️  Aviso: O provedor %s não exibe o modal \"Intervalo Opcional\"; ponto.intervalo_opcional será ignorado\n", provedor.Nome)
+		}
+		configPonto.Intervalo = clockin.ConfigIntervalo{}
+	}
 
-	// Loop de tentativas de login
+	// Provedores baseados no navegador dependem do login feito pelo módulo auth;
+	// os demais são criados antes e fazem o próprio login, se necessário
+	var authModule auth.Module
+	var pontoModule clockin.Module
+	var login func(auth.Credentials) error
+	if provedor.UsaNavegador {
+		// Inicializa o módulo de autenticação
+		loading = uiModule.ShowSpinner("Inicializando autenticação")
+		loading.Start()
+		authModule, err = auth.NewModule(auth.Config{
+			Headless:      !depuracaoNavegador.ativa,
+			UseMock:       mocarPonto,
+			MockScenario:  cenarioAuthMock,
+			Navegador:     navegador,
+			URLBase:       cfg.Ponto.URLBase,
+			Seletores:     &seletores.Softtrade,
+			Relogio:       relogio,
+			Armazenamento: armazenamento,
+			SegredoTOTP:   segredoTOTP(armazenamento),
+			// O spinner do login é pausado enquanto o código é digitado
+			SolicitanteCodigo: auth.OTPPrompterFunc(func() (string, error) {
+				loading.Stop()
+				defer loading.Start()
+				return uiModule.SolicitarCodigoVerificacao()
+			}),
+		})
+		if err != nil {
+			loading.Error(err)
+			fmt.Println("Erro ao iniciar módulo de autenticação:", err)
+			navegador.Close()
+			os.Exit(1)
+		}
+		loading.Success()
+		login = authModule.Login
+	} else {
+		loading = uiModule.ShowSpinner("Inicializando módulo de ponto")
+		loading.Start()
+		pontoModule, err = provedor.Criar(ctx, configPonto)
+		if err != nil {
+			loading.Error(err)
+			fmt.Println("Erro ao iniciar módulo de ponto:", err)
+			navegador.Close()
+			os.Exit(1)
+		}
+		loading.Success()
+
+		login = func(auth.Credentials) error { return nil }
+		if autenticavel, ok := pontoModule.(clockin.Autenticavel); ok {
+			login = func(creds auth.Credentials) error {
+				return autenticavel.Login(creds.Username, creds.Password)
+			}
+		}
+	}
+
+	// Loop de tentativas de login; credenciais recusadas repetidas vezes encerram o programa
+	// para não levar ao bloqueio do usuário no Softtrade
 	var loginSucesso bool
+	recusas := 0
 	for !loginSucesso {
 		// Faz login
 		loading = uiModule.ShowSpinner("Realizando login")
 		loading.Start()
-		err = authModule.Login(creds)
+		err = login(creds)
 		if err != nil {
 			loading.Error(err)
-			var loginErr *auth.LoginError
-			if errors.As(err, &loginErr) && loginErr.Type == "auth" {
+			switch tipoErroLogin(err) {
+			case "auth":
+				recusas++
+				if recusas >= maxRecusasLogin {
+					fmt.Printf("\n✖ Login recusado %d vezes; encerrando para evitar o bloqueio do usuário\n", recusas)
+					fmt.Println("Se a senha mudou, atualize a salva com \"batponto credenciais update\"")
+					navegador.Close()
+					os.Exit(1)
+				}
 				fmt.Println("\nPor favor, tente novamente.")
 				creds, err = auth.SolicitarCredenciais()
 				if err != nil {
 					fmt.Println("Erro ao obter credenciais:", err)
+					navegador.Close()
 					os.Exit(1)
 				}
 				credenciaisNaoSalvas = true
+				fonte = nil
 				continue
+			case "password_expired", "password_change":
+				creds, err = trocarSenha(authModule, creds, fonte)
+				if err != nil {
+					fmt.Println("Erro ao trocar a senha:", err)
+					navegador.Close()
+					os.Exit(1)
+				}
+				continue
+			case "account_locked":
+				fmt.Println("\n✖", err)
+				navegador.Close()
+				os.Exit(1)
 			}
 			fmt.Println("Erro ao fazer login:", err)
+			navegador.Close()
 			os.Exit(1)
 		}
 		loading.Success()
@@ -97,25 +270,49 @@ func main() {
 
 	// Se as credenciais não estavam salvas e o login foi bem sucedido, oferece salvar
 	if credenciaisNaoSalvas {
-		if err := auth.SalvarCredenciais(creds); err != nil {
+		if err := auth.SalvarCredenciais(creds, armazenamento); err != nil {
 			fmt.Printf("\n⚠️  Aviso: não foi possível salvar as credenciais: %v\n", err)
 		}
 	}
 
-	// Inicializa o módulo de ponto
-	loading = uiModule.ShowSpinner("Inicializando módulo de ponto")
-	loading.Start()
-	pontoModule := clockin.NewModule(authModule.GetContext(), clockin.Config{
-		UseMock: mocarPonto,
-	})
-	loading.Success()
+	// Inicializa o módulo de ponto sobre a sessão autenticada do navegador
+	if pontoModule == nil {
+		loading = uiModule.ShowSpinner("Inicializando módulo de ponto")
+		loading.Start()
+		pontoModule, err = provedor.Criar(authModule.GetContext(), configPonto)
+		if err != nil {
+			loading.Error(err)
+			fmt.Println("Erro ao iniciar módulo de ponto:", err)
+			navegador.Close()
+			os.Exit(1)
+		}
+
+		// Mantém a sessão aberta e refaz o login quando o Softtrade a encerra
+		gerenciadorSessao := sessao.Novo(authModule, pontoModule, creds, sessao.Config{
+			Intervalo: time.Duration(cfg.Ponto.VerificacaoSessao),
+			Seletores: &seletores.Softtrade,
+			Relogio:   relogio,
+		})
+		gerenciadorSessao.Iniciar()
+		pontoModule = gerenciadorSessao
+		loading.Success()
+	}
 
 	// Tenta inicializar o módulo do Slack
 	loading = uiModule.ShowSpinner("Configurando Slack")
 	loading.Start()
 	slackModule, err := slack.NewModulo(ctx, slack.Configuracao{
-		DiretorioConfig: os.Getenv("HOME") + "/.batedorponto",
+		DiretorioConfig: config.Diretorio(),
 		ModoSilencioso:  true,
+		Navegador:       navegador,
+		Retentativas:    config.Politicas(cfg.Slack.Retentativas),
+		Seletores:       &seletores.Slack,
+		Gravador:        gravador,
+		Relogio:         relogio,
+		Segredos:        armazenamento,
+		Backend:         cfg.Slack.Backend,
+		URLBase:         cfg.Slack.URLBase,
+		Destinos:        cfg.Slack.Destinos,
 	})
 	if err != nil {
 		loading.Error(err)
@@ -131,10 +328,14 @@ func main() {
 	go func() {
 		<-sigChan
 		fmt.Print("\nEncerrando programa...")
-		authModule.Close()
+		pontoModule.Close()
+		if authModule != nil {
+			authModule.Close()
+		}
 		if slackModule != nil {
 			slackModule.Close()
 		}
+		navegador.Close()
 		fmt.Println(" OK")
 		os.Exit(0)
 	}()
@@ -155,10 +356,12 @@ func main() {
 		// Se o usuário optar por marcar ponto
 		marcarPonto := opcao == ui.OpSomentePonto || opcao == ui.OpPontoCompletoSlack
 		if marcarPonto {
-			// Gerencia localização
-			if _, err := gerenciarLocalizacao(pontoModule, uiModule); err != nil {
-				fmt.Println("Erro ao gerenciar localização:", err)
-				continue
+			// Gerencia localização, quando o sistema de ponto exige
+			if provedor.Capacidades.Localizacoes {
+				if _, err := gerenciarLocalizacao(pontoModule, uiModule, diario, relogio, catalogo, cfg.Deteccao); err != nil {
+					fmt.Println("Erro ao gerenciar localização:", err)
+					continue
+				}
 			}
 
 			// Obtém operações disponíveis
@@ -179,6 +382,16 @@ func main() {
 				continue
 			}
 
+			// Evita marcações duplicadas ou fora da sequência do dia
+			permitida, err := validarMarcacao(cfg.Ponto.Jornada, pontoModule, provedor, diario, relogio, operacao)
+			if err != nil {
+				fmt.Println("Erro na validação da marcação:", err)
+				continue
+			}
+			if !permitida {
+				continue
+			}
+
 			confirmado, err := uiModule.ExibirConfirmacao(operacao)
 			if err != nil {
 				fmt.Println("Erro na confirmação:", err)
@@ -192,12 +405,23 @@ func main() {
 
 			loading = uiModule.ShowSpinner("Marcando ponto")
 			loading.Start()
-			if err := pontoModule.ExecutarOperacao(operacao); err != nil {
+			resultado, err := pontoModule.ExecutarOperacao(operacao)
+			registroPonto := journal.Registro{Evento: journal.EventoPonto, Operacao: operacao.String()}
+			if err != nil {
 				loading.Error(err)
+				registrarDiario(diario, registroPonto, err)
 				fmt.Println("Erro ao marcar ponto:", err)
 				continue
 			}
 			loading.Success()
+			registroPonto.Tentativas = resultado.Tentativas
+			registrarDiario(diario, registroPonto, nil)
+			if resultado.Tentativas > 1 {
+				fmt.Printf("  Marcação registrada após %d tentativas\n", resultado.Tentativas)
+			}
+			if provedor.Capacidades.Modal {
+				exibirModalIntervalo(resultado)
+			}
 
 			// Atualiza o status do Slack se necessário
 			if opcao == ui.OpPontoCompletoSlack {
@@ -212,13 +436,16 @@ func main() {
 				}
 				loading.Success()
 
-				localizacaoAtual, err := pontoModule.ObterLocalizacaoAtual()
-				if err != nil {
-					fmt.Println("Erro ao obter localização atual:", err)
-					continue
+				var localizacaoAtual string
+				if provedor.Capacidades.Localizacoes {
+					localizacaoAtual, err = pontoModule.ObterLocalizacaoAtual()
+					if err != nil {
+						fmt.Println("Erro ao obter localização atual:", err)
+						continue
+					}
 				}
 
-				novoStatus := slack.DeterminarStatus(operacao, localizacaoAtual)
+				novoStatus := slack.DeterminarStatus(operacao, localizacaoAtual, catalogo)
 				confirmado, err := slack.ConfirmarAlteracaoStatus(statusAtual, novoStatus)
 				if err != nil {
 					fmt.Println("Erro na confirmação do status:", err)
@@ -228,7 +455,9 @@ func main() {
 				if confirmado {
 					loading = uiModule.ShowSpinner("Atualizando status no Slack")
 					loading.Start()
-					if err := slackModule.DefinirStatus(novoStatus); err != nil {
+					err := slackModule.DefinirStatus(novoStatus)
+					registrarDiario(diario, journal.Registro{Evento: journal.EventoStatusSlack, Detalhe: novoStatus.Mensagem}, err)
+					if err != nil {
 						loading.Error(err)
 						fmt.Println("Erro ao atualizar status:", err)
 						continue
@@ -249,14 +478,7 @@ func main() {
 					continue
 				}
 
-				loading = uiModule.ShowSpinner("Enviando mensagem no Slack")
-				loading.Start()
-				if err := slackModule.EnviarMensagem(mensagem); err != nil {
-					loading.Error(err)
-					fmt.Println("Erro ao enviar mensagem:", err)
-					continue
-				}
-				loading.Success()
+				enviarMensagemSlack(slackModule, uiModule, diario, tipoMensagem, mensagem)
 			}
 		}
 
@@ -313,7 +535,9 @@ func main() {
 
 				loading = uiModule.ShowSpinner("Atualizando status")
 				loading.Start()
-				if err := slackModule.DefinirStatus(novoStatus); err != nil {
+				err = slackModule.DefinirStatus(novoStatus)
+				registrarDiario(diario, journal.Registro{Evento: journal.EventoStatusSlack, Detalhe: novoStatus.Mensagem}, err)
+				if err != nil {
 					loading.Error(err)
 					fmt.Println("Erro ao atualizar status:", err)
 					continue
@@ -334,7 +558,9 @@ func main() {
 
 				loading = uiModule.ShowSpinner("Limpando status")
 				loading.Start()
-				if err := slackModule.LimparStatus(); err != nil {
+				err = slackModule.LimparStatus()
+				registrarDiario(diario, journal.Registro{Evento: journal.EventoStatusSlack, Detalhe: "limpar"}, err)
+				if err != nil {
 					loading.Error(err)
 					fmt.Println("Erro ao limpar status:", err)
 					continue
@@ -362,37 +588,108 @@ func main() {
 				continue
 			}
 
-			loading = uiModule.ShowSpinner("Enviando mensagem no Slack")
-			loading.Start()
-			if err := slackModule.EnviarMensagem(mensagem); err != nil {
-				loading.Error(err)
-				fmt.Println("Erro ao enviar mensagem:", err)
-				continue
-			}
-			loading.Success()
+			enviarMensagemSlack(slackModule, uiModule, diario, tipoMensagem, mensagem)
 		}
 
 		fmt.Println()
 	}
 
 	// Cleanup final
-	authModule.Close()
+	pontoModule.Close()
+	if authModule != nil {
+		authModule.Close()
+	}
 	if slackModule != nil {
 		slackModule.Close()
 	}
 	fmt.Println("Programa finalizado")
 }
 
+// tipoErroLogin retorna o tipo do erro de login do módulo auth ou do provedor de ponto
+func tipoErroLogin(err error) string {
+	var loginErr *auth.LoginError
+	if errors.As(err, &loginErr) {
+		return loginErr.Type
+	}
+	var erroPonto *clockin.ErroPonto
+	if errors.As(err, &erroPonto) {
+		return erroPonto.Tipo
+	}
+	return ""
+}
+
+// Função auxiliar para registrar uma operação no diário sem interromper o fluxo
+func registrarDiario(diario *journal.Diario, registro journal.Registro, err error) {
+	registro.Sucesso = err == nil
+	if err != nil {
+		registro.Erro = err.Error()
+		var erroTentativas *common.ErroTentativas
+		if errors.As(err, &erroTentativas) {
+			registro.Tentativas = erroTentativas.Tentativas
+		}
+	}
+
+	if errDiario := diario.Registrar(registro); errDiario != nil {
+		fmt.Printf("\n⚠️  Aviso: não foi possível registrar no diário: %v\n", errDiario)
+	}
+}
+
+// enviarMensagemSlack envia a mensagem aos destinos configurados para o tipo, registrando
+// e exibindo o resultado de cada destino
+func enviarMensagemSlack(slackModule slack.OperacoesSlack, uiModule ui.Module, diario *journal.Diario, tipoMensagem, mensagem string) {
+	loading := uiModule.ShowSpinner("Enviando mensagem no Slack")
+	loading.Start()
+	resultados, err := slackModule.EnviarMensagem(tipoMensagem, mensagem)
+	if len(resultados) == 0 {
+		registrarDiario(diario, journal.Registro{Evento: journal.EventoMensagemSlack, Detalhe: mensagem}, err)
+	}
+	for _, resultado := range resultados {
+		registrarDiario(diario, journal.Registro{
+			Evento:  journal.EventoMensagemSlack,
+			Detalhe: fmt.Sprintf("%s → %s", mensagem, resultado.Destino),
+		}, resultado.Erro)
+	}
+
+	if err != nil {
+		loading.Error(err)
+		if len(resultados) == 0 {
+			fmt.Println("Erro ao enviar mensagem:", err)
+		}
+	} else {
+		loading.Success()
+	}
+	for _, resultado := range resultados {
+		if resultado.Erro != nil {
+			fmt.Printf("  ✖ %s: %v\n", resultado.Destino, resultado.Erro)
+		} else {
+			fmt.Printf("  ✓ %s\n", resultado.Destino)
+		}
+	}
+}
+
+// Função auxiliar para exibir a resposta dada ao modal "Intervalo Opcional"
+func exibirModalIntervalo(resultado *clockin.ResultadoOperacao) {
+	if resultado == nil || resultado.Modal == nil {
+		return
+	}
+
+	resposta := "Não"
+	if resultado.Modal.Resposta {
+		resposta = "Sim"
+	}
+	fmt.Printf("\n⏰ Intervalo Opcional: %s (política: %s)\n", resposta, resultado.Modal.Politica)
+}
+
 // Função auxiliar para determinar o tipo de mensagem com base nas operações disponíveis
 func determinarTipoMensagem(operacoes []clockin.TipoOperacao) string {
 	for _, op := range operacoes {
 		switch op {
 		case clockin.Entrada:
-			return "entrada"
+			return slack.TipoEntrada
 		case clockin.Almoco:
-			return "refeicao"
+			return slack.TipoRefeicao
 		case clockin.Saida:
-			return "saida"
+			return slack.TipoSaida
 		}
 	}
 	return ""
@@ -412,18 +709,18 @@ func selecionarTipoMensagem() (string, error) {
 
 	switch resultado {
 	case "Entrada":
-		return "entrada", nil
+		return slack.TipoEntrada, nil
 	case "Almoço":
-		return "refeicao", nil
+		return slack.TipoRefeicao, nil
 	case "Saída":
-		return "saida", nil
+		return slack.TipoSaida, nil
 	default:
 		return "", fmt.Errorf("tipo de mensagem inválido")
 	}
 }
 
 // Função auxiliar para gerenciar localização
-func gerenciarLocalizacao(pontoModule clockin.Module, uiModule ui.Module) (bool, error) {
+func gerenciarLocalizacao(pontoModule clockin.Module, uiModule ui.Module, diario *journal.Diario, relogio common.Relogio, catalogo *clockin.Catalogo, cfgDeteccao config.Deteccao) (bool, error) {
 	// Primeiro verifica se há operações disponíveis
 	operacoes, _ := pontoModule.ObterOperacoesDisponiveis()
 	forcarSelecao := len(operacoes) == 0
@@ -435,9 +732,32 @@ func gerenciarLocalizacao(pontoModule clockin.Module, uiModule ui.Module) (bool,
 
 	fmt.Printf("\nLocalização atual: %s\n", localizacaoAtual)
 
-	if forcarSelecao {
+	// Localização definida pelo usuário ou detectada pela rede; se recusada, abre o menu
+	sugerida, confirmar := sugerirLocalizacao(cfgDeteccao)
+	if sugerida != "" {
+		if !forcarSelecao && catalogo.Equivalentes(sugerida, localizacaoAtual) {
+			fmt.Printf("\nMantendo localização: %s\n", localizacaoAtual)
+			return false, nil
+		}
+		if confirmar {
+			prompt := promptui.Prompt{
+				Label:     fmt.Sprintf("Selecionar a localização detectada (%s)", sugerida),
+				IsConfirm: true,
+				Default:   "y",
+			}
+			if _, err := prompt.Run(); err != nil {
+				if err != promptui.ErrAbort {
+					return false, fmt.Errorf("erro na confirmação: %w", err)
+				}
+				sugerida = ""
+				forcarSelecao = true
+			}
+		}
+	}
+
+	if forcarSelecao && sugerida == "" {
 		fmt.Println("⚠️  É necessário selecionar uma localização para habilitar as operações")
-	} else {
+	} else if sugerida == "" {
 		prompt := promptui.Prompt{
 			Label:     "Deseja alterar a localização",
 			IsConfirm: true,
@@ -471,19 +791,36 @@ func gerenciarLocalizacao(pontoModule clockin.Module, uiModule ui.Module) (bool,
 		return false, fmt.Errorf("nenhuma localização disponível")
 	}
 
-	localizacaoSelecionada, err := uiModule.ExibirMenuLocalizacao(localizacoes)
-	if err != nil {
-		return false, fmt.Errorf("erro na seleção de localização: %w", err)
+	if desconhecidas := catalogo.Desconhecidas(localizacoes); len(desconhecidas) > 0 {
+		fmt.Printf("\n⚠️  Aviso: Localizações fora do catálogo (ponto.localizacoes): %s\n", strings.Join(desconhecidas, ", "))
+		for _, rotulo := range desconhecidas {
+			if candidatas := catalogo.Candidatas(rotulo); len(candidatas) > 0 {
+				fmt.Printf("   %q é parecida com %s; adicione-a aos apelidos da correta\n", rotulo, strings.Join(candidatas, " e "))
+			}
+		}
+	}
+
+	localizacaoSelecionada, encontrada := catalogo.Encontrar(localizacoes, sugerida)
+	if sugerida != "" && !encontrada {
+		fmt.Printf("\n⚠️  Aviso: A localização %q não está entre as disponíveis\n", sugerida)
+	}
+	if !encontrada {
+		localizacaoSelecionada, err = uiModule.ExibirMenuLocalizacao(localizacoes)
+		if err != nil {
+			return false, fmt.Errorf("erro na seleção de localização: %w", err)
+		}
 	}
 
-	if !forcarSelecao && localizacaoSelecionada.Nome == localizacaoAtual {
+	if !forcarSelecao && catalogo.Equivalentes(localizacaoSelecionada.Nome, localizacaoAtual) {
 		fmt.Printf("\nMantendo localização: %s\n", localizacaoAtual)
 		return false, nil
 	}
 
 	loading = uiModule.ShowSpinner(fmt.Sprintf("Alterando localização para: %s", localizacaoSelecionada.Nome))
 	loading.Start()
-	if err := pontoModule.SelecionarLocalizacao(localizacaoSelecionada); err != nil {
+	err = pontoModule.SelecionarLocalizacao(localizacaoSelecionada)
+	registrarDiario(diario, journal.Registro{Evento: journal.EventoLocalizacao, Detalhe: localizacaoSelecionada.Nome}, err)
+	if err != nil {
 		loading.Error(err)
 		return false, fmt.Errorf("erro ao selecionar localização: %w", err)
 	}
@@ -492,7 +829,7 @@ func gerenciarLocalizacao(pontoModule clockin.Module, uiModule ui.Module) (bool,
 	// Aguarda um momento e verifica se as operações estão disponíveis
 	loading = uiModule.ShowSpinner("Aguardando operações serem habilitadas")
 	loading.Start()
-	time.Sleep(2 * time.Second) // Aguarda 2 segundos para a interface atualizar
+	common.Dormir(context.Background(), relogio, 2*time.Second) // Aguarda 2 segundos para a interface atualizar
 	operacoes, err = pontoModule.ObterOperacoesDisponiveis()
 	if err != nil || len(operacoes) == 0 {
 		loading.Error(fmt.Errorf("operações não foram habilitadas após selecionar localização"))
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/auth"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+)
+
+// carregarCenariosMock lê o arquivo de cenário configurado e o converte para os mocks
+// de autenticação e de ponto; sem arquivo, os mocks usam o comportamento padrão
+func carregarCenariosMock(caminho string) (auth.MockScenario, clockin.CenarioMock, error) {
+	if caminho == "" {
+		return auth.MockScenario{}, clockin.CenarioMock{}, nil
+	}
+
+	cenario, err := config.CarregarCenarioMock(caminho)
+	if err != nil {
+		return auth.MockScenario{}, clockin.CenarioMock{}, err
+	}
+
+	var operacoes [][]clockin.TipoOperacao
+	for _, passo := range cenario.Operacoes {
+		disponiveis := []clockin.TipoOperacao{}
+		for _, nome := range passo {
+			op, err := operacaoPorNome(nome)
+			if err != nil {
+				return auth.MockScenario{}, clockin.CenarioMock{}, fmt.Errorf("cenário do mock: %w", err)
+			}
+			disponiveis = append(disponiveis, op)
+		}
+		operacoes = append(operacoes, disponiveis)
+	}
+
+	return auth.MockScenario{
+		Seed:        cenario.Semente,
+		FailureRate: cenario.ProbabilidadeFalha,
+		Failures:    cenario.Falhas,
+		Username:    cenario.Usuario,
+		Password:    cenario.Senha,
+	}, clockin.CenarioMock{
+		Semente:            cenario.Semente,
+		ProbabilidadeFalha: cenario.ProbabilidadeFalha,
+		Falhas:             cenario.Falhas,
+		Localizacoes:       cenario.Localizacoes,
+		LocalizacaoInicial: cenario.LocalizacaoInicial,
+		Operacoes:          operacoes,
+		Inicio:             cenario.Inicio,
+		AvancoPorMarcacao:  time.Duration(cenario.AvancoPorMarcacao),
+	}, nil
+}
+
+// operacaoPorNome converte os nomes usados no cenário ("entrada", "almoco", "saida")
+func operacaoPorNome(nome string) (clockin.TipoOperacao, error) {
+	switch nome {
+	case "entrada":
+		return clockin.Entrada, nil
+	case "almoco", "refeicao":
+		return clockin.Almoco, nil
+	case "saida":
+		return clockin.Saida, nil
+	default:
+		return 0, fmt.Errorf("operação desconhecida %q", nome)
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/auth"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slack"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/ui"
+)
+
+func comandoSeletores(args []string) int {
+	if len(args) == 0 {
+		exibirAjuda()
+		return 2
+	}
+
+	mapa, err := selectors.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar seletores:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "validar":
+		return validarSeletores(mapa)
+	case "exportar":
+		dados, err := json.MarshalIndent(mapa, "", "  ")
+		if err != nil {
+			fmt.Println("Erro ao exportar seletores:", err)
+			return 1
+		}
+		fmt.Println(string(dados))
+		return 0
+	default:
+		fmt.Printf("Subcomando desconhecido: seletores %s\n\n", args[0])
+		exibirAjuda()
+		return 2
+	}
+}
+
+// validarSeletores verifica todos os seletores do mapa nas páginas reais e retorna
+// código de saída 1 se algum estiver ausente ou inválido
+func validarSeletores(mapa *selectors.Mapa) int {
+	uiModule := ui.NewModule()
+	fmt.Printf("\nValidando mapa de seletores (esquema v%d)\n", mapa.Versao)
+
+	cfg, err := config.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar configuração:", err)
+		return 1
+	}
+
+	armazenamento, err := abrirArmazenamento(cfg.Credenciais, common.RelogioSistema)
+	if err != nil {
+		fmt.Println("Erro ao abrir armazenamento de credenciais:", err)
+		return 1
+	}
+
+	fontes, err := fontesCredenciais(cfg.Credenciais, armazenamento)
+	if err != nil {
+		fmt.Println("Erro na configuração de credenciais:", err)
+		return 1
+	}
+
+	creds, _, err := auth.CarregarCredenciais(fontes...)
+	if err != nil {
+		creds, err = auth.SolicitarCredenciais()
+		if err != nil {
+			fmt.Println("Erro ao obter credenciais:", err)
+			return 1
+		}
+	}
+
+	navegador := novoNavegador(cfg)
+	defer navegador.Close()
+
+	var resultados []selectors.ResultadoValidacao
+	// incompletas conta as páginas que não puderam ser verificadas, como após um login recusado
+	incompletas := 0
+
+	loading := uiModule.ShowSpinner("Verificando seletores do Softtrade")
+	loading.Start()
+	authModule, err := auth.NewModule(auth.Config{
+		Headless:  !depuracaoNavegador.ativa,
+		Navegador: navegador,
+		URLBase:   cfg.Ponto.URLBase,
+		Seletores: &mapa.Softtrade,
+	})
+	if err != nil {
+		loading.Error(err)
+		return 1
+	}
+	softtrade, err := auth.ValidarSeletores(authModule, creds, mapa)
+	authModule.Close()
+	resultados = append(resultados, softtrade...)
+	if err != nil {
+		loading.Error(err)
+		incompletas++
+	} else {
+		loading.Success()
+	}
+
+	loading = uiModule.ShowSpinner("Verificando seletores do Slack")
+	loading.Start()
+	slackResultados, err := slack.ValidarSeletores(context.Background(), slack.Configuracao{
+		DiretorioConfig: config.Diretorio(),
+		ModoSilencioso:  true,
+		Navegador:       navegador,
+		Segredos:        armazenamento,
+	}, mapa)
+	resultados = append(resultados, slackResultados...)
+	if err != nil {
+		loading.Error(err)
+		incompletas++
+	} else {
+		loading.Success()
+	}
+
+	falhas := 0
+	fmt.Println()
+	for _, r := range resultados {
+		icone := "✓"
+		switch {
+		case r.Falhou():
+			icone = "✗"
+			falhas++
+		case r.Situacao == selectors.SituacaoNaoVerificado:
+			icone = "•"
+		}
+
+		linha := fmt.Sprintf("%s %-40s %s", icone, r.Seletor.Nome, r.Situacao)
+		if r.Detalhe != "" {
+			linha += " (" + r.Detalhe + ")"
+		}
+		fmt.Println(linha)
+	}
+
+	if falhas > 0 {
+		fmt.Printf("\n%d seletor(es) com problema. Ajuste-os em %s/%s\n", falhas, config.Diretorio(), selectors.NomeArquivo)
+	}
+	if incompletas > 0 {
+		fmt.Printf("\n%d verificação(ões) interrompida(s); os seletores das páginas seguintes não foram verificados\n", incompletas)
+	}
+	if falhas > 0 || incompletas > 0 {
+		return 1
+	}
+
+	fmt.Println("\nTodos os seletores verificáveis foram encontrados")
+	return 0
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/config"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slack"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/snapshot"
+)
+
+const tempoLimiteReproducao = 20 * time.Second
+
+func comandoSnapshots(args []string) int {
+	if len(args) == 0 {
+		exibirAjuda()
+		return 2
+	}
+
+	cfg, err := config.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar configuração:", err)
+		return 1
+	}
+
+	diretorio := cfg.DiretorioSnapshots()
+	if len(args) > 1 {
+		diretorio = args[1]
+	}
+
+	snapshots, err := snapshot.Listar(diretorio)
+	if err != nil {
+		fmt.Println("Erro ao listar snapshots:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "listar":
+		if len(snapshots) == 0 {
+			fmt.Printf("Nenhum snapshot em %s\n", diretorio)
+			return 0
+		}
+		for _, s := range snapshots {
+			fmt.Printf("  %-28s %s  %s\n", s.Pagina, s.Momento.Format("02/01/2006 15:04:05"), s.Caminho)
+		}
+		return 0
+	case "reproduzir":
+		return reproduzirSnapshots(diretorio, snapshots)
+	default:
+		fmt.Printf("Subcomando desconhecido: snapshots %s\n\n", args[0])
+		exibirAjuda()
+		return 2
+	}
+}
+
+// reproduzirSnapshots carrega cada snapshot no Chromium, executa o extrator correspondente
+// com o mapa de seletores atual e compara com o resultado obtido na gravação
+func reproduzirSnapshots(diretorio string, snapshots []snapshot.Snapshot) int {
+	if len(snapshots) == 0 {
+		fmt.Printf("Nenhum snapshot em %s\n", diretorio)
+		return 0
+	}
+
+	mapa, err := selectors.Carregar(config.Diretorio())
+	if err != nil {
+		fmt.Println("Erro ao carregar seletores:", err)
+		return 1
+	}
+
+	servidor := httptest.NewServer(snapshot.NovoServidor(diretorio))
+	defer servidor.Close()
+
+	navegador := novoNavegador(nil)
+	defer navegador.Close()
+
+	falhas := 0
+	for _, s := range snapshots {
+		obtido, err := reproduzir(navegador, servidor.URL+"/"+s.Caminho, s.Pagina, mapa)
+		if err != nil {
+			falhas++
+			fmt.Printf("  ✗ %s\n      erro: %v\n", s.Caminho, err)
+			continue
+		}
+
+		igual, atual, err := s.Comparar(obtido)
+		if err != nil {
+			falhas++
+			fmt.Printf("  ✗ %s\n      erro: %v\n", s.Caminho, err)
+			continue
+		}
+		if !igual {
+			falhas++
+			fmt.Printf("  ✗ %s\n      esperado: %s\n      obtido:   %s\n", s.Caminho, s.Esperado, atual)
+			continue
+		}
+		fmt.Printf("  ✓ %s\n", s.Caminho)
+	}
+
+	fmt.Printf("\n%d de %d snapshots reproduzidos corretamente\n", len(snapshots)-falhas, len(snapshots))
+	if falhas > 0 {
+		return 1
+	}
+	return 0
+}
+
+// reproduzir abre o snapshot em uma aba isolada e executa o extrator da página
+func reproduzir(navegador *common.GerenciadorNavegador, url, pagina string, mapa *selectors.Mapa) (any, error) {
+	aba, fecharAba, err := navegador.NovaAba()
+	if err != nil {
+		return nil, err
+	}
+	defer fecharAba()
+
+	ctx, cancelar := context.WithTimeout(aba, tempoLimiteReproducao)
+	defer cancelar()
+
+	if err := chromedp.Run(ctx, chromedp.Navigate(url), chromedp.WaitReady("body")); err != nil {
+		return nil, fmt.Errorf("falha ao carregar snapshot: %w", err)
+	}
+
+	switch pagina {
+	case snapshot.PaginaLocalizacaoAtual:
+		return clockin.ExtrairLocalizacaoAtual(ctx, mapa.Softtrade)
+	case snapshot.PaginaLocalizacoes:
+		return clockin.ExtrairLocalizacoes(ctx, mapa.Softtrade)
+	case snapshot.PaginaOperacoes:
+		return clockin.ExtrairOperacoes(ctx, mapa.Softtrade)
+	case snapshot.PaginaStatusSlack:
+		return slack.ExtrairStatus(ctx, mapa.Slack)
+	default:
+		return nil, fmt.Errorf("página desconhecida %q", pagina)
+	}
+}
@@ -0,0 +1,36 @@
+// Comando fakeslack inicia o Slack falso para testar o backend "api" sem acessar o Slack.
+// Aponte slack.url_base do config.json para o endereço exibido.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slackfake"
+)
+
+func main() {
+	cenario := slackfake.CenarioPadrao()
+
+	endereco := flag.String("endereco", "127.0.0.1:8090", "endereço em que o servidor escuta")
+	canais := flag.String("canais", "C010LNL7KS9=batedor,C0000000002=equipe", "conversas do workspace como ID=nome, separadas por vírgula")
+	flag.StringVar(&cenario.Token, "token", cenario.Token, "token de usuário aceito sem cookie")
+	flag.StringVar(&cenario.TokenSessao, "token-sessao", cenario.TokenSessao, "token xoxc exibido na página do workspace")
+	flag.StringVar(&cenario.CookieD, "cookie", cenario.CookieD, "valor do cookie d da sessão web")
+	flag.IntVar(&cenario.TamanhoPagina, "pagina", 0, "itens por página de conversations.list e users.list (0 devolve tudo)")
+	flag.IntVar(&cenario.LimitarTaxa, "limitar", 0, "número de chamadas respondidas com 429 antes de serem atendidas")
+	flag.DurationVar(&cenario.EsperaLimite, "retry-after", 0, "Retry-After das respostas 429")
+	flag.Parse()
+
+	cenario.Canais = nil
+	for _, item := range strings.Split(*canais, ",") {
+		id, nome, _ := strings.Cut(item, "=")
+		cenario.Canais = append(cenario.Canais, slackfake.Canal{ID: id, Nome: nome})
+	}
+
+	fmt.Printf("Slack falso em http://%s (token %q, cookie d %q)\n", *endereco, cenario.Token, cenario.CookieD)
+	log.Fatal(http.ListenAndServe(*endereco, slackfake.Novo(cenario)))
+}
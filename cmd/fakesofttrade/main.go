@@ -0,0 +1,34 @@
+// Comando fakesofttrade inicia o Softtrade falso para testar o batedor sem acessar produção.
+// Aponte ponto.url_base do config.json para o endereço exibido.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/softtradefake"
+)
+
+func main() {
+	cenario := softtradefake.CenarioPadrao()
+
+	endereco := flag.String("endereco", "127.0.0.1:8089", "endereço em que o servidor escuta")
+	localizacoes := flag.String("localizacoes", strings.Join(cenario.Localizacoes, ","), "localizações separadas por vírgula")
+	flag.StringVar(&cenario.Usuario, "usuario", cenario.Usuario, "usuário aceito no login")
+	flag.StringVar(&cenario.Senha, "senha", cenario.Senha, "senha aceita no login")
+	flag.StringVar(&cenario.MensagemErroLogin, "erro-login", cenario.MensagemErroLogin, "mensagem exibida quando o login é recusado")
+	flag.StringVar(&cenario.LocalizacaoInicial, "localizacao", "", "localização já selecionada após o login")
+	flag.BoolVar(&cenario.ModalIntervalo, "modal", cenario.ModalIntervalo, "exibe o modal \"Intervalo Opcional\" na volta da refeição")
+	flag.DurationVar(&cenario.AtrasoAjax, "atraso", 0, "tempo de cada requisição AJAX")
+	flag.IntVar(&cenario.FalhasMarcacao, "falhas", 0, "número de marcações que falham antes de serem aceitas")
+	flag.BoolVar(&cenario.ExpirarView, "expirar", false, "responde ViewExpiredException à primeira requisição AJAX de cada sessão")
+	flag.Parse()
+
+	cenario.Localizacoes = strings.Split(*localizacoes, ",")
+
+	fmt.Printf("Softtrade falso em http://%s (usuário %q, senha %q)\n", *endereco, cenario.Usuario, cenario.Senha)
+	log.Fatal(http.ListenAndServe(*endereco, softtradefake.Novo(cenario)))
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
 )
 
 type LoginError struct {
@@ -27,13 +29,43 @@ func (e *LoginError) Error() string {
 var (
 	ErrEmptyCredentials   = &LoginError{Type: "validation", Message: "usuário e senha são obrigatórios"}
 	ErrInvalidCredentials = &LoginError{Type: "auth", Message: "credenciais inválidas"}
+	ErrPasswordExpired    = &LoginError{Type: "password_expired", Message: "a senha do Softtrade expirou e precisa ser trocada"}
+	ErrPasswordChange     = &LoginError{Type: "password_change", Message: "o Softtrade exige a troca da senha"}
+	ErrPasswordFormAbsent = &LoginError{Type: "password_change", Message: "formulário de troca de senha não encontrado"}
+	ErrAccountLocked      = &LoginError{Type: "account_locked", Message: "usuário bloqueado no Softtrade; procure o suporte para desbloquear"}
+	ErrInvalidOTP         = &LoginError{Type: "otp", Message: "código de verificação recusado"}
+	ErrOTPRequired        = &LoginError{Type: "otp", Message: "o Softtrade pediu um código de verificação, mas não há segredo TOTP salvo nem como solicitá-lo"}
 )
 
+// loginFailures maps the messages shown by Softtrade (lowercase) to the login error they
+// indicate, checked in order
+var loginFailures = []struct {
+	err     *LoginError
+	phrases []string
+}{
+	{ErrAccountLocked, []string{"usuário bloqueado", "usuario bloqueado", "conta bloqueada", "acesso bloqueado", "excedeu o número de tentativas", "excedeu o numero de tentativas"}},
+	{ErrInvalidOTP, []string{"código inválido", "codigo invalido", "código expirado", "codigo expirado", "código incorreto", "codigo incorreto", "token inválido", "token invalido"}},
+	{ErrPasswordExpired, []string{"senha expirada", "senha expirou", "senha vencida"}},
+	{ErrPasswordChange, []string{"troca de senha", "alterar sua senha", "altere sua senha", "alteração de senha", "alteracao de senha", "redefinir sua senha", "nova senha"}},
+	{ErrInvalidCredentials, []string{"acesso negado", "credenciais inválidas", "usuário ou senha incorretos", "login inválido"}},
+}
+
+// classifyLoginMessage returns the login error indicated by a Softtrade message, or nil
+func classifyLoginMessage(message string) *LoginError {
+	message = strings.ToLower(message)
+	for _, failure := range loginFailures {
+		for _, phrase := range failure.phrases {
+			if strings.Contains(message, phrase) {
+				return failure.err
+			}
+		}
+	}
+	return nil
+}
+
 const (
-	baseURL             = "https://oliveiratrust.softtrade.com.br"
-	defaultTimeout      = 2 * time.Minute
-	browserWindowWidth  = 1280
-	browserWindowHeight = 720
+	baseURL        = "https://oliveiratrust.softtrade.com.br"
+	defaultTimeout = 2 * time.Minute
 )
 
 type loginStep struct {
@@ -46,6 +78,15 @@ type Credentials struct {
 	Password string
 }
 
+// MaskedUsername returns the username with only its first and last characters visible
+func (c Credentials) MaskedUsername() string {
+	runes := []rune(c.Username)
+	if len(runes) <= 2 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[0]) + strings.Repeat("*", len(runes)-2) + string(runes[len(runes)-1])
+}
+
 func (c Credentials) validate() error {
 	if c.Username == "" || c.Password == "" {
 		return ErrEmptyCredentials
@@ -60,54 +101,119 @@ type BrowserSession interface {
 }
 
 type AuthSession struct {
-	ctx    context.Context
-	cancel context.CancelFunc
+	// tab is the browser tab, kept open until Close; ctx limits the running login or
+	// password change to defaultTimeout
+	tab       context.Context
+	ctx       context.Context
+	cancel    context.CancelFunc
+	selectors selectors.Softtrade
+	baseURL   string
+	clock     common.Relogio
+	store     CredentialStore
+
+	totpSecret  string
+	otpPrompter OTPPrompter
 }
 
-// NewAuthSession creates a new authentication session
-func NewAuthSession(headless bool) BrowserSession {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
-
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", headless),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-setuid-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("disable-gpu", true),
-		chromedp.Flag("no-first-run", true),
-		chromedp.Flag("no-default-browser-check", true),
-		chromedp.Flag("ignore-certificate-errors", true),
-		chromedp.Flag("disable-extensions", true),
-		chromedp.WindowSize(browserWindowWidth, browserWindowHeight),
-		chromedp.UserAgent("Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
-	)
+// NewAuthSession creates a new authentication session.
+// When config.Navegador is not nil, the session runs in an isolated tab of the shared
+// browser; otherwise a dedicated Chromium process is started.
+func NewAuthSession(config Config) BrowserSession {
+	sel := config.Seletores
+	if sel == nil {
+		sel = &selectors.Padrao().Softtrade
+	}
+
+	url := config.URLBase
+	if url == "" {
+		url = baseURL
+	}
+
+	clock := common.RelogioOuSistema(config.Relogio)
+
+	if config.Navegador != nil {
+		tabCtx, cancelTab, err := config.Navegador.NovaAba()
+		if err != nil {
+			log.Printf("falha ao abrir aba do navegador: %v", err)
+			return nil
+		}
+
+		return &AuthSession{
+			tab:         tabCtx,
+			ctx:         tabCtx,
+			cancel:      cancelTab,
+			selectors:   *sel,
+			baseURL:     url,
+			clock:       clock,
+			store:       config.Armazenamento,
+			totpSecret:  config.SegredoTOTP,
+			otpPrompter: config.SolicitanteCodigo,
+		}
+	}
 
-	allocCtx, _ := chromedp.NewExecAllocator(ctx, opts...)
-	browserCtx, _ := chromedp.NewContext(allocCtx,
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), common.OpcoesNavegador(config.Headless)...)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx,
 		chromedp.WithLogf(log.Printf),
 	)
 
+	// Starts the browser now: chromedp ties it to the context of the first Run, and the
+	// first Run of Login uses a context limited to defaultTimeout
+	if err := chromedp.Run(browserCtx); err != nil {
+		cancelBrowser()
+		cancelAlloc()
+		log.Printf("falha ao iniciar o navegador: %v", err)
+		return nil
+	}
+
 	return &AuthSession{
-		ctx:    browserCtx,
-		cancel: cancel,
+		tab: browserCtx,
+		ctx: browserCtx,
+		cancel: func() {
+			cancelBrowser()
+			cancelAlloc()
+		},
+		selectors:   *sel,
+		baseURL:     url,
+		clock:       clock,
+		store:       config.Armazenamento,
+		totpSecret:  config.SegredoTOTP,
+		otpPrompter: config.SolicitanteCodigo,
 	}
 }
 
+// GetContext returns the browser tab, which stays open after the login for the clock-in module
 func (a *AuthSession) GetContext() context.Context {
-	return a.ctx
+	return a.tab
+}
+
+// limit bounds a.ctx to defaultTimeout for a login or password change; the returned
+// function releases it
+func (a *AuthSession) limit() context.CancelFunc {
+	ctx, cancel := common.ComTempoLimite(a.tab, a.clock, defaultTimeout)
+	a.ctx = ctx
+	return func() {
+		cancel()
+		a.ctx = a.tab
+	}
 }
 
 func (a *AuthSession) Login(creds Credentials) error {
 	if err := creds.validate(); err != nil {
 		return err
 	}
+	defer a.limit()()
+
+	// Reaproveita a sessão salva enquanto ela ainda abre a página de marcação
+	if a.restoreSession(creds.Username) {
+		return nil
+	}
 
 	// Primeiro passo: Navegar e aguardar a página carregar completamente
 	if err := a.executeLoginStep(loginStep{
 		actions: []chromedp.Action{
-			chromedp.Navigate(baseURL),
+			chromedp.Navigate(a.baseURL),
 			chromedp.WaitReady("body"),
-			chromedp.WaitVisible(`input[id="username"]`, chromedp.ByQuery),
+			chromedp.WaitVisible(a.selectors.LoginUsuario, chromedp.ByQuery),
 		},
 		errMsg: "falha ao carregar página de login",
 	}); err != nil {
@@ -117,12 +223,12 @@ func (a *AuthSession) Login(creds Credentials) error {
 	// Segundo passo: Preencher e submeter o formulário
 	if err := a.executeLoginStep(loginStep{
 		actions: []chromedp.Action{
-			chromedp.Focus(`input[id="username"]`),
-			chromedp.SendKeys(`input[id="username"]`, creds.Username),
-			chromedp.Focus(`input[id="password"]`),
-			chromedp.SendKeys(`input[id="password"]`, creds.Password),
-			chromedp.WaitVisible(`button[id="verifqUsu"]`),
-			chromedp.Click(`button[id="verifqUsu"]`),
+			chromedp.Focus(a.selectors.LoginUsuario),
+			chromedp.SendKeys(a.selectors.LoginUsuario, creds.Username),
+			chromedp.Focus(a.selectors.LoginSenha),
+			chromedp.SendKeys(a.selectors.LoginSenha, creds.Password),
+			chromedp.WaitVisible(a.selectors.LoginEntrar),
+			common.Clicar(a.selectors.LoginEntrar),
 		},
 		errMsg: "falha no processo de login",
 	}); err != nil {
@@ -130,40 +236,58 @@ func (a *AuthSession) Login(creds Credentials) error {
 	}
 
 	// Aguarda um pouco para a página processar o login
-	if err := chromedp.Run(a.ctx, chromedp.Sleep(2*time.Second)); err != nil {
+	if err := common.Dormir(a.ctx, a.clock, 2*time.Second); err != nil {
 		return err
 	}
 
-	// Terceiro passo: Verificar se há mensagem de erro
-	hasInvalidCredentials, err := a.checkForLoginErrors()
-	if err != nil {
+	// Terceiro passo: Verificar se há mensagem de erro (credenciais, senha expirada, bloqueio)
+	if err := a.checkForLoginErrors(); err != nil {
+		return err
+	}
+
+	// Quarto passo: Aguardar a página principal, o código de verificação ou a troca de senha
+	if err := a.waitAfterLogin(); err != nil {
+		return err
+	}
+
+	// Quinto passo: Responder ao segundo fator, se o Softtrade pedir o código
+	if err := a.completeSecondFactor(); err != nil {
 		return err
 	}
-	if hasInvalidCredentials {
-		return ErrInvalidCredentials
+
+	if err := a.checkForPasswordChange(); err != nil {
+		return err
 	}
 
-	// Quarto passo: Aguardar carregamento da página principal
+	if err := a.saveSession(creds.Username); err != nil {
+		fmt.Printf("\n⚠️  Aviso: Não foi possível salvar a sessão do Softtrade: %v\n", err)
+	}
+
+	return nil
+}
+
+// waitAfterLogin waits for the clock-in page, the verification code page or the password
+// change form, whichever Softtrade shows after the credentials
+func (a *AuthSession) waitAfterLogin() error {
 	if err := a.executeLoginStep(loginStep{
 		actions: []chromedp.Action{
 			chromedp.WaitReady("body"),
-			chromedp.WaitVisible(`//button[contains(., 'Entrada') or contains(., 'Saída') or contains(., 'refeição')]`, chromedp.BySearch),
+			chromedp.Poll(fmt.Sprintf(`document.querySelector(%s) !== null || document.querySelector(%s) !== null || document.querySelectorAll(%s).length >= 2`,
+				selectors.JS(a.selectors.Formulario), selectors.JS(a.selectors.CodigoCampos), selectors.JS(a.selectors.TrocaSenhaCampos)), nil),
 		},
 		errMsg: "falha ao carregar a página após login",
 	}); err != nil {
 		// Verifica novamente se houve erro de credenciais
-		hasInvalidCredentials, checkErr := a.checkForLoginErrors()
-		if checkErr == nil && hasInvalidCredentials {
-			return ErrInvalidCredentials
+		if checkErr := a.checkForLoginErrors(); checkErr != nil {
+			return checkErr
 		}
 		return err
 	}
-
 	return nil
 }
 
 func (a *AuthSession) executeLoginStep(step loginStep) error {
-	err := chromedp.Run(a.ctx, step.actions...)
+	err := common.Executar(a.ctx, step.actions...)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return &LoginError{
@@ -181,78 +305,104 @@ func (a *AuthSession) executeLoginStep(step loginStep) error {
 	return nil
 }
 
-func (a *AuthSession) checkForLoginErrors() (bool, error) {
-	// Aguarda um pouco para a página recarregar e o alerta aparecer
-	var err error
+// checkForLoginErrors reads the error messages of the page and returns the login error they
+// indicate, or nil when there is none or the message is unknown
+func (a *AuthSession) checkForLoginErrors() error {
+	mensagemErro, err := a.readErrorMessage()
+	if err != nil {
+		return err
+	}
+
+	if loginErr := classifyLoginMessage(mensagemErro); loginErr != nil {
+		return loginErr
+	}
+
+	// Se encontrou alguma mensagem de erro mas não é uma das conhecidas, loga para debug
+	if mensagemErro != "" {
+		log.Printf("Mensagem de erro encontrada: %s", mensagemErro)
+	}
+
+	return nil
+}
 
+// readErrorMessage returns the first non-empty message of the error selectors
+func (a *AuthSession) readErrorMessage() (string, error) {
 	var mensagemErro string
-	err = chromedp.Run(a.ctx,
-		chromedp.Evaluate(`
+	err := common.Executar(a.ctx,
+		chromedp.Evaluate(fmt.Sprintf(`
 			(function() {
 				// Verifica mensagens de erro em diferentes elementos possíveis
-				const seletores = [
-					'.ui-messages-error-detail',
-					'.ui-messages-error span',
-					'.ui-messages span',
-					'.ui-message-error-detail',
-					'.ui-message-error span'
-				];
+				const seletores = %s;
 
 				for (const seletor of seletores) {
-					const elemento = document.querySelector(seletor);
-					if (elemento) {
+					for (const elemento of document.querySelectorAll(seletor)) {
 						const texto = elemento.textContent.trim();
 						if (texto) return texto;
 					}
 				}
 
-				// Verifica alertas do navegador
-				const alerts = document.querySelectorAll('[role="alert"]');
-				for (const alert of alerts) {
-					const texto = alert.textContent.trim();
-					if (texto) return texto;
-				}
-
 				return '';
 			})()
-		`, &mensagemErro),
+		`, selectors.JSLista(a.selectors.MensagensErro)), &mensagemErro),
 	)
 	if err != nil {
-		return false, &LoginError{
+		return "", &LoginError{
 			Type:    "validation",
 			Message: "falha ao verificar erros de login",
 			Cause:   err,
 		}
 	}
+	return mensagemErro, nil
+}
+
+func (a *AuthSession) Close() {
+	if a.cancel != nil {
+		a.cancel()
+	}
+}
+
+// ValidarSeletores checks the login page selectors and, after authenticating, the
+// selectors of the clock-in page. The session must have been created with the same map.
+func ValidarSeletores(session Module, creds Credentials, mapa *selectors.Mapa) ([]selectors.ResultadoValidacao, error) {
+	ctx, cancel := common.ComTempoLimite(session.GetContext(), nil, defaultTimeout)
+	defer cancel()
+
+	url := baseURL
+	if s, ok := session.(*AuthSession); ok {
+		url = s.baseURL
+	}
+	if err := common.Executar(ctx,
+		chromedp.Navigate(url),
+		chromedp.WaitReady("body"),
+	); err != nil {
+		return nil, fmt.Errorf("falha ao carregar página de login: %w", err)
+	}
 
-	// Verifica várias possíveis mensagens de erro
-	mensagemErroLower := strings.ToLower(mensagemErro)
-	mensagensErro := []string{
-		"acesso negado",
-		"credenciais inválidas",
-		"usuário ou senha incorretos",
-		"login inválido",
+	resultados, err := selectors.Validar(ctx, mapa.Filtrar("login"))
+	if err != nil {
+		return resultados, err
 	}
 
-	for _, erro := range mensagensErro {
-		if strings.Contains(mensagemErroLower, erro) {
-			return true, &LoginError{
-				Type:    "auth",
-				Message: "credenciais inválidas",
+	// As páginas de troca de senha e de código só aparecem em logins específicos; seus
+	// seletores têm apenas a sintaxe conferida e são listados como não verificados
+	for _, pagina := range []string{"troca_senha", "codigo"} {
+		extras, err := selectors.Validar(ctx, mapa.Filtrar(pagina))
+		for i := range extras {
+			if extras[i].Situacao != selectors.SituacaoInvalido {
+				extras[i].Situacao = selectors.SituacaoNaoVerificado
+				extras[i].Detalhe = "a página " + pagina + " não é aberta na validação"
 			}
 		}
+		resultados = append(resultados, extras...)
+		if err != nil {
+			return resultados, err
+		}
 	}
 
-	// Se encontrou alguma mensagem de erro mas não é uma das conhecidas, loga para debug
-	if mensagemErro != "" {
-		log.Printf("Mensagem de erro encontrada: %s", mensagemErro)
+	if err := session.Login(creds); err != nil {
+		return resultados, fmt.Errorf("falha no login, seletores da página de marcação não verificados: %w", err)
 	}
 
-	return false, nil
-}
-
-func (a *AuthSession) Close() {
-	if a.cancel != nil {
-		a.cancel()
-	}
+	marcacao, err := selectors.Validar(ctx, mapa.Filtrar("marcacao"))
+	return append(resultados, marcacao...), err
 }
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/softtradefake"
+)
+
+// exigirChromium pula o teste quando não há Chromium instalado ou com -short
+func exigirChromium(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("teste com navegador ignorado em -short")
+	}
+	for _, nome := range []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless-shell", "headless_shell"} {
+		if _, err := exec.LookPath(nome); err == nil {
+			return
+		}
+	}
+	t.Skip("Chromium não instalado")
+}
+
+// novaSessaoFake inicia o Softtrade falso e uma sessão headless apontada para ele
+func novaSessaoFake(t *testing.T, cenario softtradefake.Cenario) (*softtradefake.Servidor, BrowserSession) {
+	t.Helper()
+	exigirChromium(t)
+
+	servidor := softtradefake.Iniciar(cenario)
+	t.Cleanup(servidor.Close)
+
+	navegador := common.NovoGerenciadorNavegador(true)
+	t.Cleanup(navegador.Close)
+
+	sessao := NewAuthSession(Config{Navegador: navegador, URLBase: servidor.URL()})
+	t.Cleanup(sessao.Close)
+	return servidor, sessao
+}
+
+func TestLoginFake(t *testing.T) {
+	_, sessao := novaSessaoFake(t, softtradefake.CenarioPadrao())
+
+	if err := sessao.Login(Credentials{Username: "usuario", Password: "senha"}); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+}
+
+func TestLoginDescartaSessaoExpirada(t *testing.T) {
+	exigirChromium(t)
+	servidor := softtradefake.Iniciar(softtradefake.CenarioPadrao())
+	t.Cleanup(servidor.Close)
+	navegador := common.NovoGerenciadorNavegador(true)
+	t.Cleanup(navegador.Close)
+
+	// Sessão salva que o Softtrade não reconhece mais, com um cookie que o login não recria
+	host := strings.TrimPrefix(servidor.URL(), "http://")
+	host = host[:strings.LastIndex(host, ":")]
+	store := NewFileStore(t.TempDir())
+	cookie := `{"name":%q,"value":%q,"domain":%q,"path":"/","session":true,"priority":"Medium","sourceScheme":"NonSecure"}`
+	velha := fmt.Sprintf(`{"usuario":"usuario","cookies":[`+cookie+`,`+cookie+`]}`, "JSESSIONID", "expirada", host, "LEMBRAR", "velho", host)
+	if err := store.SaveSecret(SecretSofttradeCookies, []byte(velha)); err != nil {
+		t.Fatal(err)
+	}
+
+	sessao := NewAuthSession(Config{Navegador: navegador, URLBase: servidor.URL(), Armazenamento: store})
+	t.Cleanup(sessao.Close)
+	if err := sessao.Login(Credentials{Username: "usuario", Password: "senha"}); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	dados, err := store.LoadSecret(SecretSofttradeCookies)
+	if err != nil {
+		t.Fatalf("sessão nova não foi salva: %v", err)
+	}
+	var nova savedSession
+	if err := json.Unmarshal(dados, &nova); err != nil {
+		t.Fatal(err)
+	}
+	for _, cookie := range nova.Cookies {
+		if cookie.Name == "LEMBRAR" || cookie.Value == "expirada" {
+			t.Fatalf("cookie da sessão expirada mantido: %s=%s", cookie.Name, cookie.Value)
+		}
+	}
+}
+
+func TestLoginCredenciaisInvalidas(t *testing.T) {
+	_, sessao := novaSessaoFake(t, softtradefake.CenarioPadrao())
+
+	err := sessao.Login(Credentials{Username: "usuario", Password: "errada"})
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login com senha errada = %v, esperado %v", err, ErrInvalidCredentials)
+	}
+}
+
+func TestChangePasswordSemFormulario(t *testing.T) {
+	_, sessao := novaSessaoFake(t, softtradefake.CenarioPadrao())
+	if err := sessao.Login(Credentials{Username: "usuario", Password: "senha"}); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	err := sessao.(PasswordChanger).ChangePassword("senha", "nova")
+	if !errors.Is(err, ErrPasswordFormAbsent) {
+		t.Fatalf("ChangePassword fora do formulário = %v, esperado %v", err, ErrPasswordFormAbsent)
+	}
+}
+
+func TestValidarSeletores(t *testing.T) {
+	_, sessao := novaSessaoFake(t, softtradefake.CenarioPadrao())
+
+	resultados, err := ValidarSeletores(sessao, Credentials{Username: "usuario", Password: "errada"}, selectors.Padrao())
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("ValidarSeletores com login recusado = %v, esperado %v", err, ErrInvalidCredentials)
+	}
+	for _, r := range resultados {
+		switch r.Seletor.Pagina {
+		case "marcacao":
+			t.Errorf("%s verificado sem login", r.Seletor.Nome)
+		case "troca_senha", "codigo":
+			if r.Situacao != selectors.SituacaoNaoVerificado {
+				t.Errorf("%s = %s, esperado %s", r.Seletor.Nome, r.Situacao, selectors.SituacaoNaoVerificado)
+			}
+		}
+	}
+}
+
+func TestLoginCredenciaisVazias(t *testing.T) {
+	sessao := &AuthSession{}
+	if err := sessao.Login(Credentials{Username: "usuario"}); !errors.Is(err, ErrEmptyCredentials) {
+		t.Fatalf("Login sem senha = %v, esperado %v", err, ErrEmptyCredentials)
+	}
+}
+
+func TestClassifyLoginMessage(t *testing.T) {
+	casos := []struct {
+		mensagem string
+		esperado *LoginError
+	}{
+		{"Usuário ou senha incorretos", ErrInvalidCredentials},
+		{"Sua senha expirou. Altere sua senha.", ErrPasswordExpired},
+		{"Usuário bloqueado após excesso de tentativas", ErrAccountLocked},
+		{"Código inválido", ErrInvalidOTP},
+		{"Informe a nova senha", ErrPasswordChange},
+		{"Bem-vindo", nil},
+	}
+
+	for _, caso := range casos {
+		if obtido := classifyLoginMessage(caso.mensagem); obtido != caso.esperado {
+			t.Errorf("classifyLoginMessage(%q) = %v, esperado %v", caso.mensagem, obtido, caso.esperado)
+		}
+	}
+}
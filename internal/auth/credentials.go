@@ -1,43 +1,69 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
-	"github.com/joho/godotenv"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/cofre"
 	"github.com/manifoldco/promptui"
 )
 
-const (
-	configDirName = ".batedorponto"
-	envFileName   = ".env"
-)
+const envFileName = ".env"
 
 // ErrCredenciaisNaoEncontradas indica que as credenciais não foram encontradas e precisam ser inseridas
 var ErrCredenciaisNaoEncontradas = fmt.Errorf("credenciais não encontradas")
 
-// CarregarCredenciais loads credentials from environment or prompts the user
-func CarregarCredenciais() (Credentials, error) {
-	configDir := filepath.Join(os.Getenv("HOME"), configDirName)
-	if err := os.MkdirAll(configDir, 0700); err != nil {
-		return Credentials{}, fmt.Errorf("erro ao criar diretório de configuração: %w", err)
+// CarregarCredenciais tries the sources in order and returns the first credentials found
+// with the source that provided them. A failing source is reported and skipped; when none
+// has credentials it returns ErrCredenciaisNaoEncontradas, and the caller should fall back
+// to SolicitarCredenciais.
+func CarregarCredenciais(sources ...CredentialSource) (Credentials, CredentialSource, error) {
+	for _, source := range sources {
+		creds, err := source.Credentials(context.Background())
+		if err == nil {
+			return creds, source, nil
+		}
+		if !errors.Is(err, ErrCredenciaisNaoEncontradas) {
+			fmt.Printf("\n⚠️  Aviso: Credenciais de %s indisponíveis: %v\n", source.Name(), err)
+		}
 	}
+	return Credentials{}, nil, ErrCredenciaisNaoEncontradas
+}
 
-	envFile := filepath.Join(configDir, envFileName)
-	if err := godotenv.Load(envFile); err == nil {
-		username := os.Getenv("USERNAME_PONTO")
-		password := os.Getenv("PASSWORD_PONTO")
+// MigrarCredenciais moves the credentials from the .env file in configDir, and the saved
+// Softtrade session, to the unlocked vault and deletes the files. It returns false if there
+// were no saved credentials.
+func MigrarCredenciais(configDir string, vault *cofre.Cofre) (bool, error) {
+	arquivo := NewFileStore(configDir)
+	destino := NewVaultStore(vault)
 
-		if username != "" && password != "" {
-			return Credentials{
-				Username: username,
-				Password: password,
-			}, nil
+	if sessao, err := arquivo.LoadSecret(SecretSofttradeCookies); err == nil {
+		if err := destino.SaveSecret(SecretSofttradeCookies, sessao); err != nil {
+			return false, fmt.Errorf("erro ao salvar sessão do Softtrade no cofre: %w", err)
+		}
+		if err := os.Remove(filepath.Join(configDir, SecretSofttradeCookies+".json")); err != nil {
+			return false, fmt.Errorf("sessão do Softtrade copiada para o cofre, mas o arquivo não foi apagado: %w", err)
 		}
 	}
 
-	return Credentials{}, ErrCredenciaisNaoEncontradas
+	creds, err := arquivo.Load()
+	if err != nil {
+		if errors.Is(err, ErrCredenciaisNaoEncontradas) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := destino.Save(creds); err != nil {
+		return false, err
+	}
+	if err := arquivo.Delete(); err != nil {
+		return true, fmt.Errorf("credenciais copiadas para o cofre, mas %s não foi apagado: %w", envFileName, err)
+	}
+	return true, nil
 }
 
 // SolicitarCredenciais solicita as credenciais do usuário
@@ -89,8 +115,36 @@ func SolicitarCredenciais() (Credentials, error) {
 	return creds, nil
 }
 
-// SalvarCredenciais salva as credenciais em arquivo
-func SalvarCredenciais(creds Credentials) error {
+// SolicitarNovaSenha solicita a nova senha exigida pelo Softtrade, com confirmação
+func SolicitarNovaSenha() (string, error) {
+	novaPrompt := promptui.Prompt{
+		Label: "Nova senha",
+		Mask:  '*',
+		Validate: func(input string) error {
+			if len(input) < 4 {
+				return fmt.Errorf("senha deve ter pelo menos 4 caracteres")
+			}
+			return nil
+		},
+	}
+	nova, err := novaPrompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler nova senha: %w", err)
+	}
+
+	confirmacaoPrompt := promptui.Prompt{Label: "Confirme a nova senha", Mask: '*'}
+	confirmacao, err := confirmacaoPrompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler confirmação: %w", err)
+	}
+	if confirmacao != nova {
+		return "", errors.New("as senhas não conferem")
+	}
+	return nova, nil
+}
+
+// SalvarCredenciais pergunta se as credenciais devem ser salvas e as grava no armazenamento
+func SalvarCredenciais(creds Credentials, store CredentialStore) error {
 	// Pergunta se deseja salvar
 	confirmPrompt := promptui.Prompt{
 		Label:     "Deseja salvar as credenciais? (Recomendado)",
@@ -108,17 +162,10 @@ func SalvarCredenciais(creds Credentials) error {
 	}
 
 	if resultado == "y" || resultado == "Y" {
-		configDir := filepath.Join(os.Getenv("HOME"), configDirName)
-		if err := os.MkdirAll(configDir, 0700); err != nil {
-			return fmt.Errorf("erro ao criar diretório de configuração: %w", err)
-		}
-
-		envContent := fmt.Sprintf("USERNAME_PONTO=%s\nPASSWORD_PONTO=%s\n", creds.Username, creds.Password)
-		envFile := filepath.Join(configDir, envFileName)
-		if err := os.WriteFile(envFile, []byte(envContent), 0600); err != nil {
-			return fmt.Errorf("erro ao salvar credenciais: %w", err)
+		if err := store.Save(creds); err != nil {
+			return err
 		}
-		fmt.Println("\nCredenciais salvas com sucesso")
+		fmt.Printf("\nCredenciais salvas (%s)\n", store.Name())
 	} else {
 		fmt.Println("\nCredenciais não serão salvas. Você precisará inseri-las novamente na próxima execução.")
 	}
@@ -3,6 +3,9 @@ package auth
 import (
 	"context"
 	"fmt"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
 )
 
 // Module defines the interface for authentication operations
@@ -24,15 +27,42 @@ type Config struct {
 
 	// UseMock determina se será usado o mock ao invés do browser real
 	UseMock bool
+
+	// MockScenario roteiriza as falhas e as credenciais aceitas pelo mock
+	MockScenario MockScenario
+
+	// Navegador é a instância compartilhada do Chromium; se nil, uma instância própria é iniciada
+	Navegador *common.GerenciadorNavegador
+
+	// URLBase é o endereço do Softtrade; vazio usa o endereço de produção
+	URLBase string
+
+	// Seletores contém os seletores do Softtrade; se nil, usa o mapa embutido
+	Seletores *selectors.Softtrade
+
+	// Relogio mede as esperas e o tempo limite do login; nil usa o relógio do sistema
+	Relogio common.Relogio
+
+	// Armazenamento guarda os cookies da sessão do Softtrade para reutilizá-la nas próximas
+	// execuções; nil faz sempre o login pelo formulário
+	Armazenamento CredentialStore
+
+	// SegredoTOTP é o segredo (base32 ou URI otpauth://) usado para gerar o código de
+	// verificação quando o Softtrade o pede após o login; vazio recorre ao SolicitanteCodigo
+	SegredoTOTP string
+
+	// SolicitanteCodigo pede o código de verificação ao usuário; nil faz o login falhar
+	// com ErrOTPRequired quando não há segredo TOTP
+	SolicitanteCodigo OTPPrompter
 }
 
 // NewModule creates a new instance of the Auth module
 func NewModule(config Config) (Module, error) {
 	if config.UseMock {
-		return NewMockSession(), nil
+		return NewMockSession(config.MockScenario, config.Relogio), nil
 	}
 
-	session := NewAuthSession(config.Headless)
+	session := NewAuthSession(config)
 	if session == nil {
 		return nil, fmt.Errorf("failed to create auth session")
 	}
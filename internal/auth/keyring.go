@@ -0,0 +1,239 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Secret Service (org.freedesktop.secrets) names used by the keyring backend
+const (
+	secretsBusName         = "org.freedesktop.secrets"
+	secretsServicePath     = dbus.ObjectPath("/org/freedesktop/secrets")
+	secretsDefaultAlias    = dbus.ObjectPath("/org/freedesktop/secrets/aliases/default")
+	secretsServiceIface    = "org.freedesktop.Secret.Service"
+	secretsCollectionIface = "org.freedesktop.Secret.Collection"
+	secretsItemIface       = "org.freedesktop.Secret.Item"
+	secretsPromptIface     = "org.freedesktop.Secret.Prompt"
+
+	keyringApplication = "batedor-ponto"
+)
+
+// DefaultPromptTimeout limits the wait for a keyring prompt (unlock or confirmation) when
+// KeyringStore.PromptTimeout is zero
+const DefaultPromptTimeout = 2 * time.Minute
+
+// secret mirrors the Secret Service (oayays) struct
+type secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// KeyringStore keeps the credentials and secrets in the desktop keyring (GNOME Keyring,
+// KWallet) through the Secret Service D-Bus API. Items are tagged with the attributes
+// application=batedor-ponto and item=<key> in the default collection.
+type KeyringStore struct {
+	conn    *dbus.Conn
+	session dbus.ObjectPath
+
+	// PromptTimeout limits the wait for each prompt, which is dismissed when it expires;
+	// zero uses DefaultPromptTimeout
+	PromptTimeout time.Duration
+}
+
+// ConnectKeyring opens a Secret Service session on the user's session bus
+func ConnectKeyring() (*KeyringStore, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("barramento de sessão D-Bus indisponível: %w", err)
+	}
+	store, err := NewKeyringStore(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// NewKeyringStore opens a Secret Service session on an existing connection, which allows
+// using a private bus or a mock service
+func NewKeyringStore(conn *dbus.Conn) (*KeyringStore, error) {
+	var saida dbus.Variant
+	var session dbus.ObjectPath
+	err := conn.Object(secretsBusName, secretsServicePath).
+		Call(secretsServiceIface+".OpenSession", 0, "plain", dbus.MakeVariant("")).
+		Store(&saida, &session)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir sessão no Secret Service: %w", err)
+	}
+	return &KeyringStore{conn: conn, session: session}, nil
+}
+
+// Close closes the Secret Service session
+func (k *KeyringStore) Close() error {
+	k.conn.Object(secretsBusName, k.session).Call("org.freedesktop.Secret.Session.Close", 0)
+	return k.conn.Close()
+}
+
+func (k *KeyringStore) Name() string {
+	return "keyring (Secret Service)"
+}
+
+func (k *KeyringStore) Load() (Credentials, error) {
+	return loadCredentials(k)
+}
+
+func (k *KeyringStore) Save(creds Credentials) error {
+	return saveCredentials(k, creds)
+}
+
+func (k *KeyringStore) Delete() error {
+	for _, key := range []string{SecretUsername, SecretPassword} {
+		if err := k.DeleteSecret(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *KeyringStore) DeleteSecret(key string) error {
+	itens, err := k.search(key)
+	if err != nil {
+		return err
+	}
+	for _, item := range itens {
+		var prompt dbus.ObjectPath
+		if err := k.conn.Object(secretsBusName, item).Call(secretsItemIface+".Delete", 0).Store(&prompt); err != nil {
+			return fmt.Errorf("erro ao apagar %s do keyring: %w", key, err)
+		}
+		if err := k.prompt(prompt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (k *KeyringStore) LoadSecret(key string) ([]byte, error) {
+	itens, err := k.search(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(itens) == 0 {
+		return nil, ErrSecretNotFound
+	}
+
+	var s secret
+	if err := k.conn.Object(secretsBusName, itens[0]).Call(secretsItemIface+".GetSecret", 0, k.session).Store(&s); err != nil {
+		return nil, fmt.Errorf("erro ao ler %s do keyring: %w", key, err)
+	}
+	return s.Value, nil
+}
+
+func (k *KeyringStore) SaveSecret(key string, value []byte) error {
+	if err := k.unlock([]dbus.ObjectPath{secretsDefaultAlias}); err != nil {
+		return err
+	}
+
+	propriedades := map[string]dbus.Variant{
+		secretsItemIface + ".Label":      dbus.MakeVariant("Batedor de Ponto: " + key),
+		secretsItemIface + ".Attributes": dbus.MakeVariant(attributes(key)),
+	}
+	s := secret{Session: k.session, Value: value, ContentType: "application/octet-stream"}
+
+	var item, prompt dbus.ObjectPath
+	err := k.conn.Object(secretsBusName, secretsDefaultAlias).
+		Call(secretsCollectionIface+".CreateItem", 0, propriedades, s, true).
+		Store(&item, &prompt)
+	if err != nil {
+		return fmt.Errorf("erro ao salvar %s no keyring: %w", key, err)
+	}
+	return k.prompt(prompt)
+}
+
+func attributes(key string) map[string]string {
+	return map[string]string{"application": keyringApplication, "item": key}
+}
+
+// search returns the unlocked items stored under key, unlocking the locked ones
+func (k *KeyringStore) search(key string) ([]dbus.ObjectPath, error) {
+	var desbloqueados, bloqueados []dbus.ObjectPath
+	err := k.conn.Object(secretsBusName, secretsServicePath).
+		Call(secretsServiceIface+".SearchItems", 0, attributes(key)).
+		Store(&desbloqueados, &bloqueados)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar %s no keyring: %w", key, err)
+	}
+	if len(bloqueados) > 0 {
+		if err := k.unlock(bloqueados); err != nil {
+			return nil, err
+		}
+		desbloqueados = append(desbloqueados, bloqueados...)
+	}
+	return desbloqueados, nil
+}
+
+// unlock asks the service to unlock the objects, showing its prompt when required
+func (k *KeyringStore) unlock(objetos []dbus.ObjectPath) error {
+	var desbloqueados []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	err := k.conn.Object(secretsBusName, secretsServicePath).
+		Call(secretsServiceIface+".Unlock", 0, objetos).
+		Store(&desbloqueados, &prompt)
+	if err != nil {
+		return fmt.Errorf("erro ao desbloquear o keyring: %w", err)
+	}
+	return k.prompt(prompt)
+}
+
+// prompt runs a Secret Service prompt ("/" means none) and waits for its Completed signal
+func (k *KeyringStore) prompt(caminho dbus.ObjectPath) error {
+	if caminho == "" || caminho == "/" {
+		return nil
+	}
+
+	regra := []dbus.MatchOption{dbus.WithMatchObjectPath(caminho), dbus.WithMatchInterface(secretsPromptIface), dbus.WithMatchMember("Completed")}
+	if err := k.conn.AddMatchSignal(regra...); err != nil {
+		return fmt.Errorf("erro ao aguardar confirmação do keyring: %w", err)
+	}
+	defer k.conn.RemoveMatchSignal(regra...)
+
+	sinais := make(chan *dbus.Signal, 1)
+	k.conn.Signal(sinais)
+	defer k.conn.RemoveSignal(sinais)
+
+	if err := k.conn.Object(secretsBusName, caminho).Call(secretsPromptIface+".Prompt", 0, "").Err; err != nil {
+		return fmt.Errorf("erro ao exibir confirmação do keyring: %w", err)
+	}
+
+	limite := k.PromptTimeout
+	if limite <= 0 {
+		limite = DefaultPromptTimeout
+	}
+	temporizador := time.NewTimer(limite)
+	defer temporizador.Stop()
+
+	for {
+		select {
+		case sinal, ok := <-sinais:
+			if !ok {
+				return errors.New("conexão com o keyring encerrada")
+			}
+			if sinal.Path != caminho || sinal.Name != secretsPromptIface+".Completed" {
+				continue
+			}
+			if len(sinal.Body) > 0 {
+				if cancelado, ok := sinal.Body[0].(bool); ok && cancelado {
+					return errors.New("operação no keyring cancelada pelo usuário")
+				}
+			}
+			return nil
+		case <-temporizador.C:
+			k.conn.Object(secretsBusName, caminho).Call(secretsPromptIface+".Dismiss", 0)
+			return fmt.Errorf("confirmação do keyring não respondida em %s", limite)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package auth
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Respostas do Secret Service falso aos prompts
+const (
+	promptConfirmado = iota
+	promptCancelado
+	promptSemResposta
+)
+
+// secretServiceFake implementa o mínimo do org.freedesktop.Secret.Service usado pelo
+// KeyringStore, exportado em um barramento D-Bus privado
+type secretServiceFake struct {
+	mu        sync.Mutex
+	conn      *dbus.Conn
+	itens     map[dbus.ObjectPath]*itemFake
+	proximo   int
+	bloqueado bool
+	resposta  int
+	dispensas int
+}
+
+type itemFake struct {
+	servico   *secretServiceFake
+	caminho   dbus.ObjectPath
+	atributos map[string]string
+	valor     []byte
+}
+
+type servicoFake struct{ *secretServiceFake }
+type colecaoFake struct{ *secretServiceFake }
+type sessaoFake struct{}
+
+func (sessaoFake) Close() *dbus.Error { return nil }
+
+// configBarramento é a configuração do dbus-daemon privado usado nos testes
+const configBarramento = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:dir=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>`
+
+// iniciarBarramento inicia um dbus-daemon privado e retorna seu endereço; pula o teste
+// quando o dbus-daemon não está instalado
+func iniciarBarramento(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon não instalado")
+	}
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "bus.conf")
+	if err := os.WriteFile(config, []byte(fmt.Sprintf(configBarramento, dir)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(daemon, "--config-file="+config, "--nofork", "--print-address=1")
+	saida, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("erro ao iniciar dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	endereco, err := bufio.NewReader(saida).ReadString('\n')
+	if err != nil {
+		t.Fatalf("erro ao ler o endereço do dbus-daemon: %v", err)
+	}
+	return strings.TrimSpace(endereco)
+}
+
+// novoKeyringFake exporta o serviço falso em um barramento privado e abre o KeyringStore
+// em outra conexão ao mesmo barramento
+func novoKeyringFake(t *testing.T) (*secretServiceFake, *KeyringStore) {
+	t.Helper()
+	endereco := iniciarBarramento(t)
+
+	connServico, err := dbus.Connect(endereco)
+	if err != nil {
+		t.Fatalf("erro ao conectar o serviço: %v", err)
+	}
+	t.Cleanup(func() { connServico.Close() })
+
+	servico := &secretServiceFake{conn: connServico, itens: make(map[dbus.ObjectPath]*itemFake)}
+	exportar := func(v any, caminho dbus.ObjectPath, iface string) {
+		if err := connServico.Export(v, caminho, iface); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exportar(servicoFake{servico}, secretsServicePath, secretsServiceIface)
+	exportar(colecaoFake{servico}, secretsDefaultAlias, secretsCollectionIface)
+	if resposta, err := connServico.RequestName(secretsBusName, dbus.NameFlagDoNotQueue); err != nil || resposta != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("erro ao registrar %s: %v", secretsBusName, err)
+	}
+
+	connCliente, err := dbus.Connect(endereco)
+	if err != nil {
+		t.Fatalf("erro ao conectar o cliente: %v", err)
+	}
+	store, err := NewKeyringStore(connCliente)
+	if err != nil {
+		connCliente.Close()
+		t.Fatalf("NewKeyringStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return servico, store
+}
+
+func (s servicoFake) OpenSession(algoritmo string, entrada dbus.Variant) (dbus.Variant, dbus.ObjectPath, *dbus.Error) {
+	if algoritmo != "plain" {
+		return dbus.Variant{}, "", dbus.MakeFailedError(errors.New("algoritmo não suportado"))
+	}
+	caminho := dbus.ObjectPath("/org/freedesktop/secrets/session/1")
+	if err := s.conn.Export(sessaoFake{}, caminho, "org.freedesktop.Secret.Session"); err != nil {
+		return dbus.Variant{}, "", dbus.MakeFailedError(err)
+	}
+	return dbus.MakeVariant(""), caminho, nil
+}
+
+func (s servicoFake) SearchItems(atributos map[string]string) ([]dbus.ObjectPath, []dbus.ObjectPath, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var encontrados []dbus.ObjectPath
+	for caminho, item := range s.itens {
+		if item.atributos["application"] == atributos["application"] && item.atributos["item"] == atributos["item"] {
+			encontrados = append(encontrados, caminho)
+		}
+	}
+	if s.bloqueado {
+		return []dbus.ObjectPath{}, encontrados, nil
+	}
+	return encontrados, []dbus.ObjectPath{}, nil
+}
+
+func (s servicoFake) Unlock(objetos []dbus.ObjectPath) ([]dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	s.mu.Lock()
+	bloqueado := s.bloqueado
+	s.mu.Unlock()
+	if !bloqueado {
+		return objetos, "/", nil
+	}
+	caminho, err := s.novoPrompt(func() {
+		s.mu.Lock()
+		s.bloqueado = false
+		s.mu.Unlock()
+	})
+	if err != nil {
+		return nil, "", dbus.MakeFailedError(err)
+	}
+	return []dbus.ObjectPath{}, caminho, nil
+}
+
+// novoPrompt exporta um prompt que, ao ser exibido, executa concluir e emite Completed
+// conforme a resposta configurada
+func (s *secretServiceFake) novoPrompt(concluir func()) (dbus.ObjectPath, error) {
+	s.mu.Lock()
+	s.proximo++
+	caminho := dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/secrets/prompt/p%d", s.proximo))
+	s.mu.Unlock()
+
+	if err := s.conn.ExportMethodTable(map[string]any{
+		"Prompt": func(janela string) *dbus.Error {
+			s.mu.Lock()
+			resposta := s.resposta
+			s.mu.Unlock()
+
+			switch resposta {
+			case promptConfirmado:
+				concluir()
+				go s.conn.Emit(caminho, secretsPromptIface+".Completed", false, dbus.MakeVariant(""))
+			case promptCancelado:
+				go s.conn.Emit(caminho, secretsPromptIface+".Completed", true, dbus.MakeVariant(""))
+			}
+			return nil
+		},
+		"Dismiss": func() *dbus.Error {
+			s.mu.Lock()
+			s.dispensas++
+			s.mu.Unlock()
+			return nil
+		},
+	}, caminho, secretsPromptIface); err != nil {
+		return "", err
+	}
+	return caminho, nil
+}
+
+func (c colecaoFake) CreateItem(propriedades map[string]dbus.Variant, s secret, substituir bool) (dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	var atributos map[string]string
+	if err := propriedades[secretsItemIface+".Attributes"].Store(&atributos); err != nil {
+		return "", "", dbus.MakeFailedError(err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.bloqueado {
+		return "", "", dbus.MakeFailedError(errors.New("coleção bloqueada"))
+	}
+	for caminho, item := range c.itens {
+		if substituir && item.atributos["item"] == atributos["item"] {
+			item.valor = s.Value
+			return caminho, "/", nil
+		}
+	}
+
+	c.proximo++
+	caminho := dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/secrets/collection/login/%d", c.proximo))
+	item := &itemFake{servico: c.secretServiceFake, caminho: caminho, atributos: atributos, valor: s.Value}
+	if err := c.conn.Export(item, caminho, secretsItemIface); err != nil {
+		return "", "", dbus.MakeFailedError(err)
+	}
+	c.itens[caminho] = item
+	return caminho, "/", nil
+}
+
+func (i *itemFake) GetSecret(sessao dbus.ObjectPath) (secret, *dbus.Error) {
+	i.servico.mu.Lock()
+	defer i.servico.mu.Unlock()
+	return secret{Session: sessao, Parameters: []byte{}, Value: i.valor, ContentType: "application/octet-stream"}, nil
+}
+
+func (i *itemFake) Delete() (dbus.ObjectPath, *dbus.Error) {
+	i.servico.mu.Lock()
+	defer i.servico.mu.Unlock()
+	delete(i.servico.itens, i.caminho)
+	return "/", nil
+}
+
+func (s *secretServiceFake) definir(bloqueado bool, resposta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bloqueado, s.resposta = bloqueado, resposta
+}
+
+func TestKeyringSegredos(t *testing.T) {
+	_, store := novoKeyringFake(t)
+
+	if _, err := store.LoadSecret(SecretSofttradeCookies); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("LoadSecret vazio = %v, esperado ErrSecretNotFound", err)
+	}
+	if err := store.SaveSecret(SecretSofttradeCookies, []byte("[]")); err != nil {
+		t.Fatalf("SaveSecret: %v", err)
+	}
+	if err := store.SaveSecret(SecretSofttradeCookies, []byte(`[{"name":"JSESSIONID"}]`)); err != nil {
+		t.Fatalf("SaveSecret substituindo: %v", err)
+	}
+	if valor, err := store.LoadSecret(SecretSofttradeCookies); err != nil || string(valor) != `[{"name":"JSESSIONID"}]` {
+		t.Fatalf("LoadSecret = %q, %v", valor, err)
+	}
+}
+
+func TestKeyringCredenciais(t *testing.T) {
+	_, store := novoKeyringFake(t)
+
+	creds := Credentials{Username: "usuario", Password: "senha"}
+	if err := store.Save(creds); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if obtidas, err := store.Load(); err != nil || obtidas != creds {
+		t.Fatalf("Load = %+v, %v", obtidas, err)
+	}
+
+	if err := store.Delete(); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.LoadSecret(SecretPassword); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("LoadSecret após Delete = %v, esperado ErrSecretNotFound", err)
+	}
+}
+
+func TestKeyringDesbloqueio(t *testing.T) {
+	servico, store := novoKeyringFake(t)
+	servico.definir(true, promptConfirmado)
+
+	if err := store.SaveSecret(SecretSlackCookies, []byte("cookies")); err != nil {
+		t.Fatalf("SaveSecret com o keyring bloqueado: %v", err)
+	}
+	if valor, err := store.LoadSecret(SecretSlackCookies); err != nil || string(valor) != "cookies" {
+		t.Fatalf("LoadSecret = %q, %v", valor, err)
+	}
+}
+
+func TestKeyringPromptCancelado(t *testing.T) {
+	servico, store := novoKeyringFake(t)
+	servico.definir(true, promptCancelado)
+
+	err := store.SaveSecret(SecretSlackCookies, []byte("cookies"))
+	if err == nil || !strings.Contains(err.Error(), "cancelada") {
+		t.Fatalf("SaveSecret com prompt cancelado = %v", err)
+	}
+}
+
+func TestKeyringPromptSemResposta(t *testing.T) {
+	servico, store := novoKeyringFake(t)
+	servico.definir(true, promptSemResposta)
+	store.PromptTimeout = 50 * time.Millisecond
+
+	if err := store.SaveSecret(SecretSlackCookies, []byte("cookies")); err == nil {
+		t.Fatal("SaveSecret com prompt sem resposta não falhou")
+	}
+
+	servico.mu.Lock()
+	defer servico.mu.Unlock()
+	if servico.dispensas != 1 {
+		t.Fatalf("prompt dispensado %d vezes, esperada 1", servico.dispensas)
+	}
+}
+
+func TestOpenCredentialStoreSemSecretService(t *testing.T) {
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path="+t.TempDir()+"/inexistente")
+
+	store, err := OpenCredentialStore(StoreKeyring, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("OpenCredentialStore: %v", err)
+	}
+	if _, ok := store.(*FileStore); !ok {
+		t.Fatalf("backend = %T, esperado *FileStore", store)
+	}
+}
@@ -3,21 +3,41 @@ package auth
 import (
 	"context"
 	"fmt"
-	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
 )
 
+// MockScenario scripts the MockSession so demos and tests are reproducible.
+// The zero value never fails and accepts any credentials except the user "invalid".
+type MockScenario struct {
+	// Seed initializes the random failure draw
+	Seed int64
+	// FailureRate is the chance (0 to 1) of each login timing out; 0 disables it
+	FailureRate float64
+	// Failures lists, by method name ("Login"), the call numbers that fail, starting at 1
+	Failures map[string][]int
+	// Username and Password, when set, are the only credentials accepted
+	Username string
+	Password string
+}
+
 // MockSession implements the Module interface for testing and development
 type MockSession struct {
-	ctx    context.Context
-	cancel context.CancelFunc
+	ctx      context.Context
+	cancel   context.CancelFunc
+	scenario MockScenario
+	failures *common.InjetorFalhas
 }
 
-// NewMockSession creates a new mock authentication session
-func NewMockSession() Module {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+// NewMockSession creates a new mock authentication session driven by scenario;
+// its timeout is measured on clock (nil uses the system clock)
+func NewMockSession(scenario MockScenario, clock common.Relogio) Module {
+	ctx, cancel := common.ComTempoLimite(context.Background(), clock, defaultTimeout)
 	return &MockSession{
-		ctx:    ctx,
-		cancel: cancel,
+		ctx:      ctx,
+		cancel:   cancel,
+		scenario: scenario,
+		failures: common.NovoInjetorFalhas(scenario.Seed, scenario.FailureRate, scenario.Failures),
 	}
 }
 
@@ -32,13 +52,16 @@ func (m *MockSession) Login(creds Credentials) error {
 		return err
 	}
 
-	// Simula credenciais inválidas para um usuário específico
+	// Simula credenciais inválidas para um usuário específico ou diferentes das do cenário
 	if creds.Username == "invalid" {
 		return ErrInvalidCredentials
 	}
+	if m.scenario.Username != "" && (creds.Username != m.scenario.Username || creds.Password != m.scenario.Password) {
+		return ErrInvalidCredentials
+	}
 
-	// Simula erro de timeout aleatoriamente (10% de chance)
-	if time.Now().UnixNano()%10 == 0 {
+	// Simula erro de timeout nas chamadas definidas pelo cenário
+	if m.failures.Falhar("Login") {
 		return &LoginError{
 			Type:    "timeout",
 			Message: "timeout: falha ao carregar página de login",
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+)
+
+// maxOTPAttempts limits the verification codes sent before giving up
+const maxOTPAttempts = 3
+
+// otpFields returns the verification code fields shown after the login, if any
+func (a *AuthSession) otpFields() ([]*cdp.Node, error) {
+	var nodes []*cdp.Node
+	if err := common.Executar(a.ctx, chromedp.Nodes(a.selectors.CodigoCampos, &nodes, chromedp.ByQueryAll, chromedp.AtLeast(0))); err != nil {
+		return nil, &LoginError{Type: "execution", Message: "falha ao verificar o campo de código de verificação", Cause: err}
+	}
+	return nodes, nil
+}
+
+// completeSecondFactor answers the verification code page, when Softtrade shows one, with a
+// code generated from the TOTP secret or typed by the user, and waits for the page after it
+func (a *AuthSession) completeSecondFactor() error {
+	campos, err := a.otpFields()
+	if err != nil || len(campos) == 0 {
+		return err
+	}
+
+	for tentativa := 1; tentativa <= maxOTPAttempts; tentativa++ {
+		codigo, err := a.otpCode(tentativa)
+		if err != nil {
+			return err
+		}
+		if err := a.submitOTP(campos, codigo); err != nil {
+			return err
+		}
+
+		// Aguarda o Softtrade validar o código
+		if err := common.Dormir(a.ctx, a.clock, 2*time.Second); err != nil {
+			return err
+		}
+
+		campos, err = a.otpFields()
+		if err != nil {
+			return err
+		}
+		if len(campos) == 0 {
+			return a.waitAfterLogin()
+		}
+
+		mensagem, err := a.readErrorMessage()
+		if err != nil {
+			return err
+		}
+		if loginErr := classifyLoginMessage(mensagem); loginErr != nil && loginErr != ErrInvalidOTP {
+			return loginErr
+		}
+		if mensagem != "" {
+			fmt.Printf("\n⚠️  Aviso: Código de verificação recusado: %s\n", mensagem)
+		}
+	}
+	return ErrInvalidOTP
+}
+
+// otpCode returns the code of the current TOTP window or asks the user for it. A generated
+// code refused by Softtrade is retried only in the next window.
+func (a *AuthSession) otpCode(tentativa int) (string, error) {
+	if a.totpSecret != "" {
+		params, err := parseTOTPSecret(a.totpSecret)
+		if err != nil {
+			return "", &LoginError{Type: "otp", Message: "segredo TOTP inválido", Cause: err}
+		}
+		if tentativa > 1 {
+			if err := common.Dormir(a.ctx, a.clock, params.nextWindow(a.clock.Agora())); err != nil {
+				return "", err
+			}
+		}
+		return params.code(a.clock.Agora()), nil
+	}
+
+	if a.otpPrompter == nil {
+		return "", ErrOTPRequired
+	}
+	codigo, err := a.otpPrompter.SolicitarCodigoVerificacao()
+	if err != nil {
+		return "", &LoginError{Type: "otp", Message: "código de verificação não informado", Cause: err}
+	}
+	return strings.TrimSpace(codigo), nil
+}
+
+// submitOTP fills the code and sends it. A single field receives the whole code; one field
+// per digit (maxlength 1) receives a digit each. The confirm button is clicked only when the
+// page has one, since some variants submit by themselves after the last digit.
+func (a *AuthSession) submitOTP(campos []*cdp.Node, codigo string) error {
+	porDigito := len(campos) >= len(codigo) && campos[0].AttributeValue("maxlength") == "1"
+
+	var acoes []chromedp.Action
+	if porDigito {
+		for i, digito := range codigo {
+			acoes = append(acoes, fillNode(campos[i], string(digito))...)
+		}
+	} else {
+		acoes = append(acoes, fillNode(campos[0], codigo)...)
+	}
+	if err := a.executeLoginStep(loginStep{actions: acoes, errMsg: "falha ao preencher o código de verificação"}); err != nil {
+		return err
+	}
+
+	var confirmar []*cdp.Node
+	if err := common.Executar(a.ctx, chromedp.Nodes(a.selectors.CodigoConfirmar, &confirmar, chromedp.ByQueryAll, chromedp.AtLeast(0))); err != nil {
+		return &LoginError{Type: "execution", Message: "falha ao procurar o botão de confirmação do código", Cause: err}
+	}
+	if len(confirmar) == 0 {
+		return nil
+	}
+	return a.executeLoginStep(loginStep{
+		actions: []chromedp.Action{common.Clicar([]cdp.NodeID{confirmar[0].NodeID}, chromedp.ByNodeID)},
+		errMsg:  "falha ao enviar o código de verificação",
+	})
+}
+
+// fillNode replaces the value of an input, clearing a previous attempt
+func fillNode(node *cdp.Node, valor string) []chromedp.Action {
+	id := []cdp.NodeID{node.NodeID}
+	return []chromedp.Action{
+		chromedp.Clear(id, chromedp.ByNodeID),
+		chromedp.Focus(id, chromedp.ByNodeID),
+		chromedp.SendKeys(id, valor, chromedp.ByNodeID),
+	}
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+)
+
+// PasswordChanger is implemented by sessions that can complete the password change
+// required by Softtrade after a login fails with ErrPasswordExpired or ErrPasswordChange
+type PasswordChanger interface {
+	ChangePassword(current, newPassword string) error
+}
+
+// passwordFields returns the visible password fields of the change form; the login page
+// has a single one
+func (a *AuthSession) passwordFields() ([]*cdp.Node, error) {
+	var nodes []*cdp.Node
+	if err := common.Executar(a.ctx, chromedp.Nodes(a.selectors.TrocaSenhaCampos, &nodes, chromedp.ByQueryAll, chromedp.AtLeast(0))); err != nil {
+		return nil, &LoginError{Type: "execution", Message: "falha ao verificar o formulário de troca de senha", Cause: err}
+	}
+	return nodes, nil
+}
+
+// checkForPasswordChange returns ErrPasswordChange when the page after login is the password
+// change form instead of the clock-in page
+func (a *AuthSession) checkForPasswordChange() error {
+	var marcacao bool
+	if err := common.Executar(a.ctx, chromedp.Evaluate(fmt.Sprintf(`document.querySelector(%s) !== null`, selectors.JS(a.selectors.Formulario)), &marcacao)); err != nil {
+		return &LoginError{Type: "execution", Message: "falha ao verificar a página após login", Cause: err}
+	}
+	if marcacao {
+		return nil
+	}
+
+	campos, err := a.passwordFields()
+	if err != nil {
+		return err
+	}
+	if len(campos) >= 2 {
+		if loginErr := a.checkForLoginErrors(); loginErr != nil {
+			return loginErr
+		}
+		return ErrPasswordChange
+	}
+	return nil
+}
+
+// ChangePassword fills the password change form shown after the login. With three fields
+// they are taken as current, new and confirmation; with two, as new and confirmation.
+func (a *AuthSession) ChangePassword(current, newPassword string) error {
+	if newPassword == "" {
+		return &LoginError{Type: "validation", Message: "a nova senha é obrigatória"}
+	}
+	defer a.limit()()
+
+	campos, err := a.passwordFields()
+	if err != nil {
+		return err
+	}
+
+	var valores []string
+	switch {
+	case len(campos) >= 3:
+		valores = []string{current, newPassword, newPassword}
+	case len(campos) == 2:
+		valores = []string{newPassword, newPassword}
+	default:
+		return ErrPasswordFormAbsent
+	}
+
+	var acoes []chromedp.Action
+	for i, valor := range valores {
+		acoes = append(acoes,
+			chromedp.Focus([]cdp.NodeID{campos[i].NodeID}, chromedp.ByNodeID),
+			chromedp.SendKeys([]cdp.NodeID{campos[i].NodeID}, valor, chromedp.ByNodeID),
+		)
+	}
+	acoes = append(acoes,
+		chromedp.WaitVisible(a.selectors.TrocaSenhaSalvar, chromedp.ByQuery),
+		common.Clicar(a.selectors.TrocaSenhaSalvar, chromedp.ByQuery),
+	)
+	if err := a.executeLoginStep(loginStep{actions: acoes, errMsg: "falha ao enviar a troca de senha"}); err != nil {
+		return err
+	}
+
+	// Aguarda o Softtrade processar a troca
+	if err := common.Dormir(a.ctx, a.clock, 2*time.Second); err != nil {
+		return err
+	}
+
+	// Continuar no formulário indica que a troca foi recusada (senha fraca, repetida, ...)
+	campos, err = a.passwordFields()
+	if err != nil {
+		return err
+	}
+	if len(campos) >= 2 {
+		mensagem, err := a.readErrorMessage()
+		if err != nil {
+			return err
+		}
+		if mensagem == "" {
+			mensagem = "o formulário continua aberto"
+		}
+		return &LoginError{Type: "password_change", Message: "troca de senha recusada", Cause: errors.New(mensagem)}
+	}
+	return nil
+}
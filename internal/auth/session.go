@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+)
+
+// sessionCheckTimeout limits the wait for the page that tells whether the saved session is valid
+const sessionCheckTimeout = 30 * time.Second
+
+// savedSession is the Softtrade session stored under SecretSofttradeCookies
+type savedSession struct {
+	Username string            `json:"usuario"`
+	SavedAt  time.Time         `json:"salva_em"`
+	Cookies  []*network.Cookie `json:"cookies"`
+}
+
+// restoreSession loads the saved cookies of the user and reports whether they still open
+// the clock-in page (the form a.selectors.Formulario) instead of the login form. Cookies
+// that do not are discarded, so the form login starts from a clean session.
+func (a *AuthSession) restoreSession(username string) bool {
+	if a.store == nil {
+		return false
+	}
+
+	dados, err := a.store.LoadSecret(SecretSofttradeCookies)
+	if err != nil {
+		if !errors.Is(err, ErrSecretNotFound) {
+			fmt.Printf("\n⚠️  Aviso: Não foi possível ler a sessão salva do Softtrade: %v\n", err)
+		}
+		return false
+	}
+
+	var sessao savedSession
+	if err := json.Unmarshal(dados, &sessao); err != nil || sessao.Username != username || len(sessao.Cookies) == 0 {
+		a.discardSession()
+		return false
+	}
+
+	ctx, cancel := common.ComTempoLimite(a.ctx, a.clock, sessionCheckTimeout)
+	defer cancel()
+
+	var logado bool
+	err = common.Executar(ctx,
+		network.SetCookies(cookieParams(sessao.Cookies, a.clock.Agora())),
+		chromedp.Navigate(a.baseURL),
+		chromedp.WaitReady("body"),
+		chromedp.WaitVisible(a.selectors.Formulario+", "+a.selectors.LoginUsuario, chromedp.ByQuery),
+		chromedp.Evaluate(fmt.Sprintf(`document.querySelector(%s) !== null`, selectors.JS(a.selectors.Formulario)), &logado),
+	)
+	if err == nil && logado {
+		return true
+	}
+	a.discardSession()
+	return false
+}
+
+// discardSession deletes the saved cookies and removes the Softtrade cookies from the
+// browser, where the failed restore left them
+func (a *AuthSession) discardSession() {
+	if err := a.store.DeleteSecret(SecretSofttradeCookies); err != nil {
+		fmt.Printf("\n⚠️  Aviso: Não foi possível apagar a sessão expirada do Softtrade: %v\n", err)
+	}
+
+	err := common.Executar(a.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		cookies, err := network.GetCookies().WithUrls([]string{a.baseURL}).Do(ctx)
+		if err != nil {
+			return err
+		}
+		for _, cookie := range cookies {
+			if err := network.DeleteCookies(cookie.Name).WithDomain(cookie.Domain).WithPath(cookie.Path).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	if err != nil {
+		fmt.Printf("\n⚠️  Aviso: Não foi possível limpar os cookies do Softtrade no navegador: %v\n", err)
+	}
+}
+
+// saveSession stores the cookies of the logged-in session for the next runs
+func (a *AuthSession) saveSession(username string) error {
+	if a.store == nil {
+		return nil
+	}
+
+	var cookies []*network.Cookie
+	err := common.Executar(a.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookies, err = network.GetCookies().WithUrls([]string{a.baseURL}).Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return fmt.Errorf("erro ao obter cookies do Softtrade: %w", err)
+	}
+
+	dados, err := json.Marshal(savedSession{Username: username, SavedAt: a.clock.Agora(), Cookies: cookies})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar sessão do Softtrade: %w", err)
+	}
+	return a.store.SaveSecret(SecretSofttradeCookies, dados)
+}
+
+// cookieParams converts the saved cookies back into parameters for Network.setCookies,
+// skipping the expired ones
+func cookieParams(cookies []*network.Cookie, now time.Time) []*network.CookieParam {
+	var params []*network.CookieParam
+	for _, cookie := range cookies {
+		param := &network.CookieParam{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HTTPOnly: cookie.HTTPOnly,
+			SameSite: cookie.SameSite,
+		}
+		if !cookie.Session && cookie.Expires > 0 {
+			expires := time.Unix(0, int64(cookie.Expires*float64(time.Second)))
+			if !expires.After(now) {
+				continue
+			}
+			epoch := cdp.TimeSinceEpoch(expires)
+			param.Expires = &epoch
+		}
+		if strings.TrimSpace(param.Name) != "" {
+			params = append(params, param)
+		}
+	}
+	return params
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Environment variables read by EnvSource, the same keys of the .env file
+const (
+	EnvUsername = "USERNAME_PONTO"
+	EnvPassword = "PASSWORD_PONTO"
+)
+
+// DefaultSourceTimeout limits credential commands and stdin reads without a configured timeout
+const DefaultSourceTimeout = 10 * time.Second
+
+// CredentialSource provides the credentials without asking the user
+type CredentialSource interface {
+	// Name identifies the source in messages
+	Name() string
+
+	// Credentials returns the credentials or ErrCredenciaisNaoEncontradas when the source
+	// has none to offer
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// EnvSource reads USERNAME_PONTO and PASSWORD_PONTO from the environment
+type EnvSource struct{}
+
+func (EnvSource) Name() string {
+	return "variáveis de ambiente"
+}
+
+func (EnvSource) Credentials(context.Context) (Credentials, error) {
+	creds := Credentials{Username: os.Getenv(EnvUsername), Password: os.Getenv(EnvPassword)}
+	if creds.validate() != nil {
+		return Credentials{}, ErrCredenciaisNaoEncontradas
+	}
+	return creds, nil
+}
+
+// CommandSource runs a shell command, such as "pass show softtrade", that prints the
+// password on the first line and, optionally, the username on a "usuario:", "user:",
+// "username:" or "login:" line, following the pass convention
+type CommandSource struct {
+	Command string
+	// Username is used when the output has no username line
+	Username string
+	// Timeout limits the command; zero uses DefaultSourceTimeout
+	Timeout time.Duration
+}
+
+func (c CommandSource) Name() string {
+	return fmt.Sprintf("comando %q", c.Command)
+}
+
+func (c CommandSource) Credentials(ctx context.Context) (Credentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeoutOrDefault(c.Timeout))
+	defer cancel()
+
+	var saida, erros bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	cmd.Stdout = &saida
+	cmd.Stderr = &erros
+	// Filhos do shell (como o gpg do pass) podem manter a saída aberta após o tempo limite
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return Credentials{}, fmt.Errorf("tempo limite de %s esgotado", timeoutOrDefault(c.Timeout))
+		}
+		if detalhe := strings.TrimSpace(erros.String()); detalhe != "" {
+			return Credentials{}, fmt.Errorf("%w: %s", err, detalhe)
+		}
+		return Credentials{}, err
+	}
+
+	return parseSecretOutput(saida.String(), c.Username)
+}
+
+// StdinSource reads the credentials piped to the program: the username and the password
+// on separate lines or, with Username set, only the password. It offers nothing when
+// stdin is a terminal.
+type StdinSource struct {
+	Username string
+	// Timeout limits the read; zero uses DefaultSourceTimeout
+	Timeout time.Duration
+}
+
+func (StdinSource) Name() string {
+	return "entrada padrão"
+}
+
+func (s StdinSource) Credentials(ctx context.Context) (Credentials, error) {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice != 0 {
+		return Credentials{}, ErrCredenciaisNaoEncontradas
+	}
+
+	linhas := 2
+	if s.Username != "" {
+		linhas = 1
+	}
+
+	type leitura struct {
+		valores []string
+		err     error
+	}
+	resultado := make(chan leitura, 1)
+	go func() {
+		var valores []string
+		scanner := bufio.NewScanner(os.Stdin)
+		for len(valores) < linhas && scanner.Scan() {
+			valores = append(valores, strings.TrimRight(scanner.Text(), "\r"))
+		}
+		resultado <- leitura{valores, scanner.Err()}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, timeoutOrDefault(s.Timeout))
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		return Credentials{}, fmt.Errorf("tempo limite de %s esgotado", timeoutOrDefault(s.Timeout))
+	case r := <-resultado:
+		if r.err != nil && !errors.Is(r.err, io.EOF) {
+			return Credentials{}, r.err
+		}
+		if len(r.valores) < linhas {
+			return Credentials{}, ErrCredenciaisNaoEncontradas
+		}
+		creds := Credentials{Username: s.Username, Password: r.valores[linhas-1]}
+		if s.Username == "" {
+			creds.Username = r.valores[0]
+		}
+		if creds.validate() != nil {
+			return Credentials{}, ErrCredenciaisNaoEncontradas
+		}
+		return creds, nil
+	}
+}
+
+// StoreSource loads the credentials saved in a CredentialStore
+type StoreSource struct {
+	Store CredentialStore
+}
+
+func (s StoreSource) Name() string {
+	return s.Store.Name()
+}
+
+func (s StoreSource) Credentials(context.Context) (Credentials, error) {
+	return s.Store.Load()
+}
+
+// parseSecretOutput reads the password from the first line and the username from a
+// "usuario:"/"user:"/"username:"/"login:" line
+func parseSecretOutput(saida, username string) (Credentials, error) {
+	linhas := strings.Split(strings.ReplaceAll(saida, "\r\n", "\n"), "\n")
+	creds := Credentials{Username: username, Password: linhas[0]}
+
+	for _, linha := range linhas[1:] {
+		chave, valor, ok := strings.Cut(linha, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(chave)) {
+		case "usuario", "usuário", "user", "username", "login":
+			creds.Username = strings.TrimSpace(valor)
+		}
+	}
+
+	if creds.Password == "" {
+		return Credentials{}, errors.New("a primeira linha da saída (senha) está vazia")
+	}
+	if creds.Username == "" {
+		return Credentials{}, errors.New("a saída não tem uma linha \"usuario: <nome>\" e credenciais.usuario não foi configurado")
+	}
+	return creds, nil
+}
+
+func timeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return DefaultSourceTimeout
+	}
+	return timeout
+}
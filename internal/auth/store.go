@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/cofre"
+	"github.com/joho/godotenv"
+)
+
+// Storage backends accepted by OpenCredentialStore
+const (
+	StoreAuto    = "auto"
+	StoreFile    = "arquivo"
+	StoreVault   = "cofre"
+	StoreKeyring = "keyring"
+)
+
+// Secret keys shared by all backends
+const (
+	SecretUsername     = cofre.ItemUsuarioPonto
+	SecretPassword     = cofre.ItemSenhaPonto
+	SecretSlackCookies = cofre.ItemCookiesSlack
+
+	// SecretSofttradeCookies holds the Softtrade browser session of the last login
+	SecretSofttradeCookies = "softtrade_cookies"
+)
+
+// ErrSecretNotFound indicates that the store holds no secret under the key
+var ErrSecretNotFound = errors.New("segredo não encontrado")
+
+// CredentialStore persists the Softtrade credentials and other session secrets, such as
+// the Slack cookies
+type CredentialStore interface {
+	// Name identifies the backend in messages
+	Name() string
+
+	// Load returns the saved credentials or ErrCredenciaisNaoEncontradas
+	Load() (Credentials, error)
+
+	// Save replaces the saved credentials
+	Save(creds Credentials) error
+
+	// Delete removes the saved credentials; deleting missing credentials is not an error
+	Delete() error
+
+	// LoadSecret returns the secret stored under key or ErrSecretNotFound
+	LoadSecret(key string) ([]byte, error)
+
+	// SaveSecret stores or replaces the secret under key
+	SaveSecret(key string, value []byte) error
+
+	// DeleteSecret removes the secret under key; deleting a missing secret is not an error
+	DeleteSecret(key string) error
+}
+
+// OpenCredentialStore returns the backend named in the configuration. "auto" (or empty)
+// uses the vault when it exists and the files otherwise; "keyring" falls back to the files
+// when no Secret Service is available. vault must be unlocked for the vault backend.
+func OpenCredentialStore(backend, configDir string, vault *cofre.Cofre) (CredentialStore, error) {
+	switch backend {
+	case "", StoreAuto:
+		if vault.Existe() {
+			return NewVaultStore(vault), nil
+		}
+		return NewFileStore(configDir), nil
+	case StoreFile:
+		return NewFileStore(configDir), nil
+	case StoreVault:
+		if !vault.Existe() {
+			return nil, cofre.ErrInexistente
+		}
+		return NewVaultStore(vault), nil
+	case StoreKeyring:
+		store, err := ConnectKeyring()
+		if err != nil {
+			fmt.Printf("\n⚠️  Aviso: Secret Service indisponível (%v); usando arquivos em %s\n", err, configDir)
+			return NewFileStore(configDir), nil
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("armazenamento de credenciais desconhecido %q (use %s, %s, %s ou %s)",
+			backend, StoreAuto, StoreFile, StoreVault, StoreKeyring)
+	}
+}
+
+// loadCredentials reads the username and password secrets of a key/value backend
+func loadCredentials(store CredentialStore) (Credentials, error) {
+	username, err := store.LoadSecret(SecretUsername)
+	if err == nil {
+		var password []byte
+		password, err = store.LoadSecret(SecretPassword)
+		if err == nil {
+			return Credentials{Username: string(username), Password: string(password)}, nil
+		}
+	}
+	if errors.Is(err, ErrSecretNotFound) {
+		return Credentials{}, ErrCredenciaisNaoEncontradas
+	}
+	return Credentials{}, fmt.Errorf("erro ao ler credenciais (%s): %w", store.Name(), err)
+}
+
+// saveCredentials writes the username and password secrets of a key/value backend
+func saveCredentials(store CredentialStore, creds Credentials) error {
+	if err := store.SaveSecret(SecretUsername, []byte(creds.Username)); err != nil {
+		return fmt.Errorf("erro ao salvar credenciais (%s): %w", store.Name(), err)
+	}
+	if err := store.SaveSecret(SecretPassword, []byte(creds.Password)); err != nil {
+		return fmt.Errorf("erro ao salvar credenciais (%s): %w", store.Name(), err)
+	}
+	return nil
+}
+
+// FileStore keeps the credentials in <configDir>/.env and each secret in <configDir>/<key>.json,
+// unencrypted, readable only by the user
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns the file backend rooted at configDir
+func NewFileStore(configDir string) *FileStore {
+	return &FileStore{dir: configDir}
+}
+
+func (f *FileStore) Name() string {
+	return "arquivo " + filepath.Join(f.dir, envFileName)
+}
+
+func (f *FileStore) Load() (Credentials, error) {
+	env, err := godotenv.Read(filepath.Join(f.dir, envFileName))
+	if err != nil || env[EnvUsername] == "" || env[EnvPassword] == "" {
+		return Credentials{}, ErrCredenciaisNaoEncontradas
+	}
+	return Credentials{Username: env[EnvUsername], Password: env[EnvPassword]}, nil
+}
+
+func (f *FileStore) Save(creds Credentials) error {
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório de configuração: %w", err)
+	}
+	env := map[string]string{EnvUsername: creds.Username, EnvPassword: creds.Password}
+	conteudo, err := godotenv.Marshal(env)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar credenciais: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(f.dir, envFileName), []byte(conteudo+"\n"), 0600); err != nil {
+		return fmt.Errorf("erro ao salvar credenciais: %w", err)
+	}
+	return nil
+}
+
+func (f *FileStore) Delete() error {
+	if err := os.Remove(filepath.Join(f.dir, envFileName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("erro ao apagar credenciais: %w", err)
+	}
+	return nil
+}
+
+func (f *FileStore) LoadSecret(key string) ([]byte, error) {
+	dados, err := os.ReadFile(filepath.Join(f.dir, key+".json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrSecretNotFound
+		}
+		return nil, fmt.Errorf("erro ao ler %s: %w", key, err)
+	}
+	return dados, nil
+}
+
+func (f *FileStore) SaveSecret(key string, value []byte) error {
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório de configuração: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(f.dir, key+".json"), value, 0600); err != nil {
+		return fmt.Errorf("erro ao salvar %s: %w", key, err)
+	}
+	return nil
+}
+
+func (f *FileStore) DeleteSecret(key string) error {
+	if err := os.Remove(filepath.Join(f.dir, key+".json")); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("erro ao apagar %s: %w", key, err)
+	}
+	return nil
+}
+
+// VaultStore keeps the credentials and secrets in the encrypted vault
+type VaultStore struct {
+	vault *cofre.Cofre
+}
+
+// NewVaultStore returns the backend over an unlocked vault
+func NewVaultStore(vault *cofre.Cofre) *VaultStore {
+	return &VaultStore{vault: vault}
+}
+
+func (v *VaultStore) Name() string {
+	return "cofre " + v.vault.Caminho()
+}
+
+func (v *VaultStore) Load() (Credentials, error) {
+	return loadCredentials(v)
+}
+
+func (v *VaultStore) Save(creds Credentials) error {
+	return saveCredentials(v, creds)
+}
+
+func (v *VaultStore) Delete() error {
+	for _, key := range []string{SecretUsername, SecretPassword} {
+		if err := v.DeleteSecret(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *VaultStore) LoadSecret(key string) ([]byte, error) {
+	dados, err := v.vault.Obter(key)
+	if errors.Is(err, cofre.ErrItemNaoEncontrado) {
+		return nil, ErrSecretNotFound
+	}
+	return dados, err
+}
+
+func (v *VaultStore) SaveSecret(key string, value []byte) error {
+	return v.vault.Definir(key, value)
+}
+
+func (v *VaultStore) DeleteSecret(key string) error {
+	if err := v.vault.Remover(key); err != nil {
+		return fmt.Errorf("erro ao apagar %s do cofre: %w", key, err)
+	}
+	return nil
+}
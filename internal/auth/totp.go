@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults of RFC 6238 and of otpauth URIs without digits, period or algorithm
+const (
+	totpPeriod = 30 * time.Second
+	totpDigits = 6
+)
+
+// SecretTOTP holds the base32 secret of the Softtrade second factor
+const SecretTOTP = "totp_secret"
+
+// OTPPrompter asks the user for the verification code when there is no TOTP secret
+type OTPPrompter interface {
+	SolicitarCodigoVerificacao() (string, error)
+}
+
+// OTPPrompterFunc adapts a function to OTPPrompter
+type OTPPrompterFunc func() (string, error)
+
+func (f OTPPrompterFunc) SolicitarCodigoVerificacao() (string, error) {
+	return f()
+}
+
+// totpParams are the key and the otpauth parameters of a TOTP secret
+type totpParams struct {
+	key    []byte
+	digits int
+	period time.Duration
+	hash   func() hash.Hash
+}
+
+// totpAlgorithms maps the otpauth algorithm parameter to the HMAC hash (RFC 6238, section 1.2)
+var totpAlgorithms = map[string]func() hash.Hash{
+	"SHA1":   sha1.New,
+	"SHA256": sha256.New,
+	"SHA512": sha512.New,
+}
+
+// TOTPCode returns the RFC 6238 code of the secret at t. The secret is the base32 text shown
+// by the portal (HMAC-SHA1, 30 seconds, 6 digits) or an otpauth:// URI, whose digits,
+// period and algorithm parameters are honored.
+func TOTPCode(secret string, t time.Time) (string, error) {
+	params, err := parseTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return params.code(t), nil
+}
+
+// code returns the code of the window that contains t
+func (p totpParams) code(t time.Time) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/int64(p.period/time.Second)))
+	mac := hmac.New(p.hash, p.key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Truncamento dinâmico (RFC 4226, seção 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	modulus := uint32(1)
+	for range p.digits {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", p.digits, value%modulus)
+}
+
+// nextWindow returns how long until the code generated at t changes
+func (p totpParams) nextWindow(t time.Time) time.Duration {
+	return p.period - time.Duration(t.UnixNano()%int64(p.period))
+}
+
+// parseTOTPSecret decodes the secret and the otpauth parameters, rejecting values that
+// authenticator apps do not support: 6 to 8 digits, a period in whole seconds and SHA1,
+// SHA256 or SHA512
+func parseTOTPSecret(secret string) (totpParams, error) {
+	params := totpParams{digits: totpDigits, period: totpPeriod, hash: sha1.New}
+
+	secret = strings.TrimSpace(secret)
+	if strings.HasPrefix(secret, "otpauth://") {
+		uri, err := url.Parse(secret)
+		if err != nil {
+			return totpParams{}, fmt.Errorf("URI otpauth inválida: %w", err)
+		}
+		if uri.Host != "totp" {
+			return totpParams{}, fmt.Errorf("URI otpauth do tipo %q não suportada; use totp", uri.Host)
+		}
+		query := uri.Query()
+		secret = query.Get("secret")
+
+		if value := query.Get("digits"); value != "" {
+			digits, err := strconv.Atoi(value)
+			if err != nil || digits < 6 || digits > 8 {
+				return totpParams{}, fmt.Errorf("número de dígitos TOTP %q não suportado; use de 6 a 8", value)
+			}
+			params.digits = digits
+		}
+		if value := query.Get("period"); value != "" {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds <= 0 {
+				return totpParams{}, fmt.Errorf("período TOTP %q inválido; use um número de segundos", value)
+			}
+			params.period = time.Duration(seconds) * time.Second
+		}
+		if value := query.Get("algorithm"); value != "" {
+			newHash, ok := totpAlgorithms[strings.ToUpper(value)]
+			if !ok {
+				return totpParams{}, fmt.Errorf("algoritmo TOTP %q não suportado; use SHA1, SHA256 ou SHA512", value)
+			}
+			params.hash = newHash
+		}
+	}
+
+	secret = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(secret))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return totpParams{}, errors.New("segredo TOTP vazio")
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return totpParams{}, fmt.Errorf("segredo TOTP não está em base32: %w", err)
+	}
+	params.key = key
+	return params, nil
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// TestTOTPCodeRFC6238 confere os vetores de teste do apêndice B do RFC 6238
+func TestTOTPCodeRFC6238(t *testing.T) {
+	sementes := map[string]string{
+		"SHA1":   "12345678901234567890",
+		"SHA256": "12345678901234567890123456789012",
+		"SHA512": "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+	vetores := []struct {
+		instante int64
+		codigos  map[string]string
+	}{
+		{59, map[string]string{"SHA1": "94287082", "SHA256": "46119246", "SHA512": "90693936"}},
+		{1111111109, map[string]string{"SHA1": "07081804", "SHA256": "68084774", "SHA512": "25091201"}},
+		{1111111111, map[string]string{"SHA1": "14050471", "SHA256": "67062674", "SHA512": "99943326"}},
+		{1234567890, map[string]string{"SHA1": "89005924", "SHA256": "91819424", "SHA512": "93441116"}},
+		{2000000000, map[string]string{"SHA1": "69279037", "SHA256": "90698825", "SHA512": "38618901"}},
+		{20000000000, map[string]string{"SHA1": "65353130", "SHA256": "77737706", "SHA512": "47863826"}},
+	}
+
+	for algoritmo, semente := range sementes {
+		segredo := base32.StdEncoding.EncodeToString([]byte(semente))
+		uri := "otpauth://totp/Softtrade:usuario?secret=" + segredo + "&digits=8&period=30&algorithm=" + algoritmo
+		for _, v := range vetores {
+			codigo, err := TOTPCode(uri, time.Unix(v.instante, 0))
+			if err != nil {
+				t.Fatalf("TOTPCode(%s, %d): %v", algoritmo, v.instante, err)
+			}
+			if codigo != v.codigos[algoritmo] {
+				t.Errorf("TOTPCode(%s, %d) = %s, esperado %s", algoritmo, v.instante, codigo, v.codigos[algoritmo])
+			}
+		}
+	}
+
+	// Sem parâmetros valem HMAC-SHA1, 30 segundos e 6 dígitos: os últimos 6 do vetor
+	segredo := base32.StdEncoding.EncodeToString([]byte(sementes["SHA1"]))
+	if codigo, err := TOTPCode(segredo, time.Unix(59, 0)); err != nil || codigo != "287082" {
+		t.Fatalf("TOTPCode sem parâmetros = %s, %v; esperado 287082", codigo, err)
+	}
+}
+
+func TestTOTPParametrosNaoSuportados(t *testing.T) {
+	segredo := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for _, parametros := range []string{"digits=10", "digits=4", "period=0", "period=abc", "algorithm=MD5"} {
+		uri := "otpauth://totp/Softtrade?secret=" + segredo + "&" + parametros
+		if _, err := TOTPCode(uri, time.Unix(59, 0)); err == nil {
+			t.Errorf("TOTPCode com %s foi aceito", parametros)
+		}
+	}
+	if _, err := TOTPCode("otpauth://hotp/Softtrade?secret="+segredo+"&counter=0", time.Unix(59, 0)); err == nil {
+		t.Error("URI hotp foi aceita")
+	}
+}
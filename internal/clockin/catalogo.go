@@ -0,0 +1,283 @@
+package clockin
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Modalidade indica se o trabalho na localização é remoto ou presencial
+type Modalidade string
+
+const (
+	ModalidadeRemota     Modalidade = "remota"
+	ModalidadePresencial Modalidade = "presencial"
+)
+
+// LocalizacaoConhecida é uma entrada do catálogo de localizações
+type LocalizacaoConhecida struct {
+	// Nome é o nome canônico da localização
+	Nome string `json:"nome"`
+	// Apelidos são outros rótulos exibidos pelo sistema de ponto para a mesma localização
+	Apelidos []string `json:"apelidos,omitempty"`
+	// Modalidade define o status do Slack na entrada; vazio equivale a presencial
+	Modalidade Modalidade `json:"modalidade,omitempty"`
+}
+
+// Remota indica se a localização é de trabalho remoto
+func (l LocalizacaoConhecida) Remota() bool {
+	return l.Modalidade == ModalidadeRemota
+}
+
+// Catalogo reconhece os rótulos de localização independentemente de maiúsculas, acentos
+// e espaços, resolvendo apelidos para o nome canônico
+type Catalogo struct {
+	entradas []LocalizacaoConhecida
+	indice   map[string]int
+}
+
+// localizacoesPadrao formam o catálogo usado quando nenhum é configurado
+var localizacoesPadrao = []LocalizacaoConhecida{
+	{Nome: "Home Office", Apelidos: []string{"Remoto", "Teletrabalho"}, Modalidade: ModalidadeRemota},
+}
+
+// CatalogoPadrao retorna o catálogo embutido
+func CatalogoPadrao() *Catalogo {
+	catalogo, _ := NovoCatalogo(nil)
+	return catalogo
+}
+
+// NovoCatalogo cria um catálogo com as entradas informadas. As entradas padrão cujo nome
+// não foi redefinido são mantidas. Rótulos repetidos entre entradas diferentes são um erro.
+func NovoCatalogo(entradas []LocalizacaoConhecida) (*Catalogo, error) {
+	catalogo := &Catalogo{indice: make(map[string]int)}
+
+	for _, entrada := range entradas {
+		if strings.TrimSpace(entrada.Nome) == "" {
+			return nil, fmt.Errorf("localização sem nome no catálogo")
+		}
+		switch entrada.Modalidade {
+		case "", ModalidadeRemota, ModalidadePresencial:
+		default:
+			return nil, fmt.Errorf("localização %q: modalidade inválida %q (use %q ou %q)",
+				entrada.Nome, entrada.Modalidade, ModalidadeRemota, ModalidadePresencial)
+		}
+		if err := catalogo.adicionar(entrada); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, entrada := range localizacoesPadrao {
+		if _, redefinida := catalogo.indice[NormalizarLocalizacao(entrada.Nome)]; redefinida {
+			continue
+		}
+		if err := catalogo.adicionar(entrada); err != nil {
+			return nil, err
+		}
+	}
+	return catalogo, nil
+}
+
+func (c *Catalogo) adicionar(entrada LocalizacaoConhecida) error {
+	posicao := len(c.entradas)
+	for _, rotulo := range append([]string{entrada.Nome}, entrada.Apelidos...) {
+		chave := NormalizarLocalizacao(rotulo)
+		if existente, ok := c.indice[chave]; ok && existente != posicao {
+			return fmt.Errorf("rótulo %q aparece em %q e %q no catálogo de localizações",
+				rotulo, c.entradas[existente].Nome, entrada.Nome)
+		}
+		c.indice[chave] = posicao
+	}
+	c.entradas = append(c.entradas, entrada)
+	return nil
+}
+
+// NormalizarLocalizacao remove acentos, converte para minúsculas e compacta os espaços
+func NormalizarLocalizacao(rotulo string) string {
+	semAcentos, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), rotulo)
+	if err != nil {
+		semAcentos = rotulo
+	}
+	return strings.Join(strings.Fields(strings.ToLower(semAcentos)), " ")
+}
+
+// funcaoNormalizar define a função JS normalizar(texto), equivalente a NormalizarLocalizacao
+// para os rótulos comparados na página
+const funcaoNormalizar = `
+	function normalizar(texto) {
+		return texto.normalize('NFD').replace(/\p{Mn}/gu, '').normalize('NFC')
+			.toLowerCase().split(/\s+/).filter(Boolean).join(' ');
+	}
+`
+
+// distanciaMaxima é o número máximo de edições (letra inserida, removida ou trocada) entre um
+// rótulo e o catálogo aceito pela busca aproximada
+const distanciaMaxima = 2
+
+// Buscar retorna a entrada do catálogo correspondente ao rótulo, pelo nome ou por um apelido.
+// Sem correspondência exata, aceita o rótulo do catálogo mais próximo, desde que uma única
+// entrada esteja a essa distância.
+func (c *Catalogo) Buscar(rotulo string) (LocalizacaoConhecida, bool) {
+	if c == nil {
+		c = CatalogoPadrao()
+	}
+	chave := NormalizarLocalizacao(rotulo)
+	if posicao, ok := c.indice[chave]; ok {
+		return c.entradas[posicao], true
+	}
+	if proximas := c.aproximar(chave); len(proximas) == 1 {
+		return c.entradas[proximas[0]], true
+	}
+	return LocalizacaoConhecida{}, false
+}
+
+// Candidatas retorna os nomes das entradas igualmente próximas do rótulo quando a busca
+// aproximada não consegue escolher entre elas, e nil nos demais casos
+func (c *Catalogo) Candidatas(rotulo string) []string {
+	if c == nil {
+		c = CatalogoPadrao()
+	}
+	chave := NormalizarLocalizacao(rotulo)
+	if _, ok := c.indice[chave]; ok {
+		return nil
+	}
+	proximas := c.aproximar(chave)
+	if len(proximas) < 2 {
+		return nil
+	}
+	nomes := make([]string, len(proximas))
+	for i, posicao := range proximas {
+		nomes[i] = c.entradas[posicao].Nome
+	}
+	return nomes
+}
+
+// aproximar retorna, em ordem, as entradas com o rótulo mais próximo da chave normalizada.
+// O limite é distanciaMaxima edições, reduzido a uma edição a cada cinco letras da chave para
+// que rótulos curtos como "RJ" e "SP" não se confundam.
+func (c *Catalogo) aproximar(chave string) []int {
+	limite := min(distanciaMaxima, utf8.RuneCountInString(chave)/5)
+	melhor := limite + 1
+	var posicoes []int
+	for rotulo, posicao := range c.indice {
+		distancia := distanciaEdicao(chave, rotulo)
+		switch {
+		case distancia > limite || distancia > melhor:
+			continue
+		case distancia < melhor:
+			melhor, posicoes = distancia, nil
+		}
+		if !slices.Contains(posicoes, posicao) {
+			posicoes = append(posicoes, posicao)
+		}
+	}
+	slices.Sort(posicoes)
+	return posicoes
+}
+
+// distanciaEdicao calcula a distância de Levenshtein entre a e b, contada em letras
+func distanciaEdicao(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	anterior := make([]int, len(rb)+1)
+	atual := make([]int, len(rb)+1)
+	for j := range anterior {
+		anterior[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		atual[0] = i
+		for j := 1; j <= len(rb); j++ {
+			troca := anterior[j-1]
+			if ra[i-1] != rb[j-1] {
+				troca++
+			}
+			atual[j] = min(anterior[j]+1, atual[j-1]+1, troca)
+		}
+		anterior, atual = atual, anterior
+	}
+	return anterior[len(rb)]
+}
+
+// buscarExata retorna a entrada cujo nome ou apelido é o rótulo, sem busca aproximada
+func (c *Catalogo) buscarExata(rotulo string) (LocalizacaoConhecida, bool) {
+	if c == nil {
+		c = CatalogoPadrao()
+	}
+	posicao, ok := c.indice[NormalizarLocalizacao(rotulo)]
+	if !ok {
+		return LocalizacaoConhecida{}, false
+	}
+	return c.entradas[posicao], true
+}
+
+// Equivalentes indica se os rótulos designam a mesma localização: mesmo texto normalizado
+// ou nome e apelido da mesma entrada do catálogo. A busca aproximada não vale aqui, pois
+// dois rótulos parecidos da tabela podem ser localizações diferentes.
+func (c *Catalogo) Equivalentes(a, b string) bool {
+	if NormalizarLocalizacao(a) == NormalizarLocalizacao(b) {
+		return true
+	}
+	entradaA, okA := c.buscarExata(a)
+	entradaB, okB := c.buscarExata(b)
+	return okA && okB && entradaA.Nome == entradaB.Nome
+}
+
+// Rotulos retorna os rótulos normalizados equivalentes a rotulo, incluindo ele próprio
+func (c *Catalogo) Rotulos(rotulo string) []string {
+	rotulos := []string{NormalizarLocalizacao(rotulo)}
+	if entrada, ok := c.buscarExata(rotulo); ok {
+		for _, r := range append([]string{entrada.Nome}, entrada.Apelidos...) {
+			rotulos = append(rotulos, NormalizarLocalizacao(r))
+		}
+	}
+	return rotulos
+}
+
+// Encontrar procura entre as localizações disponíveis a equivalente ao rótulo. Sem nenhuma
+// equivalente, aceita a única que a busca aproximada leva à mesma entrada do catálogo.
+func (c *Catalogo) Encontrar(localizacoes []Localizacao, rotulo string) (Localizacao, bool) {
+	for _, localizacao := range localizacoes {
+		if c.Equivalentes(localizacao.Nome, rotulo) {
+			return localizacao, true
+		}
+	}
+
+	alvo, ok := c.Buscar(rotulo)
+	if !ok {
+		return Localizacao{}, false
+	}
+	var encontradas []Localizacao
+	for _, localizacao := range localizacoes {
+		if entrada, ok := c.Buscar(localizacao.Nome); ok && entrada.Nome == alvo.Nome {
+			encontradas = append(encontradas, localizacao)
+		}
+	}
+	if len(encontradas) != 1 {
+		return Localizacao{}, false
+	}
+	return encontradas[0], true
+}
+
+// Desconhecidas retorna as localizações cujo rótulo não está no catálogo
+func (c *Catalogo) Desconhecidas(localizacoes []Localizacao) []string {
+	var desconhecidas []string
+	for _, localizacao := range localizacoes {
+		if _, ok := c.Buscar(localizacao.Nome); !ok {
+			desconhecidas = append(desconhecidas, localizacao.Nome)
+		}
+	}
+	return desconhecidas
+}
+
+// avisarSelecaoPorPosicao informa que o rótulo não foi encontrado na tabela e que a
+// localização foi escolhida pela posição, que pode ter mudado
+func avisarSelecaoPorPosicao(localizacao Localizacao) {
+	fmt.Printf("\n⚠️  Aviso: Localização %q não encontrada na tabela; selecionada pela posição %s. "+
+		"Adicione o rótulo exibido pelo Softtrade aos apelidos do catálogo (ponto.localizacoes)\n",
+		localizacao.Nome, localizacao.Valor)
+}
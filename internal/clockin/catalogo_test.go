@@ -0,0 +1,220 @@
+package clockin
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+)
+
+// catalogoEscritorios tem o Home Office padrão e dois escritórios de nomes parecidos
+func catalogoEscritorios(t *testing.T) *Catalogo {
+	t.Helper()
+	catalogo, err := NovoCatalogo([]LocalizacaoConhecida{
+		{Nome: "Escritório SP", Apelidos: []string{"São Paulo"}, Modalidade: ModalidadePresencial},
+		{Nome: "Escritório RJ", Apelidos: []string{"Rio de Janeiro"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return catalogo
+}
+
+func TestNovoCatalogo(t *testing.T) {
+	casos := []struct {
+		nome     string
+		entradas []LocalizacaoConhecida
+		erro     string
+	}{
+		{"vazio usa o padrão", nil, ""},
+		{"apelido igual ao próprio nome", []LocalizacaoConhecida{{Nome: "Sede", Apelidos: []string{"SEDE"}}}, ""},
+		{"redefine o padrão", []LocalizacaoConhecida{{Nome: "home office", Apelidos: []string{"Casa"}, Modalidade: ModalidadeRemota}}, ""},
+		{"apelido repetido entre entradas", []LocalizacaoConhecida{
+			{Nome: "Sede", Apelidos: []string{"Matriz"}},
+			{Nome: "Filial", Apelidos: []string{"matriz"}},
+		}, `rótulo "matriz" aparece em "Sede" e "Filial"`},
+		{"apelido de entrada padrão", []LocalizacaoConhecida{{Nome: "Casa", Apelidos: []string{"Remoto"}}}, `rótulo "Remoto" aparece em "Casa" e "Home Office"`},
+		{"sem nome", []LocalizacaoConhecida{{Nome: "  "}}, "localização sem nome"},
+		{"modalidade inválida", []LocalizacaoConhecida{{Nome: "Sede", Modalidade: "hibrida"}}, "modalidade inválida"},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			_, err := NovoCatalogo(caso.entradas)
+			switch {
+			case caso.erro == "" && err != nil:
+				t.Fatalf("NovoCatalogo: %v", err)
+			case caso.erro != "" && (err == nil || !strings.Contains(err.Error(), caso.erro)):
+				t.Fatalf("NovoCatalogo = %v, esperado erro com %q", err, caso.erro)
+			}
+		})
+	}
+}
+
+func TestNovoCatalogoRedefinePadrao(t *testing.T) {
+	catalogo, err := NovoCatalogo([]LocalizacaoConhecida{{Nome: "home office", Modalidade: ModalidadePresencial}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entrada, ok := catalogo.Buscar("Home Office"); !ok || entrada.Remota() {
+		t.Fatalf("Buscar após redefinir = %+v, %v; esperado presencial", entrada, ok)
+	}
+	if _, ok := catalogo.Buscar("Remoto"); ok {
+		t.Fatal("apelido da entrada padrão mantido após redefini-la")
+	}
+}
+
+func TestNormalizarLocalizacao(t *testing.T) {
+	casos := map[string]string{
+		"HOME OFFICE":          "home office",
+		"  Home  Office ":      "home office",
+		"Escritório São Paulo": "escritorio sao paulo",
+		"Home Office\t":        "home office",
+		"Escrito\u0301rio":     "escritorio",
+		"Home\u00a0Office":     "home office",
+		"CONCEIÇÃO DO MATO":    "conceicao do mato",
+		"":                     "",
+	}
+	for rotulo, esperado := range casos {
+		if obtido := NormalizarLocalizacao(rotulo); obtido != esperado {
+			t.Errorf("NormalizarLocalizacao(%q) = %q, esperado %q", rotulo, obtido, esperado)
+		}
+	}
+}
+
+// TestNormalizarJS garante que a função usada na página normaliza como NormalizarLocalizacao
+func TestNormalizarJS(t *testing.T) {
+	exigirChromium(t)
+	navegador := common.NovoGerenciadorNavegador(true)
+	t.Cleanup(navegador.Close)
+	ctx, cancelar, err := navegador.NovaAba()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cancelar)
+
+	rotulos := []string{"HOME OFFICE", "  Home  Office ", "Escritório São Paulo", "Home Office\t", "Escrito\u0301rio",
+		"CONCEIÇÃO DO MATO", "Teletrabalho\n", "Home\u00a0Office", "Ünïcödé Àçêntos", ""}
+	var obtidos []string
+	if err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(`(function() { %s return %s.map(normalizar); })()`,
+		funcaoNormalizar, selectors.JSLista(rotulos)), &obtidos)); err != nil {
+		t.Fatal(err)
+	}
+	for i, rotulo := range rotulos {
+		if esperado := NormalizarLocalizacao(rotulo); obtidos[i] != esperado {
+			t.Errorf("normalizar(%q) no navegador = %q, NormalizarLocalizacao = %q", rotulo, obtidos[i], esperado)
+		}
+	}
+}
+
+func TestBuscar(t *testing.T) {
+	catalogo := catalogoEscritorios(t)
+	casos := []struct {
+		rotulo     string
+		esperado   string
+		candidatas []string
+	}{
+		{"HOME OFFICE", "Home Office", nil},
+		{"  Home  Office ", "Home Office", nil},
+		{"teletrabalho", "Home Office", nil},
+		{"Home Ofice", "Home Office", nil},
+		{"Hmoe Offcie", "", nil},
+		{"escritorio sp", "Escritório SP", nil},
+		{"Sao Paulo", "Escritório SP", nil},
+		{"Escritorio SJ", "", []string{"Escritório SP", "Escritório RJ"}},
+		{"Rio de Janiero", "Escritório RJ", nil},
+		{"Remota", "Home Office", nil},
+		{"Remotas", "", nil},
+		{"Cliente", "", nil},
+		{"", "", nil},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.rotulo, func(t *testing.T) {
+			entrada, ok := catalogo.Buscar(caso.rotulo)
+			if ok != (caso.esperado != "") || entrada.Nome != caso.esperado {
+				t.Errorf("Buscar(%q) = %q, %v; esperado %q", caso.rotulo, entrada.Nome, ok, caso.esperado)
+			}
+			if candidatas := catalogo.Candidatas(caso.rotulo); !slices.Equal(candidatas, caso.candidatas) {
+				t.Errorf("Candidatas(%q) = %q, esperado %q", caso.rotulo, candidatas, caso.candidatas)
+			}
+		})
+	}
+}
+
+func TestBuscarCatalogoNil(t *testing.T) {
+	var catalogo *Catalogo
+	if entrada, ok := catalogo.Buscar("home office"); !ok || !entrada.Remota() {
+		t.Fatalf("Buscar em catálogo nil = %+v, %v; esperado o catálogo padrão", entrada, ok)
+	}
+}
+
+func TestEquivalentes(t *testing.T) {
+	catalogo := catalogoEscritorios(t)
+	casos := []struct {
+		a, b     string
+		esperado bool
+	}{
+		{"Home Office", "HOME  OFFICE", true},
+		{"Home Office", "Remoto", true},
+		{"Teletrabalho", "remoto", true},
+		{"Escritório SP", "São Paulo", true},
+		{"Cliente X", "cliente x", true},
+		{"Escritório SP", "Escritório RJ", false},
+		{"Home Ofice", "Home Office", false},
+		{"Cliente X", "Cliente Y", false},
+	}
+
+	for _, caso := range casos {
+		if obtido := catalogo.Equivalentes(caso.a, caso.b); obtido != caso.esperado {
+			t.Errorf("Equivalentes(%q, %q) = %v, esperado %v", caso.a, caso.b, obtido, caso.esperado)
+		}
+	}
+}
+
+func TestEncontrar(t *testing.T) {
+	catalogo := catalogoEscritorios(t)
+	casos := []struct {
+		nome         string
+		localizacoes []string
+		rotulo       string
+		esperado     string
+	}{
+		{"rótulo exato", []string{"Escritório RJ", "Home Office"}, "home office", "Home Office"},
+		{"apelido", []string{"Escritório RJ", "HOME OFFICE"}, "Remoto", "HOME OFFICE"},
+		{"exato antes do aproximado", []string{"Escritorio SJ", "Escritório SP"}, "Escritório SP", "Escritório SP"},
+		{"aproximado na tabela", []string{"Escritório RJ", "Home Ofice"}, "Home Office", "Home Ofice"},
+		{"aproximado ambíguo", []string{"Home Ofice", "Home Offce"}, "Home Office", ""},
+		{"ausente", []string{"Escritório RJ"}, "Escritório SP", ""},
+		{"fora do catálogo", []string{"Cliente X"}, "Cliente Y", ""},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			var localizacoes []Localizacao
+			for i, nome := range caso.localizacoes {
+				localizacoes = append(localizacoes, Localizacao{Nome: nome, Valor: fmt.Sprint(i + 1)})
+			}
+			localizacao, ok := catalogo.Encontrar(localizacoes, caso.rotulo)
+			if ok != (caso.esperado != "") || localizacao.Nome != caso.esperado {
+				t.Errorf("Encontrar(%q) = %q, %v; esperado %q", caso.rotulo, localizacao.Nome, ok, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestDesconhecidas(t *testing.T) {
+	catalogo := catalogoEscritorios(t)
+	localizacoes := []Localizacao{
+		{Nome: "HOME OFFICE"}, {Nome: "Escritorio SP"}, {Nome: "Cliente X"}, {Nome: "Escritorio SJ"}, {Nome: "Home Ofice"},
+	}
+
+	desconhecidas := catalogo.Desconhecidas(localizacoes)
+	if esperadas := []string{"Cliente X", "Escritorio SJ"}; !slices.Equal(desconhecidas, esperadas) {
+		t.Fatalf("Desconhecidas = %q, esperado %q", desconhecidas, esperadas)
+	}
+}
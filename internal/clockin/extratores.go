@@ -0,0 +1,220 @@
+package clockin
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+)
+
+// Os extratores leem a página de marcação já carregada em ctx, sem aguardar AJAX nem
+// repetir tentativas. São usados pelo GerenciadorPonto e pela reprodução de snapshots.
+
+// rotuloOperacao retorna o texto do botão que executa a operação
+func rotuloOperacao(seletores selectors.Softtrade, operacao TipoOperacao) string {
+	switch operacao {
+	case Entrada:
+		return seletores.Rotulos.Entrada
+	case Almoco:
+		return seletores.Rotulos.Almoco
+	case Saida:
+		return seletores.Rotulos.Saida
+	default:
+		return operacao.String()
+	}
+}
+
+// rotulosOperacoes retorna os textos dos botões de Entrada, Almoco e Saida
+func rotulosOperacoes(seletores selectors.Softtrade) []string {
+	return []string{
+		rotuloOperacao(seletores, Entrada),
+		rotuloOperacao(seletores, Almoco),
+		rotuloOperacao(seletores, Saida),
+	}
+}
+
+// funcaoBotaoDoRotulo define a função JS botaoDoRotulo(botoes, rotulo, rotulos). Ela
+// prefere o botão cujo texto é exatamente o rótulo, pois "Saída" também está contido em
+// "Saída refeição/descanso", e só aceita texto parcial de botões que não sejam de outra operação.
+const funcaoBotaoDoRotulo = `
+	function botaoDoRotulo(botoes, rotulo, rotulos) {
+		const normalizar = t => t.replace(/\s+/g, ' ').trim();
+		return botoes.find(b => normalizar(b.textContent) === rotulo) ||
+			botoes.find(b => normalizar(b.textContent).includes(rotulo) && !rotulos.includes(normalizar(b.textContent)));
+	}
+`
+
+// ExtrairLocalizacaoAtual retorna o texto da localização selecionada, ou vazio
+func ExtrairLocalizacaoAtual(ctx context.Context, seletores selectors.Softtrade) (string, error) {
+	var localizacaoAtual string
+	err := common.Executar(ctx, chromedp.Evaluate(fmt.Sprintf(`
+		(function() {
+			const btn = document.querySelector(%s);
+			if (!btn) return '';
+
+			btn.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
+
+			const textoLoc = btn.querySelector(%s);
+			return (textoLoc && textoLoc.textContent) ? textoLoc.textContent.trim() : btn.textContent.trim();
+		})()
+	`, selectors.JS(seletores.BotaoLocalizacao), selectors.JS(seletores.TextoLocalizacao)), &localizacaoAtual))
+	return localizacaoAtual, err
+}
+
+// ExtrairLocalizacoes abre o seletor de localização e retorna as opções da tabela
+func ExtrairLocalizacoes(ctx context.Context, seletores selectors.Softtrade) ([]Localizacao, error) {
+	var localizacoes []Localizacao
+	err := common.Executar(ctx, chromedp.Evaluate(fmt.Sprintf(`
+		(function() {
+			const btnLoc = document.querySelector(%s);
+			if (!btnLoc) return [];
+
+			btnLoc.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
+			btnLoc.click();
+
+			const tabela = document.querySelector(%s);
+			if (!tabela) return [];
+
+			tabela.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
+			const tbody = tabela.querySelector('tbody');
+			if (tbody) {
+				tbody.style.cssText = 'display:table-row-group !important; visibility:visible !important; opacity:1 !important';
+			}
+
+			const celulas = Array.from(tabela.querySelectorAll('tbody tr td'));
+			return celulas
+				.map((td, index) => {
+					const texto = td.textContent.trim();
+					return texto ? {nome: texto, valor: (index + 1).toString()} : null;
+				})
+				.filter(item => item !== null);
+		})()
+	`, selectors.JS(seletores.BotaoLocalizacao), selectors.JS(seletores.TabelaLocalizacoes)), &localizacoes))
+	return localizacoes, err
+}
+
+// ExtrairOperacoes retorna as operações cujos botões estão visíveis e habilitados
+func ExtrairOperacoes(ctx context.Context, seletores selectors.Softtrade) ([]TipoOperacao, error) {
+	var operacoesStr []string
+	err := common.Executar(ctx, chromedp.Evaluate(fmt.Sprintf(`
+		(function() {
+			%s
+			const botoes = Array.from(document.querySelectorAll('button'));
+			const tipos = %s;
+
+			// Força a atualização do display dos botões
+			botoes.forEach(btn => {
+				if (btn.offsetParent !== null) {
+					btn.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
+				}
+			});
+
+			const visiveis = botoes.filter(b =>
+				!b.disabled &&
+				b.offsetParent !== null &&
+				window.getComputedStyle(b).display !== 'none'
+			);
+
+			return tipos.filter(tipo => {
+				const btn = botaoDoRotulo(visiveis, tipo, tipos);
+				if (btn) {
+					btn.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
+					return true;
+				}
+				return false;
+			});
+		})()
+	`, funcaoBotaoDoRotulo, selectors.JSLista(rotulosOperacoes(seletores))), &operacoesStr))
+	if err != nil {
+		return nil, err
+	}
+
+	var operacoes []TipoOperacao
+	for _, op := range operacoesStr {
+		switch op {
+		case rotuloOperacao(seletores, Entrada):
+			operacoes = append(operacoes, Entrada)
+		case rotuloOperacao(seletores, Almoco):
+			operacoes = append(operacoes, Almoco)
+		case rotuloOperacao(seletores, Saida):
+			operacoes = append(operacoes, Saida)
+		}
+	}
+	return operacoes, nil
+}
+
+// linhaMarcacao é uma linha da tabela de marcações do dia
+type linhaMarcacao struct {
+	Rotulo  string `json:"rotulo"`
+	Horario string `json:"horario"`
+}
+
+// padraoHorario encontra o horário "15:04" ou "15:04:05" de uma célula
+var padraoHorario = regexp.MustCompile(`\b(\d{1,2}):(\d{2})(?::(\d{2}))?\b`)
+
+// converterMarcacoes interpreta as linhas da tabela de marcações, que lista apenas as
+// marcações do dia. Linhas sem horário, como a mensagem de tabela vazia, são ignoradas.
+func converterMarcacoes(seletores selectors.Softtrade, linhas []linhaMarcacao, dia time.Time) ([]Marcacao, error) {
+	var marcacoes []Marcacao
+	for _, linha := range linhas {
+		partes := padraoHorario.FindStringSubmatch(linha.Horario)
+		if partes == nil {
+			continue
+		}
+
+		rotulo := strings.TrimSpace(linha.Rotulo)
+		var operacao TipoOperacao
+		switch rotulo {
+		case rotuloOperacao(seletores, Entrada):
+			operacao = Entrada
+		case rotuloOperacao(seletores, Almoco):
+			operacao = Almoco
+		case rotuloOperacao(seletores, Saida):
+			operacao = Saida
+		default:
+			return nil, &ErroPonto{
+				Tipo:     "historico",
+				Mensagem: fmt.Sprintf("marcação %q não reconhecida na tabela de marcações", rotulo),
+			}
+		}
+
+		hora, _ := strconv.Atoi(partes[1])
+		minuto, _ := strconv.Atoi(partes[2])
+		segundo, _ := strconv.Atoi(partes[3])
+		ano, mes, d := dia.Date()
+		marcacoes = append(marcacoes, Marcacao{
+			Operacao: operacao,
+			Momento:  time.Date(ano, mes, d, hora, minuto, segundo, 0, dia.Location()),
+		})
+	}
+	return marcacoes, nil
+}
+
+// ExtrairMarcacoes lê a tabela com as marcações já registradas no dia
+func ExtrairMarcacoes(ctx context.Context, seletores selectors.Softtrade, dia time.Time) ([]Marcacao, error) {
+	var linhas []linhaMarcacao
+	err := common.Executar(ctx, chromedp.Evaluate(fmt.Sprintf(`
+		(function() {
+			const tabela = document.querySelector(%s);
+			if (!tabela) return null;
+
+			return Array.from(tabela.querySelectorAll('tbody tr'))
+				.map(tr => Array.from(tr.querySelectorAll('td')).map(td => td.textContent.trim()))
+				.filter(celulas => celulas.length >= 2)
+				.map(celulas => ({rotulo: celulas[0], horario: celulas[1]}));
+		})()
+	`, selectors.JS(seletores.TabelaMarcacoes)), &linhas))
+	if err != nil {
+		return nil, err
+	}
+	if linhas == nil {
+		return nil, &ErroPonto{Tipo: "historico", Mensagem: "tabela de marcações não encontrada"}
+	}
+	return converterMarcacoes(seletores, linhas, dia)
+}
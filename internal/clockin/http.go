@@ -0,0 +1,741 @@
+package clockin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// URLBasePadrao é o endereço do Softtrade usado quando Config.URLBase está vazio
+	URLBasePadrao = "https://oliveiratrust.softtrade.com.br"
+
+	tempoLimiteHTTP = 30 * time.Second
+	renderizarTudo  = "@all"
+)
+
+func init() {
+	Registrar(Provedor{
+		Nome:        ProvedorSofttradeHTTP,
+		Descricao:   "Softtrade por requisições HTTP, sem navegador",
+		Capacidades: Capacidades{Localizacoes: true, Historico: true, Modal: true},
+		Criar: func(ctx context.Context, config Config) (Module, error) {
+			return NovoClienteHTTP(ctx, config)
+		},
+	})
+}
+
+// ClienteHTTP implementa Module enviando os mesmos formulários e requisições AJAX
+// parciais que o PrimeFaces envia a partir do navegador
+type ClienteHTTP struct {
+	ctx       context.Context
+	config    Config
+	intervalo ConfigIntervalo
+	seletores selectors.Softtrade
+	cliente   *http.Client
+	urlBase   *url.URL
+
+	// pagina é a árvore da página de marcação, mantida atualizada pelas respostas parciais
+	pagina    *documentoJSF
+	urlPagina *url.URL
+}
+
+// NovoClienteHTTP cria o cliente HTTP do Softtrade. Login deve ser chamado antes das operações.
+func NovoClienteHTTP(ctx context.Context, config Config) (*ClienteHTTP, error) {
+	seletores := config.Seletores
+	if seletores == nil {
+		seletores = &selectors.Padrao().Softtrade
+	}
+
+	base := config.URLBase
+	if base == "" {
+		base = URLBasePadrao
+	}
+	urlBase, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("URL do Softtrade inválida %q: %w", base, err)
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar armazenamento de cookies: %w", err)
+	}
+
+	return &ClienteHTTP{
+		ctx:       ctx,
+		config:    config,
+		intervalo: config.Intervalo,
+		seletores: *seletores,
+		cliente: &http.Client{
+			Jar:     jar,
+			Timeout: tempoLimiteHTTP,
+		},
+		urlBase: urlBase,
+	}, nil
+}
+
+// Login envia o formulário de login e carrega a página de marcação
+func (c *ClienteHTTP) Login(usuario, senha string) error {
+	if usuario == "" || senha == "" {
+		return &ErroPonto{Tipo: "validacao", Mensagem: "usuário e senha são obrigatórios"}
+	}
+
+	doc, endereco, err := c.obter(c.urlBase)
+	if err != nil {
+		return err
+	}
+	if doc.buscar(c.seletores.Formulario) != nil {
+		c.pagina, c.urlPagina = doc, endereco
+		return nil
+	}
+
+	campoUsuario := doc.buscar(c.seletores.LoginUsuario)
+	campoSenha := doc.buscar(c.seletores.LoginSenha)
+	form := ancestral(campoUsuario, atom.Form)
+	if campoUsuario == nil || campoSenha == nil || form == nil {
+		return &ErroPonto{Tipo: "login", Mensagem: "formulário de login não encontrado"}
+	}
+
+	valores := camposFormulario(form)
+	valores.Set(atributo(campoUsuario, "name"), usuario)
+	valores.Set(atributo(campoSenha, "name"), senha)
+	if botao := buscar(form, c.seletores.LoginEntrar); botao != nil {
+		// O JSF identifica o botão acionado pela presença do seu client id na requisição
+		nome := atributo(botao, "name")
+		if nome == "" {
+			nome = atributo(botao, "id")
+		}
+		valores.Set(nome, nome)
+	}
+
+	doc, endereco, err = c.enviarFormulario(endereco, form, valores)
+	if err != nil {
+		return err
+	}
+	if doc.buscar(c.seletores.Formulario) != nil {
+		c.pagina, c.urlPagina = doc, endereco
+		return nil
+	}
+
+	if mensagem := c.mensagemErro(doc); mensagem != "" {
+		return &ErroPonto{
+			Tipo:     "auth",
+			Mensagem: "login recusado",
+			Causa:    errors.New(mensagem),
+		}
+	}
+
+	// Alguns fluxos redirecionam por script após o login; tenta carregar a página inicial
+	if err := c.recarregar(c.urlBase); err != nil {
+		return &ErroPonto{
+			Tipo:     "login",
+			Mensagem: "página de marcação não encontrada após o login",
+			Causa:    err,
+		}
+	}
+	return nil
+}
+
+func (c *ClienteHTTP) novaRequisicao(metodo string, endereco *url.URL, corpo string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(c.ctx, metodo, endereco.String(), strings.NewReader(corpo))
+	if err != nil {
+		return nil, &ErroPonto{Tipo: "requisicao", Mensagem: "erro ao montar requisição", Causa: err}
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) batedor-automatico-ponto")
+	if metodo == http.MethodPost {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	}
+	return req, nil
+}
+
+// carregarPagina executa a requisição e interpreta o HTML resultante, retornando o endereço final
+func (c *ClienteHTTP) carregarPagina(req *http.Request) (*documentoJSF, *url.URL, error) {
+	resp, err := c.cliente.Do(req)
+	if err != nil {
+		return nil, nil, &ErroPonto{Tipo: "http", Mensagem: "falha na comunicação com o Softtrade", Causa: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, &ErroPonto{Tipo: "http", Mensagem: fmt.Sprintf("resposta inesperada do Softtrade: %s", resp.Status)}
+	}
+
+	doc, err := parseDocumentoJSF(resp.Body)
+	if err != nil {
+		return nil, nil, &ErroPonto{Tipo: "http", Mensagem: "página inválida", Causa: err}
+	}
+	return doc, resp.Request.URL, nil
+}
+
+func (c *ClienteHTTP) obter(endereco *url.URL) (*documentoJSF, *url.URL, error) {
+	req, err := c.novaRequisicao(http.MethodGet, endereco, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.carregarPagina(req)
+}
+
+// enviarFormulario submete o formulário sem AJAX, como um clique em um botão comum
+func (c *ClienteHTTP) enviarFormulario(origem *url.URL, form *html.Node, valores url.Values) (*documentoJSF, *url.URL, error) {
+	acao, err := origem.Parse(atributo(form, "action"))
+	if err != nil {
+		return nil, nil, &ErroPonto{Tipo: "http", Mensagem: "ação do formulário inválida", Causa: err}
+	}
+
+	req, err := c.novaRequisicao(http.MethodPost, acao, valores.Encode())
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.carregarPagina(req)
+}
+
+// recarregar busca novamente a página de marcação, descartando o estado local
+func (c *ClienteHTTP) recarregar(endereco *url.URL) error {
+	doc, final, err := c.obter(endereco)
+	if err != nil {
+		return err
+	}
+	if doc.buscar(c.seletores.Formulario) == nil {
+		c.pagina = nil
+		return &ErroPonto{Tipo: "sessao", Mensagem: "sessão encerrada no Softtrade; faça login novamente"}
+	}
+	c.pagina, c.urlPagina = doc, final
+	return nil
+}
+
+// enviarAjax envia uma requisição parcial do JSF com o estado do formulário e aplica a resposta na página
+func (c *ClienteHTTP) enviarAjax(form *html.Node, fonte, executar string, extras url.Values) (*respostaParcial, error) {
+	valores := camposFormulario(form)
+	valores.Set("javax.faces.partial.ajax", "true")
+	valores.Set("javax.faces.source", fonte)
+	valores.Set("javax.faces.partial.execute", executar)
+	valores.Set("javax.faces.partial.render", renderizarTudo)
+	for chave, valor := range extras {
+		valores[chave] = valor
+	}
+
+	acao, err := c.urlPagina.Parse(atributo(form, "action"))
+	if err != nil {
+		return nil, &ErroPonto{Tipo: "requisicao", Mensagem: "ação do formulário inválida", Causa: err}
+	}
+
+	req, err := c.novaRequisicao(http.MethodPost, acao, valores.Encode())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Faces-Request", "partial/ajax")
+	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	req.Header.Set("Accept", "application/xml, text/xml, */*; q=0.01")
+
+	resp, err := c.cliente.Do(req)
+	if err != nil {
+		tipo := "http"
+		if naoEnviada(err) {
+			tipo = "requisicao"
+		}
+		return nil, &ErroPonto{Tipo: tipo, Mensagem: "falha na comunicação com o Softtrade", Causa: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ErroPonto{Tipo: "http", Mensagem: fmt.Sprintf("resposta inesperada do Softtrade: %s", resp.Status)}
+	}
+
+	resposta, err := parseRespostaParcial(resp.Body)
+	if err != nil {
+		return nil, &ErroPonto{Tipo: "http", Mensagem: "resposta AJAX inválida", Causa: err}
+	}
+
+	switch {
+	case resposta.sessaoExpirada():
+		// Recarrega a página para obter um ViewState válido antes da próxima tentativa
+		if err := c.recarregar(c.urlPagina); err != nil {
+			return nil, err
+		}
+		return nil, &ErroPonto{Tipo: "sessao", Mensagem: "estado da página expirou no servidor"}
+	case resposta.Erro != nil:
+		return nil, &ErroPonto{Tipo: "execucao", Mensagem: "erro no servidor", Causa: errors.New(resposta.Erro.Mensagem)}
+	case resposta.Redirect != nil:
+		destino, err := c.urlPagina.Parse(resposta.Redirect.URL)
+		if err != nil {
+			return nil, &ErroPonto{Tipo: "http", Mensagem: "redirecionamento inválido", Causa: err}
+		}
+		if err := c.recarregar(destino); err != nil {
+			return nil, err
+		}
+		return resposta, nil
+	}
+
+	if err := resposta.aplicar(c.pagina); err != nil {
+		return nil, &ErroPonto{Tipo: "http", Mensagem: "falha ao aplicar resposta AJAX", Causa: err}
+	}
+	return resposta, nil
+}
+
+// naoEnviada indica que a requisição falhou ao abrir a conexão, sem chegar ao Softtrade
+func naoEnviada(err error) bool {
+	var erroRede *net.OpError
+	return errors.As(err, &erroRede) && erroRede.Op == "dial"
+}
+
+// marcacaoNaoEnviada indica que a marcação certamente não foi registrada: a requisição não
+// saiu (tipo "requisicao") ou o servidor a recusou por sessão ou ViewState expirado
+func marcacaoNaoEnviada(err error) bool {
+	var erroPonto *ErroPonto
+	return errors.As(err, &erroPonto) && (erroPonto.Tipo == "requisicao" || erroPonto.Tipo == "sessao")
+}
+
+// mensagemErro retorna a primeira mensagem de erro exibida na página
+func (c *ClienteHTTP) mensagemErro(doc *documentoJSF) string {
+	for _, seletor := range c.seletores.MensagensErro {
+		for _, elemento := range doc.buscarTodos(seletor) {
+			if t := texto(elemento); t != "" {
+				return t
+			}
+		}
+	}
+	return ""
+}
+
+func (c *ClienteHTTP) formulario() (*html.Node, error) {
+	if c.pagina == nil {
+		return nil, &ErroPonto{Tipo: "sessao", Mensagem: "login não realizado"}
+	}
+	form := c.pagina.buscar(c.seletores.Formulario)
+	if form == nil {
+		return nil, &ErroPonto{Tipo: "sessao", Mensagem: "formulário de marcação não encontrado"}
+	}
+	return form, nil
+}
+
+// formularioDe retorna o formulário que contém o elemento, ou o formulário de marcação
+func (c *ClienteHTTP) formularioDe(n *html.Node) (*html.Node, error) {
+	if form := ancestral(n, atom.Form); form != nil {
+		return form, nil
+	}
+	return c.formulario()
+}
+
+// rotulo retorna o texto do botão que executa a operação
+func (c *ClienteHTTP) rotulo(operacao TipoOperacao) string {
+	return rotuloOperacao(c.seletores, operacao)
+}
+
+// dialogos retorna os diálogos da página cujo título contém o texto informado
+func (c *ClienteHTTP) dialogos(titulo string) []*html.Node {
+	var encontrados []*html.Node
+	for _, dialogo := range c.pagina.buscarTodos(c.seletores.Dialogo) {
+		if titulo == "" || strings.Contains(texto(buscar(dialogo, c.seletores.DialogoTitulo)), titulo) {
+			encontrados = append(encontrados, dialogo)
+		}
+	}
+	return encontrados
+}
+
+// botaoPorRotulo localiza o botão visível e habilitado da página de marcação com o rótulo,
+// preferindo correspondência exata, pois "Saída" também é prefixo de outro rótulo
+func (c *ClienteHTTP) botaoPorRotulo(rotulo string) *html.Node {
+	dialogos := c.dialogos("")
+	dentroDeDialogo := func(n *html.Node) bool {
+		for p := n; p != nil; p = p.Parent {
+			for _, d := range dialogos {
+				if p == d {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var parcial *html.Node
+	for _, botao := range c.pagina.buscarTodos("button") {
+		if desabilitado(botao) || oculto(botao) || dentroDeDialogo(botao) {
+			continue
+		}
+		t := texto(botao)
+		if t == rotulo {
+			return botao
+		}
+		if parcial == nil && strings.Contains(t, rotulo) {
+			parcial = botao
+		}
+	}
+	return parcial
+}
+
+func (c *ClienteHTTP) obterLocalizacaoAtual() (string, error) {
+	if _, err := c.formulario(); err != nil {
+		return "", err
+	}
+
+	botao := c.pagina.buscar(c.seletores.BotaoLocalizacao)
+	if botao == nil {
+		return "", &ErroPonto{Tipo: "localizacao", Mensagem: "localização não encontrada"}
+	}
+
+	localizacao := texto(buscar(botao, c.seletores.TextoLocalizacao))
+	if localizacao == "" {
+		localizacao = texto(botao)
+	}
+	if localizacao == "" {
+		return "", &ErroPonto{Tipo: "localizacao", Mensagem: "localização não encontrada"}
+	}
+	return localizacao, nil
+}
+
+// tabelaLocalizacoes retorna a tabela de localizações, abrindo o seletor quando
+// a tabela só é renderizada após o clique no botão de localização
+func (c *ClienteHTTP) tabelaLocalizacoes() (*html.Node, error) {
+	tabela := c.pagina.buscar(c.seletores.TabelaLocalizacoes)
+	if tabela != nil && len(buscarTodos(tabela, "tbody tr td")) > 0 {
+		return tabela, nil
+	}
+
+	botao := c.pagina.buscar(c.seletores.BotaoLocalizacao)
+	id := atributo(botao, "id")
+	if id == "" {
+		return nil, &ErroPonto{Tipo: "localizacao", Mensagem: "botão de localização não encontrado"}
+	}
+	form, err := c.formularioDe(botao)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.enviarAjax(form, id, id, url.Values{id: {id}}); err != nil {
+		return nil, err
+	}
+
+	tabela = c.pagina.buscar(c.seletores.TabelaLocalizacoes)
+	if tabela == nil {
+		return nil, &ErroPonto{Tipo: "localizacao", Mensagem: "tabela de localizações não encontrada"}
+	}
+	return tabela, nil
+}
+
+func (c *ClienteHTTP) obterLocalizacoesDisponiveis() ([]Localizacao, error) {
+	localizacoes, _, err := tentar(c.ctx, c.config, OpListarLocalizacoes, func() ([]Localizacao, error) {
+		if _, err := c.formulario(); err != nil {
+			return nil, err
+		}
+
+		tabela, err := c.tabelaLocalizacoes()
+		if err != nil {
+			return nil, err
+		}
+
+		var localizacoes []Localizacao
+		for i, celula := range buscarTodos(tabela, "tbody tr td") {
+			if nome := texto(celula); nome != "" {
+				localizacoes = append(localizacoes, Localizacao{Nome: nome, Valor: strconv.Itoa(i + 1)})
+			}
+		}
+
+		if len(localizacoes) == 0 {
+			return nil, &ErroPonto{
+				Tipo:     "localizacao",
+				Mensagem: "nenhuma localização disponível",
+			}
+		}
+		return localizacoes, nil
+	})
+	return localizacoes, err
+}
+
+func (c *ClienteHTTP) selecionarLocalizacao(localizacao Localizacao) error {
+	_, _, err := tentar(c.ctx, c.config, OpSelecionarLocalizacao, func() (bool, error) {
+		if _, err := c.formulario(); err != nil {
+			return false, err
+		}
+
+		tabela, err := c.tabelaLocalizacoes()
+		if err != nil {
+			return false, err
+		}
+
+		linhas := buscarTodos(tabela, "tbody tr")
+		var linha *html.Node
+		posicao := 0
+	procurar:
+		for i, l := range linhas {
+			for _, celula := range buscarTodos(l, "td") {
+				if c.config.Catalogo.Equivalentes(texto(celula), localizacao.Nome) {
+					linha, posicao = l, i
+					break procurar
+				}
+			}
+		}
+		if linha == nil {
+			if n, err := strconv.Atoi(localizacao.Valor); err == nil && n >= 1 && n <= len(linhas) {
+				linha, posicao = linhas[n-1], n-1
+				avisarSelecaoPorPosicao(localizacao)
+			}
+		}
+		if linha == nil {
+			return false, &ErroPonto{
+				Tipo:     "localizacao",
+				Mensagem: fmt.Sprintf("falha ao selecionar %s", localizacao.Nome),
+			}
+		}
+
+		chave := atributo(linha, "data-rk")
+		if chave == "" {
+			chave = strconv.Itoa(posicao)
+		}
+
+		id := atributo(tabela, "id")
+		form, err := c.formularioDe(tabela)
+		if err != nil {
+			return false, err
+		}
+
+		// Mesmo evento enviado pelo DataTable do PrimeFaces ao clicar em uma linha
+		_, err = c.enviarAjax(form, id, id, url.Values{
+			id + "_instantSelection":     {chave},
+			id + "_selection":            {chave},
+			"javax.faces.behavior.event": {"rowSelect"},
+			"javax.faces.partial.event":  {"rowSelect"},
+		})
+		if err != nil {
+			return false, err
+		}
+		return true, nil
+	})
+	return err
+}
+
+func (c *ClienteHTTP) obterOperacoesDisponiveis() ([]TipoOperacao, error) {
+	if _, err := c.formulario(); err != nil {
+		return nil, err
+	}
+
+	var operacoes []TipoOperacao
+	for _, op := range []TipoOperacao{Entrada, Almoco, Saida} {
+		if c.botaoPorRotulo(c.rotulo(op)) != nil {
+			operacoes = append(operacoes, op)
+		}
+	}
+	return operacoes, nil
+}
+
+func (c *ClienteHTTP) obterMarcacoes() ([]Marcacao, error) {
+	if _, err := c.formulario(); err != nil {
+		return nil, err
+	}
+
+	tabela := c.pagina.buscar(c.seletores.TabelaMarcacoes)
+	if tabela == nil {
+		return nil, &ErroPonto{Tipo: "historico", Mensagem: "tabela de marcações não encontrada"}
+	}
+
+	var linhas []linhaMarcacao
+	for _, tr := range buscarTodos(tabela, "tbody tr") {
+		if celulas := buscarTodos(tr, "td"); len(celulas) >= 2 {
+			linhas = append(linhas, linhaMarcacao{Rotulo: texto(celulas[0]), Horario: texto(celulas[1])})
+		}
+	}
+	return converterMarcacoes(c.seletores, linhas, common.RelogioOuSistema(c.config.Relogio).Agora())
+}
+
+func (c *ClienteHTTP) executarOperacao(operacao TipoOperacao) (*ResultadoOperacao, error) {
+	var resposta *respostaParcial
+	incerta := false
+	_, tentativas, err := tentar(c.ctx, c.config, OpExecutarOperacao, func() (bool, error) {
+		// Uma marcação enviada sem resposta válida pode ter sido registrada: recarrega a
+		// página e só a repete se a operação continuar disponível
+		if incerta {
+			if err := c.recarregar(c.urlPagina); err != nil {
+				return false, err
+			}
+			incerta = false
+			if c.botaoPorRotulo(c.rotulo(operacao)) == nil {
+				return false, &ErroPonto{
+					Operacao: operacao,
+					Tipo:     "confirmacao",
+					Mensagem: "a operação não é mais oferecida; a tentativa anterior pode ter registrado a marcação",
+				}
+			}
+		}
+
+		if _, err := c.formulario(); err != nil {
+			return false, err
+		}
+
+		botao := c.botaoPorRotulo(c.rotulo(operacao))
+		if botao == nil {
+			return false, &ErroPonto{
+				Operacao: operacao,
+				Tipo:     "validacao",
+				Mensagem: "operação indisponível",
+			}
+		}
+
+		id := atributo(botao, "id")
+		form, err := c.formularioDe(botao)
+		if err != nil {
+			return false, err
+		}
+
+		resposta, err = c.enviarAjax(form, id, id, url.Values{id: {id}})
+		if err != nil {
+			var erroPonto *ErroPonto
+			if errors.As(err, &erroPonto) {
+				erroPonto.Operacao = operacao
+			}
+			incerta = !marcacaoNaoEnviada(err)
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// O modal é tratado fora das tentativas para que uma falha ao respondê-lo
+	// não provoque uma nova marcação
+	modal, err := c.tratarModalIntervalo(operacao, resposta)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResultadoOperacao{
+		Operacao:   operacao,
+		Modal:      modal,
+		Tentativas: tentativas,
+	}, nil
+}
+
+// modalVisivel indica se o diálogo foi exibido pela resposta da marcação.
+// O PrimeFaces exibe diálogos por script, então são consideradas tanto chamadas a show()
+// na resposta quanto diálogos renderizados já visíveis.
+func modalVisivel(dialogo *html.Node, resposta *respostaParcial) bool {
+	if atributo(dialogo, "aria-hidden") == "false" {
+		return true
+	}
+	if resposta != nil {
+		for _, script := range resposta.Avaliacoes {
+			if strings.Contains(script, ".show(") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *ClienteHTTP) tratarModalIntervalo(operacao TipoOperacao, resposta *respostaParcial) (*ModalIntervalo, error) {
+	if c.pagina == nil {
+		return nil, nil
+	}
+
+	var dialogo *html.Node
+	for _, d := range c.dialogos(c.seletores.Rotulos.ModalIntervalo) {
+		if modalVisivel(d, resposta) {
+			dialogo = d
+			break
+		}
+	}
+	if dialogo == nil {
+		return nil, nil
+	}
+
+	conteudo := texto(buscar(dialogo, c.seletores.DialogoConteudo))
+	modal := &ModalIntervalo{
+		Titulo:   texto(buscar(dialogo, c.seletores.DialogoTitulo)),
+		Conteudo: conteudo,
+		Duracao:  extrairDuracaoIntervalo(conteudo),
+		Politica: c.intervalo.Politica,
+	}
+
+	respostaModal, err := c.intervalo.decidir(*modal)
+	if err != nil {
+		var erroPonto *ErroPonto
+		if errors.As(err, &erroPonto) {
+			erroPonto.Operacao = operacao
+			return nil, erroPonto
+		}
+		return nil, &ErroPonto{
+			Operacao: operacao,
+			Tipo:     "modal",
+			Mensagem: "erro na confirmação do intervalo",
+			Causa:    err,
+		}
+	}
+	modal.Resposta = respostaModal
+
+	rotulo := c.seletores.Rotulos.Nao
+	if respostaModal {
+		rotulo = c.seletores.Rotulos.Sim
+	}
+
+	var botao *html.Node
+	for _, b := range buscarTodos(dialogo, "button") {
+		if texto(b) == rotulo {
+			botao = b
+			break
+		}
+	}
+	if botao == nil {
+		return nil, &ErroPonto{
+			Operacao: operacao,
+			Tipo:     "modal",
+			Mensagem: "falha ao responder modal",
+			Causa:    fmt.Errorf("botão %q não encontrado no modal", rotulo),
+		}
+	}
+
+	id := atributo(botao, "id")
+	form, err := c.formularioDe(botao)
+	if err == nil {
+		_, err = c.enviarAjax(form, id, id, url.Values{id: {id}})
+	}
+	if err != nil {
+		return nil, &ErroPonto{
+			Operacao: operacao,
+			Tipo:     "modal",
+			Mensagem: "falha ao responder modal",
+			Causa:    err,
+		}
+	}
+
+	return modal, nil
+}
+
+func (c *ClienteHTTP) ObterLocalizacaoAtual() (string, error) {
+	return c.obterLocalizacaoAtual()
+}
+
+func (c *ClienteHTTP) ObterLocalizacoesDisponiveis() ([]Localizacao, error) {
+	return c.obterLocalizacoesDisponiveis()
+}
+
+func (c *ClienteHTTP) SelecionarLocalizacao(localizacao Localizacao) error {
+	return c.selecionarLocalizacao(localizacao)
+}
+
+func (c *ClienteHTTP) ObterOperacoesDisponiveis() ([]TipoOperacao, error) {
+	return c.obterOperacoesDisponiveis()
+}
+
+func (c *ClienteHTTP) ExecutarOperacao(operacao TipoOperacao) (*ResultadoOperacao, error) {
+	return c.executarOperacao(operacao)
+}
+
+// ObterMarcacoes retorna as marcações do dia exibidas na tabela da página de marcação
+func (c *ClienteHTTP) ObterMarcacoes() ([]Marcacao, error) {
+	return c.obterMarcacoes()
+}
+
+// Close releases resources used by the module
+func (c *ClienteHTTP) Close() {
+	c.cliente.CloseIdleConnections()
+}
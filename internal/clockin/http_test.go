@@ -0,0 +1,226 @@
+package clockin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/softtradefake"
+)
+
+// politicaRapida repete as operações sem esperar
+var politicaRapida = map[string]common.PoliticaRetentativa{
+	OpExecutarOperacao:      {MaxTentativas: 3, EsperaInicial: time.Millisecond, EsperaMaxima: time.Millisecond},
+	OpSelecionarLocalizacao: {MaxTentativas: 3, EsperaInicial: time.Millisecond, EsperaMaxima: time.Millisecond},
+}
+
+// novoClienteFake cria um ClienteHTTP apontado para o endereço informado
+func novoClienteFake(t *testing.T, endereco string) *ClienteHTTP {
+	t.Helper()
+	cliente, err := NovoClienteHTTP(context.Background(), Config{
+		URLBase:      endereco,
+		Intervalo:    ConfigIntervalo{Politica: IntervaloSempreNao},
+		Retentativas: politicaRapida,
+	})
+	if err != nil {
+		t.Fatalf("NovoClienteHTTP: %v", err)
+	}
+	return cliente
+}
+
+// clienteAutenticado inicia o Softtrade falso e retorna o cliente já autenticado
+func clienteAutenticado(t *testing.T, cenario softtradefake.Cenario) (*softtradefake.Servidor, *ClienteHTTP) {
+	t.Helper()
+	servidor := softtradefake.Iniciar(cenario)
+	t.Cleanup(servidor.Close)
+
+	cliente := novoClienteFake(t, servidor.URL())
+	if err := cliente.Login(cenario.Usuario, cenario.Senha); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return servidor, cliente
+}
+
+func executarHTTP(t *testing.T, c *ClienteHTTP, operacao TipoOperacao) *ResultadoOperacao {
+	t.Helper()
+	resultado, err := c.ExecutarOperacao(operacao)
+	if err != nil {
+		t.Fatalf("ExecutarOperacao(%s): %v", operacao, err)
+	}
+	return resultado
+}
+
+func tipoErro(err error) string {
+	var erroPonto *ErroPonto
+	if errors.As(err, &erroPonto) {
+		return erroPonto.Tipo
+	}
+	return ""
+}
+
+func TestClienteHTTPLogin(t *testing.T) {
+	servidor := softtradefake.Iniciar(softtradefake.CenarioPadrao())
+	defer servidor.Close()
+
+	casos := []struct {
+		nome, usuario, senha, tipo string
+	}{
+		{"credenciais válidas", "usuario", "senha", ""},
+		{"senha errada", "usuario", "errada", "auth"},
+		{"senha vazia", "usuario", "", "validacao"},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			err := novoClienteFake(t, servidor.URL()).Login(caso.usuario, caso.senha)
+			if tipo := tipoErro(err); tipo != caso.tipo || (caso.tipo == "" && err != nil) {
+				t.Fatalf("Login = %v (tipo %q), esperado tipo %q", err, tipo, caso.tipo)
+			}
+		})
+	}
+}
+
+func TestClienteHTTPLocalizacaoEMarcacao(t *testing.T) {
+	servidor, cliente := clienteAutenticado(t, softtradefake.CenarioPadrao())
+
+	if operacoes, err := cliente.ObterOperacoesDisponiveis(); err != nil || len(operacoes) != 0 {
+		t.Fatalf("operações sem localização = %v, %v", operacoes, err)
+	}
+
+	localizacoes, err := cliente.ObterLocalizacoesDisponiveis()
+	if err != nil || len(localizacoes) != 3 {
+		t.Fatalf("ObterLocalizacoesDisponiveis = %v, %v", localizacoes, err)
+	}
+	if err := cliente.SelecionarLocalizacao(Localizacao{Nome: "Escritório SP", Valor: "3"}); err != nil {
+		t.Fatalf("SelecionarLocalizacao: %v", err)
+	}
+	if atual, err := cliente.ObterLocalizacaoAtual(); err != nil || atual != "Escritório SP" {
+		t.Fatalf("ObterLocalizacaoAtual = %q, %v", atual, err)
+	}
+
+	// Cada resposta parcial troca o ViewState; as marcações seguintes só são aceitas se o
+	// cliente o atualizar
+	executarHTTP(t, cliente, Entrada)
+	executarHTTP(t, cliente, Saida)
+
+	marcacoes := servidor.Marcacoes()
+	if len(marcacoes) != 2 || marcacoes[0].Localizacao != "Escritório SP" || marcacoes[1].Operacao != softtradefake.Saida {
+		t.Fatalf("marcações = %+v", marcacoes)
+	}
+}
+
+func TestClienteHTTPViewExpirada(t *testing.T) {
+	cenario := cenarioComLocalizacao()
+	cenario.ExpirarView = true
+	servidor, cliente := clienteAutenticado(t, cenario)
+
+	resultado := executarHTTP(t, cliente, Entrada)
+
+	if resultado.Tentativas != 2 {
+		t.Errorf("tentativas = %d, esperadas 2", resultado.Tentativas)
+	}
+	if marcacoes := servidor.Marcacoes(); len(marcacoes) != 1 {
+		t.Fatalf("marcações = %+v, esperada 1", marcacoes)
+	}
+}
+
+func TestClienteHTTPModal(t *testing.T) {
+	servidor, cliente := clienteAutenticado(t, cenarioComLocalizacao())
+
+	executarHTTP(t, cliente, Entrada)
+	executarHTTP(t, cliente, Almoco)
+	resultado := executarHTTP(t, cliente, Entrada)
+
+	if resultado.Modal == nil || resultado.Modal.Resposta {
+		t.Fatalf("modal = %+v, esperada resposta não", resultado.Modal)
+	}
+	marcacoes := servidor.Marcacoes()
+	if len(marcacoes) != 3 || marcacoes[2].RespostaIntervalo == nil || *marcacoes[2].RespostaIntervalo {
+		t.Fatalf("marcações = %+v", marcacoes)
+	}
+}
+
+func TestClienteHTTPMarcacoes(t *testing.T) {
+	_, cliente := clienteAutenticado(t, cenarioComLocalizacao())
+
+	if marcacoes, err := cliente.ObterMarcacoes(); err != nil || len(marcacoes) != 0 {
+		t.Fatalf("marcações antes de marcar = %v, %v", marcacoes, err)
+	}
+
+	executarHTTP(t, cliente, Entrada)
+	executarHTTP(t, cliente, Almoco)
+
+	marcacoes, err := cliente.ObterMarcacoes()
+	if err != nil || len(marcacoes) != 2 || marcacoes[0].Operacao != Entrada || marcacoes[1].Operacao != Almoco {
+		t.Fatalf("ObterMarcacoes = %+v, %v", marcacoes, err)
+	}
+	if desvio := time.Since(marcacoes[1].Momento); desvio < 0 || desvio > 2*time.Minute {
+		t.Fatalf("horário da marcação %s distante de agora", marcacoes[1].Momento)
+	}
+}
+
+func TestClienteHTTPFalhaAntesDoRegistro(t *testing.T) {
+	cenario := cenarioComLocalizacao()
+	cenario.FalhasMarcacao = 1
+	servidor, cliente := clienteAutenticado(t, cenario)
+
+	resultado := executarHTTP(t, cliente, Entrada)
+
+	if resultado.Tentativas != 2 {
+		t.Errorf("tentativas = %d, esperadas 2", resultado.Tentativas)
+	}
+	if marcacoes := servidor.Marcacoes(); len(marcacoes) != 1 {
+		t.Fatalf("marcações = %+v, esperada 1", marcacoes)
+	}
+}
+
+// respostaPerdida registra as marcações no Softtrade falso, mas responde a primeira com
+// erro 502, como um proxy que perde a resposta
+type respostaPerdida struct {
+	softtrade *softtradefake.Servidor
+	perdidas  int
+}
+
+func (p *respostaPerdida) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/marcacao" || p.perdidas > 0 {
+		p.softtrade.ServeHTTP(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !strings.HasPrefix(r.PostForm.Get("javax.faces.source"), "formMarc:btn") {
+		p.softtrade.ServeHTTP(w, r)
+		return
+	}
+
+	p.perdidas++
+	p.softtrade.ServeHTTP(httptest.NewRecorder(), r)
+	http.Error(w, "bad gateway", http.StatusBadGateway)
+}
+
+func TestClienteHTTPRespostaPerdidaNaoDuplica(t *testing.T) {
+	cenario := cenarioComLocalizacao()
+	softtrade := softtradefake.Novo(cenario)
+	servidor := httptest.NewServer(&respostaPerdida{softtrade: softtrade})
+	defer servidor.Close()
+
+	cliente := novoClienteFake(t, servidor.URL)
+	if err := cliente.Login(cenario.Usuario, cenario.Senha); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	_, err := cliente.ExecutarOperacao(Entrada)
+	if tipo := tipoErro(err); tipo != "confirmacao" {
+		t.Fatalf("ExecutarOperacao = %v (tipo %q), esperado tipo confirmacao", err, tipo)
+	}
+	if marcacoes := softtrade.Marcacoes(); len(marcacoes) != 1 {
+		t.Fatalf("marcações = %+v, esperada 1", marcacoes)
+	}
+}
@@ -1,6 +1,12 @@
 package clockin
 
-import "context"
+import (
+	"context"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/snapshot"
+)
 
 // Module defines the interface for clock-in operations
 type Module interface {
@@ -17,7 +23,7 @@ type Module interface {
 	ObterOperacoesDisponiveis() ([]TipoOperacao, error)
 
 	// ExecutarOperacao executes a clock-in operation
-	ExecutarOperacao(operacao TipoOperacao) error
+	ExecutarOperacao(operacao TipoOperacao) (*ResultadoOperacao, error)
 
 	// Close releases resources used by the module
 	Close()
@@ -27,12 +33,54 @@ type Module interface {
 type Config struct {
 	// UseMock determina se será usado o mock ao invés do browser real
 	UseMock bool
+
+	// CenarioMock roteiriza as falhas, o relógio e o estado do ProvedorMock
+	CenarioMock CenarioMock
+
+	// Provedor é o nome do provedor registrado a ser usado; vazio equivale a ProvedorSofttrade
+	Provedor string
+
+	// URLBase é o endereço do Softtrade usado por ProvedorSofttradeHTTP; vazio usa URLBasePadrao
+	URLBase string
+
+	// Intervalo define como responder ao modal "Intervalo Opcional"
+	Intervalo ConfigIntervalo
+
+	// Retentativas define a política por operação (OpObterLocalizacao, OpExecutarOperacao, ...);
+	// campos não informados usam PoliticaRetentativaPadrao
+	Retentativas map[string]common.PoliticaRetentativa
+
+	// Seletores contém os seletores do Softtrade; se nil, usa o mapa embutido
+	Seletores *selectors.Softtrade
+
+	// Catalogo reconhece os rótulos das localizações; nil usa CatalogoPadrao
+	Catalogo *Catalogo
+
+	// Relogio mede as esperas entre tentativas e o horário do mock; nil usa o relógio do sistema
+	Relogio common.Relogio
+
+	// Gravador salva snapshots das páginas lidas pelo GerenciadorPonto; nil desabilita
+	Gravador *snapshot.Gravador
+}
+
+// NomeProvedor retorna o nome do provedor efetivo, considerando UseMock e o padrão
+func (c Config) NomeProvedor() string {
+	switch {
+	case c.UseMock:
+		return ProvedorMock
+	case c.Provedor == "":
+		return ProvedorSofttrade
+	default:
+		return c.Provedor
+	}
 }
 
-// NewModule creates a new instance of the ClockIn module
-func NewModule(ctx context.Context, config Config) Module {
-	if config.UseMock {
-		return NewMockPonto(ctx)
+// NewModule creates a new instance of the ClockIn module using the configured provider.
+// Modules implementing Autenticavel must be logged in before use.
+func NewModule(ctx context.Context, config Config) (Module, error) {
+	provedor, err := ObterProvedor(config.NomeProvedor())
+	if err != nil {
+		return nil, err
 	}
-	return NewGerenciadorPonto(ctx)
+	return provedor.Criar(ctx, config)
 }
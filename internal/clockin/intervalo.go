@@ -0,0 +1,150 @@
+package clockin
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// PoliticaIntervalo define como o modal "Intervalo Opcional" deve ser respondido
+type PoliticaIntervalo string
+
+const (
+	// IntervaloSempreSim sempre considera o período como intervalo opcional
+	IntervaloSempreSim PoliticaIntervalo = "sim"
+	// IntervaloSempreNao nunca considera o período como intervalo opcional
+	IntervaloSempreNao PoliticaIntervalo = "nao"
+	// IntervaloAcimaDeMinutos responde "Sim" apenas se o intervalo passou de MinutosMinimos
+	IntervaloAcimaDeMinutos PoliticaIntervalo = "acima_de_minutos"
+	// IntervaloPerguntar delega a decisão ao ConfirmadorIntervalo
+	IntervaloPerguntar PoliticaIntervalo = "perguntar"
+)
+
+// ConfigIntervalo contém a política de resposta ao modal "Intervalo Opcional"
+type ConfigIntervalo struct {
+	// Politica determina a resposta ao modal; vazio equivale a IntervaloPerguntar
+	Politica PoliticaIntervalo
+
+	// MinutosMinimos é o limite usado por IntervaloAcimaDeMinutos
+	MinutosMinimos int
+
+	// Confirmador é consultado pela política IntervaloPerguntar
+	Confirmador ConfirmadorIntervalo
+}
+
+// ConfirmadorIntervalo pergunta ao usuário se o período deve ser considerado intervalo opcional
+type ConfirmadorIntervalo interface {
+	ConfirmarIntervaloOpcional(modal ModalIntervalo) (bool, error)
+}
+
+// ModalIntervalo contém os dados do modal "Intervalo Opcional" exibido após uma marcação
+type ModalIntervalo struct {
+	// Titulo é o título exibido no modal
+	Titulo string
+	// Conteudo é o texto exibido no corpo do modal
+	Conteudo string
+	// Duracao é a duração do intervalo extraída do conteudo, zero se não encontrada
+	Duracao time.Duration
+	// Resposta indica se o período foi considerado intervalo opcional
+	Resposta bool
+	// Politica é a política usada para decidir a resposta
+	Politica PoliticaIntervalo
+}
+
+// ResultadoOperacao descreve o resultado de uma marcação de ponto
+type ResultadoOperacao struct {
+	Operacao TipoOperacao
+	// Modal é preenchido quando o modal "Intervalo Opcional" foi exibido
+	Modal *ModalIntervalo
+	// Tentativas é o número de tentativas necessárias para registrar a marcação
+	Tentativas int
+}
+
+// ParsePoliticaIntervalo converte o valor da configuração em uma PoliticaIntervalo
+func ParsePoliticaIntervalo(valor string) (PoliticaIntervalo, error) {
+	switch p := PoliticaIntervalo(valor); p {
+	case "":
+		return IntervaloPerguntar, nil
+	case IntervaloSempreSim, IntervaloSempreNao, IntervaloAcimaDeMinutos, IntervaloPerguntar:
+		return p, nil
+	default:
+		return "", fmt.Errorf("política de intervalo opcional inválida: %q", valor)
+	}
+}
+
+// decidir aplica a política ao modal e retorna a resposta a ser enviada
+func (c ConfigIntervalo) decidir(modal ModalIntervalo) (bool, error) {
+	switch c.Politica {
+	case IntervaloSempreSim:
+		return true, nil
+	case IntervaloSempreNao:
+		return false, nil
+	case IntervaloAcimaDeMinutos:
+		if modal.Duracao > 0 {
+			return modal.Duracao > time.Duration(c.MinutosMinimos)*time.Minute, nil
+		}
+		// Sem duração identificável não há como comprovar o limite: pergunta, se possível
+		if c.Confirmador == nil {
+			return false, nil
+		}
+		return c.Confirmador.ConfirmarIntervaloOpcional(modal)
+	case IntervaloPerguntar, "":
+		if c.Confirmador == nil {
+			return false, &ErroPonto{
+				Tipo:     "modal",
+				Mensagem: "intervalo opcional requer confirmação, mas nenhum confirmador foi configurado",
+			}
+		}
+		return c.Confirmador.ConfirmarIntervaloOpcional(modal)
+	default:
+		return false, &ErroPonto{
+			Tipo:     "modal",
+			Mensagem: fmt.Sprintf("política de intervalo opcional inválida: %q", c.Politica),
+		}
+	}
+}
+
+var (
+	regexHorasMinutos = regexp.MustCompile(`(\d{1,2})[:h](\d{2})`)
+	regexHoras        = regexp.MustCompile(`(?i)(\d+)\s*h(?:ora)?s?\b`)
+	regexMinutos      = regexp.MustCompile(`(?i)(\d+)\s*min(?:uto)?s?\b`)
+
+	// regexRotuloDuracao identifica o texto que apresenta um único "HH:MM" como duração
+	// ("duração de 01:15", "intervalo de 1h15"), e não como horário ("iniciado às 12:15")
+	regexRotuloDuracao = regexp.MustCompile(`(?i)(?:dura[çc][ãa]o|intervalo|tempo|total)(?:\s+(?:de|do|foi|é|e))*\s*:?\s*$`)
+)
+
+// extrairDuracaoIntervalo tenta identificar a duração do intervalo no texto do modal,
+// aceitando os formatos "01:15", "1h15", "1 hora e 15 minutos" e "75 min".
+// Quando o texto traz dois horários ("entre 12:00 e 13:15"), usa a diferença entre eles.
+// Um único "HH:MM" só é duração quando precedido de "duração"/"intervalo de"; sozinho é
+// um horário, e o resultado zero indica duração desconhecida.
+func extrairDuracaoIntervalo(conteudo string) time.Duration {
+	posicoes := regexHorasMinutos.FindAllStringSubmatchIndex(conteudo, 2)
+	if len(posicoes) > 0 {
+		duracoes := make([]time.Duration, len(posicoes))
+		for i, p := range posicoes {
+			horas, _ := strconv.Atoi(conteudo[p[2]:p[3]])
+			minutos, _ := strconv.Atoi(conteudo[p[4]:p[5]])
+			duracoes[i] = time.Duration(horas)*time.Hour + time.Duration(minutos)*time.Minute
+		}
+		if len(duracoes) == 2 && duracoes[1] > duracoes[0] {
+			return duracoes[1] - duracoes[0]
+		}
+		if regexRotuloDuracao.MatchString(conteudo[:posicoes[0][0]]) {
+			return duracoes[0]
+		}
+	}
+
+	var duracao time.Duration
+	if m := regexHoras.FindStringSubmatch(conteudo); m != nil {
+		horas, _ := strconv.Atoi(m[1])
+		duracao += time.Duration(horas) * time.Hour
+	}
+	if m := regexMinutos.FindStringSubmatch(conteudo); m != nil {
+		minutos, _ := strconv.Atoi(m[1])
+		duracao += time.Duration(minutos) * time.Minute
+	}
+	return duracao
+}
@@ -0,0 +1,77 @@
+package clockin
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExtrairDuracaoIntervalo(t *testing.T) {
+	casos := []struct {
+		conteudo string
+		esperado time.Duration
+	}{
+		{"Intervalo entre 12:00 e 13:15", 75 * time.Minute},
+		{"Saída às 12:10, retorno às 13:05", 55 * time.Minute},
+		{"Intervalo iniciado às 12:15", 0},
+		{"Intervalo iniciado às 12h15. Deseja considerar como intervalo opcional?", 0},
+		{"Duração do intervalo: 01:15", 75 * time.Minute},
+		{"Intervalo de 1h15", 75 * time.Minute},
+		{"Tempo total de 00:45", 45 * time.Minute},
+		{"Você ficou 1 hora e 15 minutos fora", 75 * time.Minute},
+		{"Intervalo de 75 min", 75 * time.Minute},
+		{"Intervalo iniciado às 12:15, duração de 40 minutos", 40 * time.Minute},
+		{"Deseja considerar este período como intervalo opcional?", 0},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.conteudo, func(t *testing.T) {
+			if obtido := extrairDuracaoIntervalo(caso.conteudo); obtido != caso.esperado {
+				t.Errorf("extrairDuracaoIntervalo(%q) = %s, esperado %s", caso.conteudo, obtido, caso.esperado)
+			}
+		})
+	}
+}
+
+type confirmadorFixo struct {
+	resposta  bool
+	consultas int
+}
+
+func (c *confirmadorFixo) ConfirmarIntervaloOpcional(ModalIntervalo) (bool, error) {
+	c.consultas++
+	return c.resposta, nil
+}
+
+func TestDecidirAcimaDeMinutos(t *testing.T) {
+	casos := []struct {
+		nome        string
+		duracao     time.Duration
+		confirmador *confirmadorFixo
+		esperado    bool
+		consultas   int
+	}{
+		{"acima do limite", 75 * time.Minute, &confirmadorFixo{}, true, 0},
+		{"abaixo do limite", 40 * time.Minute, &confirmadorFixo{resposta: true}, false, 0},
+		{"duração desconhecida pergunta", 0, &confirmadorFixo{resposta: true}, true, 1},
+		{"duração desconhecida sem confirmador", 0, nil, false, 0},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			config := ConfigIntervalo{Politica: IntervaloAcimaDeMinutos, MinutosMinimos: 60}
+			if caso.confirmador != nil {
+				config.Confirmador = caso.confirmador
+			}
+			resposta, err := config.decidir(ModalIntervalo{Duracao: caso.duracao})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resposta != caso.esperado {
+				t.Errorf("resposta = %v, esperado %v", resposta, caso.esperado)
+			}
+			if caso.confirmador != nil && caso.confirmador.consultas != caso.consultas {
+				t.Errorf("confirmador consultado %d vezes, esperado %d", caso.confirmador.consultas, caso.consultas)
+			}
+		})
+	}
+}
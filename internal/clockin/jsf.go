@@ -0,0 +1,292 @@
+package clockin
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const campoViewState = "javax.faces.ViewState"
+
+// documentoJSF mantém a árvore HTML da página atual e aplica as atualizações parciais
+// recebidas nas respostas AJAX do JSF/PrimeFaces
+type documentoJSF struct {
+	raiz *html.Node
+}
+
+func parseDocumentoJSF(r io.Reader) (*documentoJSF, error) {
+	raiz, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar HTML: %w", err)
+	}
+	return &documentoJSF{raiz: raiz}, nil
+}
+
+// buscar retorna o primeiro elemento que corresponde ao seletor CSS a partir de no
+func buscar(no *html.Node, seletor string) *html.Node {
+	sel, err := cascadia.Compile(seletor)
+	if err != nil || no == nil {
+		return nil
+	}
+	return sel.MatchFirst(no)
+}
+
+// buscarTodos retorna todos os elementos que correspondem ao seletor CSS a partir de no
+func buscarTodos(no *html.Node, seletor string) []*html.Node {
+	sel, err := cascadia.Compile(seletor)
+	if err != nil || no == nil {
+		return nil
+	}
+	return sel.MatchAll(no)
+}
+
+func (d *documentoJSF) buscar(seletor string) *html.Node {
+	return buscar(d.raiz, seletor)
+}
+
+func (d *documentoJSF) buscarTodos(seletor string) []*html.Node {
+	return buscarTodos(d.raiz, seletor)
+}
+
+// porID localiza um elemento pelo id, que no JSF costuma conter ":" e não é um seletor CSS válido sem escape
+func (d *documentoJSF) porID(id string) *html.Node {
+	var encontrado *html.Node
+	var percorrer func(*html.Node)
+	percorrer = func(n *html.Node) {
+		if encontrado != nil {
+			return
+		}
+		if n.Type == html.ElementNode && atributo(n, "id") == id {
+			encontrado = n
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			percorrer(c)
+		}
+	}
+	percorrer(d.raiz)
+	return encontrado
+}
+
+// substituir troca o elemento com o id informado pelo HTML recebido na atualização parcial
+func (d *documentoJSF) substituir(id, conteudo string) error {
+	antigo := d.porID(id)
+	if antigo == nil || antigo.Parent == nil {
+		return fmt.Errorf("elemento %q não encontrado para atualização", id)
+	}
+
+	novos, err := html.ParseFragment(strings.NewReader(conteudo), antigo.Parent)
+	if err != nil {
+		return fmt.Errorf("erro ao interpretar atualização de %q: %w", id, err)
+	}
+
+	for _, novo := range novos {
+		antigo.Parent.InsertBefore(novo, antigo)
+	}
+	antigo.Parent.RemoveChild(antigo)
+	return nil
+}
+
+// definirViewState atualiza o ViewState de todos os formulários da página
+func (d *documentoJSF) definirViewState(valor string) {
+	for _, input := range d.buscarTodos("input") {
+		if atributo(input, "name") != campoViewState {
+			continue
+		}
+		for i, attr := range input.Attr {
+			if attr.Key == "value" {
+				input.Attr[i].Val = valor
+			}
+		}
+	}
+}
+
+// atributo retorna o valor do atributo do elemento, ou vazio
+func atributo(n *html.Node, nome string) string {
+	if n == nil {
+		return ""
+	}
+	for _, attr := range n.Attr {
+		if attr.Key == nome {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// possuiClasse verifica se o elemento possui a classe CSS informada
+func possuiClasse(n *html.Node, classe string) bool {
+	for _, c := range strings.Fields(atributo(n, "class")) {
+		if c == classe {
+			return true
+		}
+	}
+	return false
+}
+
+// texto retorna o conteúdo textual do elemento com espaços normalizados
+func texto(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	var percorrer func(*html.Node)
+	percorrer = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		}
+		if n.Type == html.ElementNode && (n.DataAtom == atom.Script || n.DataAtom == atom.Style) {
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			percorrer(c)
+		}
+	}
+	percorrer(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// ancestral retorna o primeiro ancestral do elemento com a tag informada
+func ancestral(n *html.Node, tag atom.Atom) *html.Node {
+	for p := n; p != nil; p = p.Parent {
+		if p.Type == html.ElementNode && p.DataAtom == tag {
+			return p
+		}
+	}
+	return nil
+}
+
+// oculto indica se o elemento ou algum ancestral está oculto por estilo inline ou atributo
+func oculto(n *html.Node) bool {
+	for p := n; p != nil; p = p.Parent {
+		if p.Type != html.ElementNode {
+			continue
+		}
+		estilo := strings.ReplaceAll(atributo(p, "style"), " ", "")
+		if strings.Contains(estilo, "display:none") {
+			return true
+		}
+		if _, ok := atributoExiste(p, "hidden"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func atributoExiste(n *html.Node, nome string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Key == nome {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// desabilitado indica se o botão está desabilitado
+func desabilitado(n *html.Node) bool {
+	_, ok := atributoExiste(n, "disabled")
+	return ok || possuiClasse(n, "ui-state-disabled")
+}
+
+// camposFormulario coleta os campos do formulário como seriam enviados pelo navegador,
+// sem incluir botões
+func camposFormulario(form *html.Node) url.Values {
+	valores := url.Values{}
+	if nome := atributo(form, "id"); nome != "" {
+		valores.Set(nome, nome)
+	}
+
+	for _, campo := range buscarTodos(form, "input, select, textarea") {
+		nome := atributo(campo, "name")
+		if nome == "" {
+			continue
+		}
+		if _, ok := atributoExiste(campo, "disabled"); ok {
+			continue
+		}
+
+		switch campo.DataAtom {
+		case atom.Input:
+			switch strings.ToLower(atributo(campo, "type")) {
+			case "submit", "button", "image", "reset", "file":
+				continue
+			case "checkbox", "radio":
+				if _, marcado := atributoExiste(campo, "checked"); !marcado {
+					continue
+				}
+				valor, ok := atributoExiste(campo, "value")
+				if !ok {
+					valor = "on"
+				}
+				valores.Add(nome, valor)
+			default:
+				valores.Add(nome, atributo(campo, "value"))
+			}
+		case atom.Select:
+			for _, opcao := range buscarTodos(campo, "option[selected]") {
+				valores.Add(nome, atributo(opcao, "value"))
+			}
+		case atom.Textarea:
+			valores.Add(nome, texto(campo))
+		}
+	}
+	return valores
+}
+
+// respostaParcial representa o XML <partial-response> do JSF
+type respostaParcial struct {
+	XMLName      xml.Name `xml:"partial-response"`
+	Atualizacoes []struct {
+		ID       string `xml:"id,attr"`
+		Conteudo string `xml:",chardata"`
+	} `xml:"changes>update"`
+	Avaliacoes []string `xml:"changes>eval"`
+	Redirect   *struct {
+		URL string `xml:"url,attr"`
+	} `xml:"redirect"`
+	Erro *struct {
+		Nome     string `xml:"error-name"`
+		Mensagem string `xml:"error-message"`
+	} `xml:"error"`
+}
+
+func parseRespostaParcial(r io.Reader) (*respostaParcial, error) {
+	var resposta respostaParcial
+	if err := xml.NewDecoder(r).Decode(&resposta); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar resposta parcial: %w", err)
+	}
+	return &resposta, nil
+}
+
+// sessaoExpirada indica se o servidor rejeitou a requisição por expiração da view
+func (r *respostaParcial) sessaoExpirada() bool {
+	return r.Erro != nil && strings.Contains(r.Erro.Nome, "ViewExpired")
+}
+
+// aplicar atualiza o documento com o conteúdo da resposta parcial
+func (r *respostaParcial) aplicar(d *documentoJSF) error {
+	for _, atualizacao := range r.Atualizacoes {
+		switch {
+		case strings.Contains(atualizacao.ID, campoViewState):
+			d.definirViewState(strings.TrimSpace(atualizacao.Conteudo))
+		case atualizacao.ID == "javax.faces.ViewRoot":
+			novo, err := parseDocumentoJSF(strings.NewReader(atualizacao.Conteudo))
+			if err != nil {
+				return err
+			}
+			d.raiz = novo.raiz
+		default:
+			if err := d.substituir(atualizacao.ID, atualizacao.Conteudo); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
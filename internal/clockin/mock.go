@@ -3,38 +3,103 @@ package clockin
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
 )
 
+func init() {
+	Registrar(Provedor{
+		Nome:        ProvedorMock,
+		Descricao:   "simulação para desenvolvimento",
+		Capacidades: Capacidades{Localizacoes: true, Historico: true, Modal: true},
+		Criar: func(ctx context.Context, config Config) (Module, error) {
+			return NewMockPonto(ctx, config), nil
+		},
+	})
+}
+
+// CenarioMock roteiriza o MockPonto para que demonstrações e testes sejam reproduzíveis.
+// O valor zero não injeta falhas, usa as localizações de exemplo e o relógio real.
+type CenarioMock struct {
+	// Semente inicializa o sorteio das falhas aleatórias
+	Semente int64
+	// ProbabilidadeFalha é a chance (0 a 1) de cada chamada falhar; 0 desabilita
+	ProbabilidadeFalha float64
+	// Falhas lista, pelo nome do método de Module (ex.: "ExecutarOperacao"),
+	// os números das chamadas que falham, a partir de 1
+	Falhas map[string][]int
+	// Localizacoes disponíveis; vazio usa as localizações de exemplo
+	Localizacoes []string
+	// LocalizacaoInicial é a localização selecionada ao iniciar; vazio usa a primeira
+	LocalizacaoInicial string
+	// Operacoes define as operações disponíveis após cada marcação: o item i vale depois de
+	// i marcações e o último se repete. Vazio usa a regra por horário do relógio.
+	Operacoes [][]TipoOperacao
+	// Inicio fixa o relógio do mock quando Config.Relogio não é informado; zero usa o horário real
+	Inicio time.Time
+	// AvancoPorMarcacao adianta o relógio, se simulado, a cada marcação executada
+	AvancoPorMarcacao time.Duration
+}
+
+// localizacoesExemplo são usadas quando o cenário não define localizações
+var localizacoesExemplo = []string{"Home Office", "Escritório RJ", "Escritório SP"}
+
 // MockPonto implements the Module interface for testing and development
 type MockPonto struct {
 	ctx              context.Context
+	cenario          CenarioMock
+	catalogo         *Catalogo
+	falhas           *common.InjetorFalhas
+	relogio          common.Relogio
 	localizacaoAtual string
 	localizacoes     []Localizacao
 	operacoes        []TipoOperacao
+	intervalo        ConfigIntervalo
+	ultimaOperacao   *TipoOperacao
+	ultimaMarcacao   time.Time
+	marcacoes        []Marcacao
 }
 
-// NewMockPonto creates a new mock clock-in module
-func NewMockPonto(ctx context.Context) Module {
+// NewMockPonto creates a new mock clock-in module driven by config.CenarioMock
+func NewMockPonto(ctx context.Context, config Config) Module {
+	cenario := config.CenarioMock
 	mock := &MockPonto{
-		ctx: ctx,
-		localizacoes: []Localizacao{
-			{Nome: "Home Office", Valor: "1"},
-			{Nome: "Escritório RJ", Valor: "2"},
-			{Nome: "Escritório SP", Valor: "3"},
-		},
+		ctx:       ctx,
+		cenario:   cenario,
+		falhas:    common.NovoInjetorFalhas(cenario.Semente, cenario.ProbabilidadeFalha, cenario.Falhas),
+		catalogo:  config.Catalogo,
+		relogio:   common.RelogioOuSistema(config.Relogio),
+		intervalo: config.Intervalo,
+	}
+	if config.Relogio == nil && !cenario.Inicio.IsZero() {
+		mock.relogio = common.NovoRelogioSimulado(cenario.Inicio, false)
+	}
+
+	nomes := cenario.Localizacoes
+	if len(nomes) == 0 {
+		nomes = localizacoesExemplo
+	}
+	for i, nome := range nomes {
+		mock.localizacoes = append(mock.localizacoes, Localizacao{Nome: nome, Valor: fmt.Sprint(i + 1)})
+	}
+
+	mock.localizacaoAtual = cenario.LocalizacaoInicial
+	if mock.localizacaoAtual == "" {
+		mock.localizacaoAtual = mock.localizacoes[0].Nome
 	}
-	mock.localizacaoAtual = mock.localizacoes[0].Nome
 	mock.atualizarOperacoesDisponiveis()
 	return mock
 }
 
+// agora retorna o horário do relógio do mock
+func (m *MockPonto) agora() time.Time {
+	return m.relogio.Agora()
+}
+
 // ObterLocalizacaoAtual returns the current mock location
 func (m *MockPonto) ObterLocalizacaoAtual() (string, error) {
-
-	// Simula erro aleatório (5% de chance)
-	if rand.Float32() < 0.05 {
+	if m.falhas.Falhar("ObterLocalizacaoAtual") {
 		return "", &ErroPonto{
 			Tipo:     "localizacao",
 			Mensagem: "falha ao obter localização atual",
@@ -47,8 +112,7 @@ func (m *MockPonto) ObterLocalizacaoAtual() (string, error) {
 
 // ObterLocalizacoesDisponiveis returns mock available locations
 func (m *MockPonto) ObterLocalizacoesDisponiveis() ([]Localizacao, error) {
-	// Simula erro aleatório (5% de chance)
-	if rand.Float32() < 0.05 {
+	if m.falhas.Falhar("ObterLocalizacoesDisponiveis") {
 		return nil, &ErroPonto{
 			Tipo:     "localizacao",
 			Mensagem: "falha ao obter localizações",
@@ -61,8 +125,7 @@ func (m *MockPonto) ObterLocalizacoesDisponiveis() ([]Localizacao, error) {
 
 // SelecionarLocalizacao updates the mock current location
 func (m *MockPonto) SelecionarLocalizacao(localizacao Localizacao) error {
-	// Simula erro aleatório (5% de chance)
-	if rand.Float32() < 0.05 {
+	if m.falhas.Falhar("SelecionarLocalizacao") {
 		return &ErroPonto{
 			Tipo:     "localizacao",
 			Mensagem: fmt.Sprintf("falha ao selecionar %s", localizacao.Nome),
@@ -71,43 +134,37 @@ func (m *MockPonto) SelecionarLocalizacao(localizacao Localizacao) error {
 	}
 
 	// Valida se a localização existe
-	found := false
-	for _, loc := range m.localizacoes {
-		if loc.Nome == localizacao.Nome {
-			found = true
-			break
-		}
-	}
-	if !found {
+	encontrada, ok := m.catalogo.Encontrar(m.localizacoes, localizacao.Nome)
+	if !ok {
 		return &ErroPonto{
 			Tipo:     "localizacao",
 			Mensagem: "localização inválida",
 		}
 	}
 
-	m.localizacaoAtual = localizacao.Nome
+	m.localizacaoAtual = encontrada.Nome
 	return nil
 }
 
 // ObterOperacoesDisponiveis returns mock available operations
 func (m *MockPonto) ObterOperacoesDisponiveis() ([]TipoOperacao, error) {
-	// Simula erro aleatório (5% de chance)
-	if rand.Float32() < 0.05 {
+	if m.falhas.Falhar("ObterOperacoesDisponiveis") {
 		return nil, &ErroPonto{
-			Tipo:     "validacao",
+			Tipo:     "operacoes",
 			Mensagem: "falha ao obter operações",
 			Causa:    fmt.Errorf("erro de conexão simulado"),
 		}
 	}
 
+	// A regra por horário acompanha o relógio, que pode ter avançado desde a última marcação
+	m.atualizarOperacoesDisponiveis()
 	return m.operacoes, nil
 }
 
 // ExecutarOperacao simulates executing a clock-in operation
-func (m *MockPonto) ExecutarOperacao(operacao TipoOperacao) error {
-	// Simula erro aleatório (5% de chance)
-	if rand.Float32() < 0.05 {
-		return &ErroPonto{
+func (m *MockPonto) ExecutarOperacao(operacao TipoOperacao) (*ResultadoOperacao, error) {
+	if m.falhas.Falhar("ExecutarOperacao") {
+		return nil, &ErroPonto{
 			Operacao: operacao,
 			Tipo:     "execucao",
 			Mensagem: "falha ao executar operação",
@@ -124,18 +181,66 @@ func (m *MockPonto) ExecutarOperacao(operacao TipoOperacao) error {
 		}
 	}
 	if !found {
-		return &ErroPonto{
+		return nil, &ErroPonto{
 			Operacao: operacao,
 			Tipo:     "validacao",
 			Mensagem: "operação indisponível",
 		}
 	}
 
+	resultado := &ResultadoOperacao{Operacao: operacao, Tentativas: 1}
+
+	// Simula o modal "Intervalo Opcional" ao retornar de uma saída para refeição
+	if operacao == Entrada && m.ultimaOperacao != nil && *m.ultimaOperacao == Almoco {
+		modal, err := m.simularModalIntervalo(operacao)
+		if err != nil {
+			return nil, err
+		}
+		resultado.Modal = modal
+	}
+
+	m.ultimaOperacao = &operacao
+	m.ultimaMarcacao = m.agora()
+	m.marcacoes = append(m.marcacoes, Marcacao{Operacao: operacao, Momento: m.ultimaMarcacao})
+
+	if simulado, ok := m.relogio.(*common.RelogioSimulado); ok {
+		simulado.Avancar(m.cenario.AvancoPorMarcacao)
+	}
+
 	// Atualiza operações disponíveis após executar uma operação
 	m.atualizarOperacoesDisponiveis()
 
 	fmt.Printf("\n🕒 Mock: Operação '%s' executada com sucesso\n", operacao)
-	return nil
+	return resultado, nil
+}
+
+// simularModalIntervalo aplica a política configurada a um modal fictício
+func (m *MockPonto) simularModalIntervalo(operacao TipoOperacao) (*ModalIntervalo, error) {
+	duracao := m.agora().Sub(m.ultimaMarcacao).Truncate(time.Minute)
+	modal := &ModalIntervalo{
+		Titulo:   "Intervalo Opcional",
+		Conteudo: fmt.Sprintf("Intervalo de %02d:%02d. Deseja considerar este período como intervalo opcional?", int(duracao.Hours()), int(duracao.Minutes())%60),
+		Duracao:  duracao,
+		Politica: m.intervalo.Politica,
+	}
+
+	resposta, err := m.intervalo.decidir(*modal)
+	if err != nil {
+		return nil, &ErroPonto{
+			Operacao: operacao,
+			Tipo:     "modal",
+			Mensagem: "erro na confirmação do intervalo",
+			Causa:    err,
+		}
+	}
+	modal.Resposta = resposta
+
+	return modal, nil
+}
+
+// ObterMarcacoes returns the operations executed in this mock session
+func (m *MockPonto) ObterMarcacoes() ([]Marcacao, error) {
+	return append([]Marcacao(nil), m.marcacoes...), nil
 }
 
 // Close is a no-op for the mock
@@ -143,9 +248,15 @@ func (m *MockPonto) Close() {
 	fmt.Println("\n🔌 Mock: Conexão fechada")
 }
 
-// atualizarOperacoesDisponiveis atualiza as operações disponíveis com base no horário
+// atualizarOperacoesDisponiveis atualiza as operações disponíveis com base no roteiro do
+// cenário ou, sem ele, no horário
 func (m *MockPonto) atualizarOperacoesDisponiveis() {
-	hora := time.Now().Hour()
+	if roteiro := m.cenario.Operacoes; len(roteiro) > 0 {
+		m.operacoes = roteiro[min(len(m.marcacoes), len(roteiro)-1)]
+		return
+	}
+
+	hora := m.agora().Hour()
 	m.operacoes = nil
 
 	// Simula regras de negócio para operações disponíveis
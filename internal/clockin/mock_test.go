@@ -0,0 +1,81 @@
+package clockin
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+)
+
+// TestMockDiaDeTrabalho percorre um dia inteiro de marcações no relógio simulado
+// automático, em que cada espera avança o horário sem aguardar
+func TestMockDiaDeTrabalho(t *testing.T) {
+	inicio := time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local)
+	relogio := common.NovoRelogioSimulado(inicio, true)
+	mock := NewMockPonto(context.Background(), Config{
+		Relogio:   relogio,
+		Intervalo: ConfigIntervalo{Politica: IntervaloSempreSim},
+	})
+	defer mock.Close()
+
+	// O tempo limite de uma chamada não expira nem avança o relógio enquanto ninguém espera
+	ctx, cancelar := common.ComTempoLimite(context.Background(), relogio, time.Minute)
+	defer cancelar()
+	if ctx.Err() != nil || !relogio.Agora().Equal(inicio) {
+		t.Fatalf("tempo limite expirou ao ser criado: %v, relógio em %s", ctx.Err(), relogio.Agora())
+	}
+
+	dia := []struct {
+		espera     time.Duration
+		disponivel []TipoOperacao
+		operacao   TipoOperacao
+	}{
+		{0, []TipoOperacao{Entrada, Almoco}, Entrada},
+		{4 * time.Hour, []TipoOperacao{Almoco, Saida}, Almoco},
+		{2 * time.Hour, []TipoOperacao{Entrada, Saida}, Entrada},
+		{4 * time.Hour, []TipoOperacao{Entrada, Saida}, Saida},
+	}
+	for _, etapa := range dia {
+		if err := common.Dormir(context.Background(), relogio, etapa.espera); err != nil {
+			t.Fatal(err)
+		}
+
+		operacoes, err := mock.ObterOperacoesDisponiveis()
+		if err != nil || !slices.Equal(operacoes, etapa.disponivel) {
+			t.Fatalf("às %s: operações = %v, %v, esperadas %v", relogio.Agora().Format("15:04"), operacoes, err, etapa.disponivel)
+		}
+		resultado, err := mock.ExecutarOperacao(etapa.operacao)
+		if err != nil {
+			t.Fatalf("às %s: ExecutarOperacao(%s): %v", relogio.Agora().Format("15:04"), etapa.operacao, err)
+		}
+		if resultado.Modal != nil && resultado.Modal.Duracao != 2*time.Hour {
+			t.Errorf("intervalo do modal = %s, esperado 2h", resultado.Modal.Duracao)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("tempo limite não expirou após o relógio avançar o dia")
+	}
+
+	marcacoes, err := mock.(*MockPonto).ObterMarcacoes()
+	if err != nil || len(marcacoes) != len(dia) {
+		t.Fatalf("marcações = %v, %v", marcacoes, err)
+	}
+	horarios := []string{"08:00", "12:00", "14:00", "18:00"}
+	for i, m := range marcacoes {
+		if horario := m.Momento.Format("15:04"); horario != horarios[i] {
+			t.Errorf("marcação %d (%s) às %s, esperada às %s", i+1, m.Operacao, horario, horarios[i])
+		}
+	}
+
+	if err := common.Dormir(context.Background(), relogio, 2*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if operacoes, _ := mock.ObterOperacoesDisponiveis(); !slices.Equal(operacoes, []TipoOperacao{Entrada}) {
+		t.Fatalf("operações fora do expediente = %v", operacoes)
+	}
+}
@@ -2,11 +2,16 @@ package clockin
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
 	"time"
 
 	"github.com/chromedp/chromedp"
-	"github.com/manifoldco/promptui"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/snapshot"
 )
 
 type TipoOperacao int
@@ -36,6 +41,22 @@ func (e *ErroPonto) Error() string {
 	return e.Mensagem
 }
 
+func (e *ErroPonto) Unwrap() error {
+	return e.Causa
+}
+
+// Retentavel indica se a operação pode ser repetida. Erros de validação, de
+// resposta ao modal e de credenciais não mudam com novas tentativas; erros de
+// confirmação ocorrem depois do envio da marcação, e repeti-la pode duplicá-la.
+func (e *ErroPonto) Retentavel() bool {
+	switch e.Tipo {
+	case "validacao", "modal", "auth", "confirmacao":
+		return false
+	default:
+		return true
+	}
+}
+
 func (op TipoOperacao) String() string {
 	switch op {
 	case Entrada:
@@ -49,116 +70,146 @@ func (op TipoOperacao) String() string {
 	}
 }
 
+func init() {
+	Registrar(Provedor{
+		Nome:         ProvedorSofttrade,
+		Descricao:    "Softtrade pelo Chromium",
+		Capacidades:  Capacidades{Localizacoes: true, Historico: true, Modal: true},
+		UsaNavegador: true,
+		Criar: func(ctx context.Context, config Config) (Module, error) {
+			return NewGerenciadorPonto(ctx, config), nil
+		},
+	})
+}
+
 type GerenciadorPonto struct {
-	ctx context.Context
+	// aba é a aba autenticada do navegador; ctx a limita durante cada chamada pública
+	aba       context.Context
+	ctx       context.Context
+	config    Config
+	intervalo ConfigIntervalo
+	seletores selectors.Softtrade
 }
 
-func NewGerenciadorPonto(ctx context.Context) *GerenciadorPonto {
+func NewGerenciadorPonto(ctx context.Context, config Config) *GerenciadorPonto {
+	seletores := config.Seletores
+	if seletores == nil {
+		seletores = &selectors.Padrao().Softtrade
+	}
+
 	return &GerenciadorPonto{
-		ctx: ctx,
+		aba:       ctx,
+		ctx:       ctx,
+		config:    config,
+		intervalo: config.Intervalo,
+		seletores: *seletores,
 	}
 }
 
-const (
-	maxTentativas              = 10
-	tempoEsperaEntreTentativas = 500 * time.Millisecond
-)
+// tempoLimiteChamada limita cada chamada pública do GerenciadorPonto, incluindo as retentativas
+const tempoLimiteChamada = 3 * time.Minute
 
-func (g *GerenciadorPonto) aguardarAjax() chromedp.Action {
-	return chromedp.WaitNotPresent(`#j_idt113_blocker[style*="display: block"]`)
+// limitar restringe g.ctx a tempoLimiteChamada; a aba continua aberta após a chamada
+func (g *GerenciadorPonto) limitar() context.CancelFunc {
+	ctx, cancelar := common.ComTempoLimite(g.aba, g.config.Relogio, tempoLimiteChamada)
+	g.ctx = ctx
+	return func() {
+		cancelar()
+		g.ctx = g.aba
+	}
 }
 
-func (g *GerenciadorPonto) tentarOperacaoString(operacao func() (string, error)) (string, error) {
-	var resultado string
-	var ultimoErro error
-
-	for tentativa := 0; tentativa < maxTentativas; tentativa++ {
-		if tentativa > 0 {
-			time.Sleep(tempoEsperaEntreTentativas)
-		}
-
-		resultado, ultimoErro = operacao()
-		if ultimoErro == nil {
-			return resultado, nil
-		}
-	}
+// Nomes das operações usados na configuração de retentativas e nos logs
+const (
+	OpObterLocalizacao      = "obter_localizacao"
+	OpListarLocalizacoes    = "listar_localizacoes"
+	OpSelecionarLocalizacao = "selecionar_localizacao"
+	OpObterOperacoes        = "obter_operacoes"
+	OpExecutarOperacao      = "executar_operacao"
+	OpObterMarcacoes        = "obter_marcacoes"
+)
 
-	return resultado, ultimoErro
+// PoliticaRetentativaPadrao é usada nas operações sem política configurada
+var PoliticaRetentativaPadrao = common.PoliticaRetentativa{
+	MaxTentativas: 10,
+	EsperaInicial: 500 * time.Millisecond,
+	EsperaMaxima:  2 * time.Second,
+	Multiplicador: 1.5,
+	Jitter:        common.Fracao(0.2),
 }
 
-func (g *GerenciadorPonto) tentarOperacaoLocalizacoes(operacao func() ([]Localizacao, error)) ([]Localizacao, error) {
-	var resultado []Localizacao
-	var ultimoErro error
+func (g *GerenciadorPonto) aguardarAjax() chromedp.Action {
+	return chromedp.WaitNotPresent(g.seletores.BloqueioAjax + `[style*="display: block"]`)
+}
 
-	for tentativa := 0; tentativa < maxTentativas; tentativa++ {
-		if tentativa > 0 {
-			time.Sleep(tempoEsperaEntreTentativas)
+// scriptMonitorAjax registra em window.__batedorAjax.erro o nome do erro da última resposta
+// parcial do JSF (ViewExpiredException, ...), "redirect" se ela mandou trocar de página, ou
+// vazio se ela foi aceita. Respostas com erro não atualizam a página, e sem o registro a
+// marcação recusada pareceria concluída.
+const scriptMonitorAjax = `
+	(function() {
+		if (!window.__batedorAjax) {
+			window.__batedorAjax = {erro: ''};
+			const registrar = texto => {
+				if (typeof texto !== 'string' || texto.indexOf('<partial-response') < 0) return;
+				const erro = texto.match(/<error-name>([^<]*)<\/error-name>/);
+				window.__batedorAjax.erro = erro ? erro[1] : (texto.indexOf('<redirect') >= 0 ? '` + erroRedirecionamento + `' : '');
+			};
+			const enviar = XMLHttpRequest.prototype.send;
+			XMLHttpRequest.prototype.send = function() {
+				this.addEventListener('load', () => registrar(this.responseText));
+				return enviar.apply(this, arguments);
+			};
+			const buscar = window.fetch;
+			window.fetch = function() {
+				return buscar.apply(this, arguments).then(r => r.clone().text().then(texto => {
+					registrar(texto);
+					return r;
+				}, () => r));
+			};
 		}
+		window.__batedorAjax.erro = '';
+	})()
+`
 
-		resultado, ultimoErro = operacao()
-		if ultimoErro == nil {
-			return resultado, nil
-		}
-	}
+// erroRedirecionamento é o erro registrado quando a resposta parcial troca de página, o que o
+// Softtrade faz ao encerrar a sessão
+const erroRedirecionamento = "redirect"
 
-	return resultado, ultimoErro
+// rotulo retorna o texto do botão que executa a operação
+func (g *GerenciadorPonto) rotulo(operacao TipoOperacao) string {
+	return rotuloOperacao(g.seletores, operacao)
 }
 
-func (g *GerenciadorPonto) tentarOperacaoOperacoes(operacao func() ([]TipoOperacao, error)) ([]TipoOperacao, error) {
-	var resultado []TipoOperacao
-	var ultimoErro error
-
-	for tentativa := 0; tentativa < maxTentativas; tentativa++ {
-		if tentativa > 0 {
-			time.Sleep(tempoEsperaEntreTentativas)
-		}
-
-		resultado, ultimoErro = operacao()
-		if ultimoErro == nil {
-			return resultado, nil
-		}
+// gravarSnapshot salva a página atual quando a gravação de snapshots está habilitada
+func (g *GerenciadorPonto) gravarSnapshot(pagina string, resultado any) {
+	if err := g.config.Gravador.Gravar(g.ctx, pagina, resultado); err != nil {
+		log.Printf("falha ao gravar snapshot: %v", err)
 	}
-
-	return resultado, ultimoErro
 }
 
-func (g *GerenciadorPonto) tentarOperacaoBool(operacao func() (bool, error)) (bool, error) {
-	var resultado bool
-	var ultimoErro error
-
-	for tentativa := 0; tentativa < maxTentativas; tentativa++ {
-		if tentativa > 0 {
-			time.Sleep(tempoEsperaEntreTentativas)
-		}
-
-		resultado, ultimoErro = operacao()
-		if ultimoErro == nil {
-			return resultado, nil
-		}
-	}
+// politica retorna a política de retentativas configurada para a operação
+func (c Config) politica(operacao string) common.PoliticaRetentativa {
+	return c.Retentativas[operacao].Mesclar(PoliticaRetentativaPadrao)
+}
 
-	return resultado, ultimoErro
+// tentar executa a operação conforme a política configurada para nome
+func tentar[T any](ctx context.Context, config Config, nome string, operacao func() (T, error)) (T, int, error) {
+	return common.Retentar(ctx, config.Relogio, nome, config.politica(nome), operacao)
 }
 
 func (g *GerenciadorPonto) obterLocalizacaoAtual() (string, error) {
-	return g.tentarOperacaoString(func() (string, error) {
-		var localizacaoAtual string
-		err := chromedp.Run(g.ctx,
+	localizacao, _, err := tentar(g.ctx, g.config, OpObterLocalizacao, func() (string, error) {
+		err := common.Executar(g.ctx,
 			g.aguardarAjax(),
-			chromedp.WaitReady("#formMarc"),
-			chromedp.Evaluate(`
-				(function() {
-					const btn = document.querySelector('#formMarc\\:btnLoc');
-					if (!btn) return '';
-					
-					btn.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
-					
-					const textoLoc = btn.querySelector('.loc-text');
-					return (textoLoc && textoLoc.textContent) ? textoLoc.textContent.trim() : btn.textContent.trim();
-				})()
-			`, &localizacaoAtual),
+			chromedp.WaitReady(g.seletores.Formulario),
 		)
 
+		var localizacaoAtual string
+		if err == nil {
+			localizacaoAtual, err = ExtrairLocalizacaoAtual(g.ctx, g.seletores)
+		}
+
 		if err != nil {
 			return "", &ErroPonto{
 				Tipo:     "localizacao",
@@ -174,44 +225,24 @@ func (g *GerenciadorPonto) obterLocalizacaoAtual() (string, error) {
 			}
 		}
 
+		g.gravarSnapshot(snapshot.PaginaLocalizacaoAtual, localizacaoAtual)
 		return localizacaoAtual, nil
 	})
+	return localizacao, err
 }
 
 func (g *GerenciadorPonto) obterLocalizacoesDisponiveis() ([]Localizacao, error) {
-	return g.tentarOperacaoLocalizacoes(func() ([]Localizacao, error) {
-		var localizacoes []Localizacao
-		err := chromedp.Run(g.ctx,
+	localizacoes, _, err := tentar(g.ctx, g.config, OpListarLocalizacoes, func() ([]Localizacao, error) {
+		err := common.Executar(g.ctx,
 			g.aguardarAjax(),
-			chromedp.WaitReady("#formMarc"),
-			chromedp.Evaluate(`
-				(function() {
-					const btnLoc = document.querySelector('#formMarc\\:btnLoc');
-					if (!btnLoc) return [];
-					
-					btnLoc.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
-					btnLoc.click();
-					
-					const tabela = document.querySelector('#formMarc\\:dtLoc');
-					if (!tabela) return [];
-					
-					tabela.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
-					const tbody = tabela.querySelector('tbody');
-					if (tbody) {
-						tbody.style.cssText = 'display:table-row-group !important; visibility:visible !important; opacity:1 !important';
-					}
-					
-					const celulas = Array.from(tabela.querySelectorAll('tbody tr td'));
-					return celulas
-						.map((td, index) => {
-							const texto = td.textContent.trim();
-							return texto ? {nome: texto, valor: (index + 1).toString()} : null;
-						})
-						.filter(item => item !== null);
-				})()
-			`, &localizacoes),
+			chromedp.WaitReady(g.seletores.Formulario),
 		)
 
+		var localizacoes []Localizacao
+		if err == nil {
+			localizacoes, err = ExtrairLocalizacoes(g.ctx, g.seletores)
+		}
+
 		if err != nil {
 			return nil, &ErroPonto{
 				Tipo:     "localizacao",
@@ -227,26 +258,31 @@ func (g *GerenciadorPonto) obterLocalizacoesDisponiveis() ([]Localizacao, error)
 			}
 		}
 
+		g.gravarSnapshot(snapshot.PaginaLocalizacoes, localizacoes)
 		return localizacoes, nil
 	})
+	return localizacoes, err
 }
 
 func (g *GerenciadorPonto) selecionarLocalizacao(localizacao Localizacao) error {
-	_, err := g.tentarOperacaoBool(func() (bool, error) {
-		var sucesso bool
-		err := chromedp.Run(g.ctx,
+	_, _, err := tentar(g.ctx, g.config, OpSelecionarLocalizacao, func() (bool, error) {
+		var modo string
+		err := common.Executar(g.ctx,
 			g.aguardarAjax(),
-			chromedp.WaitReady("#formMarc"),
-			chromedp.Evaluate(`
+			chromedp.WaitReady(g.seletores.Formulario),
+			chromedp.Evaluate(fmt.Sprintf(`
 				(function() {
-					const btnLoc = document.querySelector('#formMarc\\:btnLoc');
-					if (!btnLoc) return false;
+					%s
+					const rotulos = %s;
+					const valor = %s;
+					const btnLoc = document.querySelector(%s);
+					if (!btnLoc) return '';
 					
 					btnLoc.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
 					btnLoc.click();
 					
-					const tabela = document.querySelector('#formMarc\\:dtLoc');
-					if (!tabela) return false;
+					const tabela = document.querySelector(%s);
+					if (!tabela) return '';
 					
 					tabela.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
 					const tbody = tabela.querySelector('tbody');
@@ -256,24 +292,25 @@ func (g *GerenciadorPonto) selecionarLocalizacao(localizacao Localizacao) error
 					
 					const celulas = tabela.querySelectorAll('tbody tr td');
 					for (const celula of celulas) {
-						if (celula.textContent.trim() === '`+localizacao.Nome+`') {
+						if (rotulos.includes(normalizar(celula.textContent))) {
 							celula.click();
-							return true;
+							return 'nome';
 						}
 					}
 					
-					const celulaAlvo = tabela.querySelector('tbody tr:nth-child(' + localizacao.Valor + ') td');
+					const celulaAlvo = tabela.querySelector('tbody tr:nth-child(' + valor + ') td');
 					if (celulaAlvo) {
 						celulaAlvo.click();
-						return true;
+						return 'posicao';
 					}
 					
-					return false;
+					return '';
 				})()
-			`, &sucesso),
+			`, funcaoNormalizar, selectors.JSLista(g.config.Catalogo.Rotulos(localizacao.Nome)), selectors.JS(localizacao.Valor),
+				selectors.JS(g.seletores.BotaoLocalizacao), selectors.JS(g.seletores.TabelaLocalizacoes)), &modo),
 		)
 
-		if err != nil || !sucesso {
+		if err != nil || modo == "" {
 			return false, &ErroPonto{
 				Tipo:     "localizacao",
 				Mensagem: fmt.Sprintf("falha ao selecionar %s", localizacao.Nome),
@@ -281,90 +318,105 @@ func (g *GerenciadorPonto) selecionarLocalizacao(localizacao Localizacao) error
 			}
 		}
 
-		return true, chromedp.Run(g.ctx, g.aguardarAjax())
+		if modo == "posicao" {
+			avisarSelecaoPorPosicao(localizacao)
+		}
+
+		if err := common.Executar(g.ctx, g.aguardarAjax()); err != nil {
+			return false, &ErroPonto{
+				Tipo:     "localizacao",
+				Mensagem: "falha ao aguardar seleção da localização",
+				Causa:    err,
+			}
+		}
+
+		return true, nil
 	})
 
 	return err
 }
 
 func (g *GerenciadorPonto) obterOperacoesDisponiveis() ([]TipoOperacao, error) {
-	return g.tentarOperacaoOperacoes(func() ([]TipoOperacao, error) {
-		var operacoesStr []string
-		err := chromedp.Run(g.ctx,
+	operacoes, _, err := tentar(g.ctx, g.config, OpObterOperacoes, g.lerOperacoes)
+	return operacoes, err
+}
+
+// lerOperacoes lê uma única vez as operações oferecidas pela página
+func (g *GerenciadorPonto) lerOperacoes() ([]TipoOperacao, error) {
+	err := common.Executar(g.ctx,
+		g.aguardarAjax(),
+		chromedp.WaitReady(g.seletores.Formulario),
+	)
+
+	var operacoes []TipoOperacao
+	if err == nil {
+		operacoes, err = ExtrairOperacoes(g.ctx, g.seletores)
+	}
+
+	if err != nil {
+		return nil, &ErroPonto{
+			Tipo:     "operacoes",
+			Mensagem: "falha ao obter operações",
+			Causa:    err,
+		}
+	}
+
+	g.gravarSnapshot(snapshot.PaginaOperacoes, operacoes)
+	return operacoes, nil
+}
+
+func (g *GerenciadorPonto) obterMarcacoes() ([]Marcacao, error) {
+	marcacoes, _, err := tentar(g.ctx, g.config, OpObterMarcacoes, func() ([]Marcacao, error) {
+		err := common.Executar(g.ctx,
 			g.aguardarAjax(),
-			chromedp.WaitReady("#formMarc"),
-			chromedp.Evaluate(`
-				(function() {
-					const botoes = Array.from(document.querySelectorAll('button'));
-					const tipos = ['Entrada', 'Saída refeição/descanso', 'Saída'];
-					
-					// Força a atualização do display dos botões
-					botoes.forEach(btn => {
-						if (btn.offsetParent !== null) {
-							btn.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
-						}
-					});
-
-					return tipos.filter(tipo => {
-						const btn = botoes.find(b => 
-							b.textContent.includes(tipo) && 
-							!b.disabled && 
-							b.offsetParent !== null &&
-							window.getComputedStyle(b).display !== 'none'
-						);
-						if (btn) {
-							btn.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
-							return true;
-						}
-						return false;
-					});
-				})()
-			`, &operacoesStr),
+			chromedp.WaitReady(g.seletores.Formulario),
 		)
 
+		var marcacoes []Marcacao
+		if err == nil {
+			marcacoes, err = ExtrairMarcacoes(g.ctx, g.seletores, common.RelogioOuSistema(g.config.Relogio).Agora())
+		}
+
 		if err != nil {
 			return nil, &ErroPonto{
-				Tipo:     "validacao",
-				Mensagem: "falha ao obter operações",
+				Tipo:     "historico",
+				Mensagem: "falha ao obter marcações do dia",
 				Causa:    err,
 			}
 		}
+		return marcacoes, nil
+	})
+	return marcacoes, err
+}
 
-		var operacoes []TipoOperacao
-		for _, op := range operacoesStr {
-			switch op {
-			case "Entrada":
-				operacoes = append(operacoes, Entrada)
-			case "Saída refeição/descanso":
-				operacoes = append(operacoes, Almoco)
-			case "Saída":
-				operacoes = append(operacoes, Saida)
+func (g *GerenciadorPonto) executarOperacao(operacao TipoOperacao) (*ResultadoOperacao, error) {
+	primeira := true
+	_, tentativas, err := tentar(g.ctx, g.config, OpExecutarOperacao, func() (bool, error) {
+		// Um clique que falhou pode ter chegado ao servidor: antes de repetir, confere se a
+		// operação continua disponível
+		if !primeira {
+			if err := g.confirmarOperacaoDisponivel(operacao); err != nil {
+				return false, err
 			}
 		}
+		primeira = false
 
-		return operacoes, nil
-	})
-}
-
-func (g *GerenciadorPonto) executarOperacao(operacao TipoOperacao) error {
-	_, err := g.tentarOperacaoBool(func() (bool, error) {
 		var clicado bool
-		err := chromedp.Run(g.ctx,
+		err := common.Executar(g.ctx,
 			g.aguardarAjax(),
-			chromedp.WaitReady("#formMarc"),
+			chromedp.WaitReady(g.seletores.Formulario),
+			chromedp.Evaluate(scriptMonitorAjax, nil),
 			chromedp.Evaluate(fmt.Sprintf(`
 				(function() {
-					const botoes = document.querySelectorAll('button');
-					for (const btn of botoes) {
-						if (btn.textContent.includes('%s') && !btn.disabled) {
-							btn.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
-							btn.click();
-							return true;
-						}
-					}
-					return false;
+					%s
+					const botoes = Array.from(document.querySelectorAll('button')).filter(b => !b.disabled);
+					const btn = botaoDoRotulo(botoes, %s, %s);
+					if (!btn) return false;
+					btn.style.cssText = 'display:block !important; visibility:visible !important; opacity:1 !important';
+					btn.click();
+					return true;
 				})()
-			`, operacao.String()), &clicado),
+			`, funcaoBotaoDoRotulo, selectors.JS(g.rotulo(operacao)), selectors.JSLista(rotulosOperacoes(g.seletores))), &clicado),
 		)
 
 		if err != nil {
@@ -384,108 +436,240 @@ func (g *GerenciadorPonto) executarOperacao(operacao TipoOperacao) error {
 			}
 		}
 
-		if err := chromedp.Run(g.ctx, g.aguardarAjax()); err != nil {
-			return false, err
+		// O clique já enviou a marcação: uma resposta lenta ou perdida não pode provocar
+		// um segundo clique
+		var erroAjax string
+		err = common.Executar(g.ctx,
+			g.aguardarAjax(),
+			chromedp.Evaluate(`window.__batedorAjax ? window.__batedorAjax.erro : '`+erroRedirecionamento+`'`, &erroAjax),
+		)
+		if err != nil {
+			return false, &ErroPonto{
+				Operacao: operacao,
+				Tipo:     "confirmacao",
+				Mensagem: "marcação enviada, mas a confirmação não chegou; confira o ponto antes de repetir",
+				Causa:    err,
+			}
+		}
+
+		// A resposta trocou de página em vez de atualizá-la; sem o monitor, a aba já está na
+		// página nova. Não há como saber se a marcação foi registrada, então ela não é repetida.
+		if erroAjax == erroRedirecionamento {
+			return false, &ErroPonto{
+				Operacao: operacao,
+				Tipo:     "confirmacao",
+				Mensagem: "o Softtrade trocou de página em vez de confirmar a marcação; a sessão pode ter expirado",
+			}
+		}
+
+		// O JSF recusou a requisição sem registrá-la: recarrega a página para obter um
+		// ViewState válido antes de repetir
+		if erroAjax != "" {
+			if err := common.Executar(g.ctx, chromedp.Reload(), chromedp.WaitReady(g.seletores.Formulario)); err != nil {
+				return false, &ErroPonto{Operacao: operacao, Tipo: "sessao", Mensagem: "falha ao recarregar a página", Causa: err}
+			}
+			return false, &ErroPonto{
+				Operacao: operacao,
+				Tipo:     "sessao",
+				Mensagem: "o Softtrade recusou a marcação",
+				Causa:    errors.New(erroAjax),
+			}
 		}
 
-		return true, g.tratarModalIntervalo()
+		return true, nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return err
+	// O modal é tratado fora das tentativas para que uma falha ao respondê-lo
+	// não provoque um novo clique no botão de marcação
+	modal, err := g.tratarModalIntervalo(operacao)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResultadoOperacao{
+		Operacao:   operacao,
+		Modal:      modal,
+		Tentativas: tentativas,
+	}, nil
+}
+
+// confirmarOperacaoDisponivel relê as operações oferecidas pela página e falha com o tipo
+// "confirmacao" se a operação não estiver mais entre elas, indicando que a tentativa
+// anterior pode ter registrado a marcação
+func (g *GerenciadorPonto) confirmarOperacaoDisponivel(operacao TipoOperacao) error {
+	// Uma única leitura: a tentativa de executar_operacao em andamento já é repetida
+	operacoes, err := g.lerOperacoes()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(operacoes, operacao) {
+		return &ErroPonto{
+			Operacao: operacao,
+			Tipo:     "confirmacao",
+			Mensagem: "a operação não é mais oferecida; a tentativa anterior pode ter registrado a marcação",
+		}
+	}
+	return nil
 }
 
-func (g *GerenciadorPonto) tratarModalIntervalo() error {
+// marcarBotaoModal identifica o botão do modal "Intervalo Opcional" com o rótulo informado
+// e retorna um seletor CSS que o localiza. A marcação é necessária porque seletores CSS
+// não permitem filtrar pelo texto do elemento.
+func (g *GerenciadorPonto) marcarBotaoModal(rotulo string) (string, error) {
+	const atributo = "data-batedor-resposta"
+
+	var encontrado bool
+	err := common.Executar(g.ctx, chromedp.Evaluate(fmt.Sprintf(`
+		(function() {
+			const rotulo = %s;
+			for (const dialog of document.querySelectorAll(%s)) {
+				const title = dialog.querySelector(%s);
+				if (!title || !title.textContent.includes(%s)) continue;
+
+				for (const btn of dialog.querySelectorAll('button')) {
+					if (btn.textContent.trim() === rotulo) {
+						btn.setAttribute(%s, rotulo);
+						return true;
+					}
+				}
+			}
+			return false;
+		})()
+	`, selectors.JS(rotulo), selectors.JS(g.seletores.Dialogo), selectors.JS(g.seletores.DialogoTitulo),
+		selectors.JS(g.seletores.Rotulos.ModalIntervalo), selectors.JS(atributo)), &encontrado))
+	if err != nil {
+		return "", err
+	}
+	if !encontrado {
+		return "", fmt.Errorf("botão %q não encontrado no modal", rotulo)
+	}
+
+	return fmt.Sprintf(`button[%s=%s]`, atributo, selectors.JS(rotulo)), nil
+}
+
+func (g *GerenciadorPonto) tratarModalIntervalo(operacao TipoOperacao) (*ModalIntervalo, error) {
 	var modalInfo struct {
 		Visivel  bool
+		Titulo   string
 		Conteudo string
 	}
 
-	err := chromedp.Run(g.ctx,
-		chromedp.Evaluate(`
+	err := common.Executar(g.ctx,
+		chromedp.Evaluate(fmt.Sprintf(`
 			(function() {
-				const dialog = document.querySelector('.ui-dialog');
-				if (!dialog) return {visivel: false};
-				
-				const title = dialog.querySelector('.ui-dialog-title');
-				if (!title || !title.textContent.includes('Intervalo Opcional')) {
-					return {visivel: false};
+				const dialogs = document.querySelectorAll(%s);
+				for (const dialog of dialogs) {
+					const title = dialog.querySelector(%s);
+					if (!title || !title.textContent.includes(%s)) continue;
+					if (dialog.offsetParent === null && window.getComputedStyle(dialog).display === 'none') continue;
+
+					const content = dialog.querySelector(%s);
+					return {
+						visivel: true,
+						titulo: title.textContent.trim(),
+						conteudo: content ? content.textContent.replace(/\s+/g, ' ').trim() : ''
+					};
 				}
-				
-				const content = dialog.querySelector('.ui-dialog-content');
-				return {
-					visivel: true,
-					conteudo: content ? content.textContent.trim() : ''
-				};
+				return {visivel: false};
 			})()
-		`, &modalInfo),
+		`, selectors.JS(g.seletores.Dialogo), selectors.JS(g.seletores.DialogoTitulo),
+			selectors.JS(g.seletores.Rotulos.ModalIntervalo), selectors.JS(g.seletores.DialogoConteudo)), &modalInfo),
 	)
 
 	if err != nil || !modalInfo.Visivel {
-		return nil
+		return nil, nil
 	}
 
-	fmt.Printf("\n⏰ Intervalo Opcional Detectado\n%s\n", modalInfo.Conteudo)
-
-	prompt := promptui.Prompt{
-		Label:     "Deseja considerar este período como intervalo opcional",
-		IsConfirm: true,
+	modal := &ModalIntervalo{
+		Titulo:   modalInfo.Titulo,
+		Conteudo: modalInfo.Conteudo,
+		Duracao:  extrairDuracaoIntervalo(modalInfo.Conteudo),
+		Politica: g.intervalo.Politica,
 	}
 
-	resultado, err := prompt.Run()
+	resposta, err := g.intervalo.decidir(*modal)
 	if err != nil {
-		if err == promptui.ErrAbort {
-			fmt.Println("\n✖ Operação cancelada")
-			return nil
+		var erroPonto *ErroPonto
+		if errors.As(err, &erroPonto) {
+			erroPonto.Operacao = operacao
+			return nil, erroPonto
 		}
-		return &ErroPonto{
+		return nil, &ErroPonto{
+			Operacao: operacao,
 			Tipo:     "modal",
 			Mensagem: "erro na confirmação do intervalo",
 			Causa:    err,
 		}
 	}
+	modal.Resposta = resposta
 
-	seletor := `button.ui-button span`
-	if resultado == "y" || resultado == "Y" {
-		seletor += `:contains("Sim")`
-	} else {
-		seletor += `:contains("Não")`
+	rotulo := g.seletores.Rotulos.Nao
+	if resposta {
+		rotulo = g.seletores.Rotulos.Sim
 	}
 
-	err = chromedp.Run(g.ctx,
-		chromedp.Click(seletor, chromedp.ByQuery),
-	)
+	seletor, err := g.marcarBotaoModal(rotulo)
+	if err == nil {
+		err = common.Executar(g.ctx,
+			common.Clicar(seletor, chromedp.ByQuery),
+		)
+	}
 
 	if err != nil {
-		return &ErroPonto{
+		return nil, &ErroPonto{
+			Operacao: operacao,
 			Tipo:     "modal",
 			Mensagem: "falha ao responder modal",
 			Causa:    err,
 		}
 	}
 
-	return chromedp.Run(g.ctx, g.aguardarAjax())
+	if err := common.Executar(g.ctx, g.aguardarAjax()); err != nil {
+		return nil, &ErroPonto{
+			Operacao: operacao,
+			Tipo:     "modal",
+			Mensagem: "falha ao aguardar resposta do modal",
+			Causa:    err,
+		}
+	}
+
+	return modal, nil
 }
 
 func (g *GerenciadorPonto) ObterLocalizacaoAtual() (string, error) {
+	defer g.limitar()()
 	return g.obterLocalizacaoAtual()
 }
 
 func (g *GerenciadorPonto) ObterLocalizacoesDisponiveis() ([]Localizacao, error) {
+	defer g.limitar()()
 	return g.obterLocalizacoesDisponiveis()
 }
 
 func (g *GerenciadorPonto) SelecionarLocalizacao(localizacao Localizacao) error {
+	defer g.limitar()()
 	return g.selecionarLocalizacao(localizacao)
 }
 
 func (g *GerenciadorPonto) ObterOperacoesDisponiveis() ([]TipoOperacao, error) {
+	defer g.limitar()()
 	return g.obterOperacoesDisponiveis()
 }
 
-func (g *GerenciadorPonto) ExecutarOperacao(operacao TipoOperacao) error {
+func (g *GerenciadorPonto) ExecutarOperacao(operacao TipoOperacao) (*ResultadoOperacao, error) {
+	defer g.limitar()()
 	return g.executarOperacao(operacao)
 }
 
+// ObterMarcacoes retorna as marcações do dia exibidas na tabela da página de marcação
+func (g *GerenciadorPonto) ObterMarcacoes() ([]Marcacao, error) {
+	defer g.limitar()()
+	return g.obterMarcacoes()
+}
+
 // Close releases resources used by the module
 func (g *GerenciadorPonto) Close() {
 	if g.ctx != nil {
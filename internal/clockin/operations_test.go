@@ -0,0 +1,158 @@
+package clockin
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/auth"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/softtradefake"
+)
+
+// exigirChromium pula o teste quando não há Chromium instalado ou com -short
+func exigirChromium(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("teste com navegador ignorado em -short")
+	}
+	for _, nome := range []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless-shell", "headless_shell"} {
+		if _, err := exec.LookPath(nome); err == nil {
+			return
+		}
+	}
+	t.Skip("Chromium não instalado")
+}
+
+// novoGerenciadorFake inicia o Softtrade falso, faz o login em uma sessão headless e
+// retorna o módulo de ponto sobre a aba autenticada
+func novoGerenciadorFake(t *testing.T, cenario softtradefake.Cenario) (*softtradefake.Servidor, *GerenciadorPonto) {
+	t.Helper()
+	exigirChromium(t)
+
+	servidor := softtradefake.Iniciar(cenario)
+	t.Cleanup(servidor.Close)
+
+	navegador := common.NovoGerenciadorNavegador(true)
+	t.Cleanup(navegador.Close)
+
+	sessao := auth.NewAuthSession(auth.Config{Navegador: navegador, URLBase: servidor.URL()})
+	t.Cleanup(sessao.Close)
+	if err := sessao.Login(auth.Credentials{Username: cenario.Usuario, Password: cenario.Senha}); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	return servidor, NewGerenciadorPonto(sessao.GetContext(), Config{
+		Intervalo: ConfigIntervalo{Politica: IntervaloSempreSim},
+	})
+}
+
+// cenarioComLocalizacao é o cenário padrão com a localização já selecionada
+func cenarioComLocalizacao() softtradefake.Cenario {
+	cenario := softtradefake.CenarioPadrao()
+	cenario.LocalizacaoInicial = "Home Office"
+	return cenario
+}
+
+// executar marca a operação e falha o teste em caso de erro
+func executar(t *testing.T, g *GerenciadorPonto, operacao TipoOperacao) *ResultadoOperacao {
+	t.Helper()
+	resultado, err := g.ExecutarOperacao(operacao)
+	if err != nil {
+		t.Fatalf("ExecutarOperacao(%s): %v", operacao, err)
+	}
+	return resultado
+}
+
+func TestSelecionarLocalizacaoEMarcar(t *testing.T) {
+	servidor, g := novoGerenciadorFake(t, softtradefake.CenarioPadrao())
+
+	localizacoes, err := g.ObterLocalizacoesDisponiveis()
+	if err != nil {
+		t.Fatalf("ObterLocalizacoesDisponiveis: %v", err)
+	}
+	if len(localizacoes) != 3 {
+		t.Fatalf("localizações = %v, esperadas 3", localizacoes)
+	}
+
+	if err := g.SelecionarLocalizacao(Localizacao{Nome: "Escritório SP", Valor: "3"}); err != nil {
+		t.Fatalf("SelecionarLocalizacao: %v", err)
+	}
+	if atual, err := g.ObterLocalizacaoAtual(); err != nil || atual != "Escritório SP" {
+		t.Fatalf("ObterLocalizacaoAtual = %q, %v", atual, err)
+	}
+
+	executar(t, g, Entrada)
+
+	marcacoes := servidor.Marcacoes()
+	if len(marcacoes) != 1 || marcacoes[0].Operacao != softtradefake.Entrada || marcacoes[0].Localizacao != "Escritório SP" {
+		t.Fatalf("marcações = %+v", marcacoes)
+	}
+}
+
+func TestModalIntervalo(t *testing.T) {
+	servidor, g := novoGerenciadorFake(t, cenarioComLocalizacao())
+
+	executar(t, g, Entrada)
+	executar(t, g, Almoco)
+	resultado := executar(t, g, Entrada)
+
+	if resultado.Modal == nil || !resultado.Modal.Resposta {
+		t.Fatalf("modal = %+v, esperada resposta sim", resultado.Modal)
+	}
+	marcacoes := servidor.Marcacoes()
+	if len(marcacoes) != 3 || marcacoes[2].RespostaIntervalo == nil || !*marcacoes[2].RespostaIntervalo {
+		t.Fatalf("marcações = %+v", marcacoes)
+	}
+
+	operacoes, err := g.ObterOperacoesDisponiveis()
+	if err != nil || len(operacoes) != 2 {
+		t.Fatalf("operações após o modal = %v, %v", operacoes, err)
+	}
+}
+
+func TestObterMarcacoes(t *testing.T) {
+	_, g := novoGerenciadorFake(t, cenarioComLocalizacao())
+
+	if marcacoes, err := g.ObterMarcacoes(); err != nil || len(marcacoes) != 0 {
+		t.Fatalf("marcações antes de marcar = %v, %v", marcacoes, err)
+	}
+
+	executar(t, g, Entrada)
+	executar(t, g, Saida)
+
+	marcacoes, err := g.ObterMarcacoes()
+	if err != nil || len(marcacoes) != 2 || marcacoes[0].Operacao != Entrada || marcacoes[1].Operacao != Saida {
+		t.Fatalf("ObterMarcacoes = %+v, %v", marcacoes, err)
+	}
+}
+
+func TestViewExpiradaNaoPerdeMarcacao(t *testing.T) {
+	cenario := cenarioComLocalizacao()
+	cenario.ExpirarView = true
+	servidor, g := novoGerenciadorFake(t, cenario)
+
+	resultado := executar(t, g, Entrada)
+
+	if resultado.Tentativas != 2 {
+		t.Errorf("tentativas = %d, esperadas 2", resultado.Tentativas)
+	}
+	if marcacoes := servidor.Marcacoes(); len(marcacoes) != 1 {
+		t.Fatalf("marcações = %+v, esperada 1", marcacoes)
+	}
+}
+
+func TestAjaxLentoNaoDuplicaMarcacao(t *testing.T) {
+	cenario := cenarioComLocalizacao()
+	cenario.AtrasoAjax = 2 * time.Second
+	servidor, g := novoGerenciadorFake(t, cenario)
+
+	resultado := executar(t, g, Entrada)
+
+	if resultado.Tentativas != 1 {
+		t.Errorf("tentativas = %d, esperada 1", resultado.Tentativas)
+	}
+	if marcacoes := servidor.Marcacoes(); len(marcacoes) != 1 {
+		t.Fatalf("marcações = %+v, esperada 1", marcacoes)
+	}
+}
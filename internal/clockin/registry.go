@@ -0,0 +1,113 @@
+package clockin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Nomes dos provedores registrados por este pacote
+const (
+	// ProvedorSofttrade automatiza o Softtrade pelo Chromium, sobre a sessão do módulo auth
+	ProvedorSofttrade = "softtrade"
+	// ProvedorSofttradeHTTP conversa diretamente com o servidor JSF do Softtrade, sem navegador
+	ProvedorSofttradeHTTP = "softtrade-http"
+	// ProvedorMock simula um sistema de ponto para desenvolvimento
+	ProvedorMock = "mock"
+)
+
+// Capacidades descreve os recursos oferecidos por um provedor de ponto
+type Capacidades struct {
+	// Localizacoes indica que o sistema exige escolher uma localização antes de marcar
+	Localizacoes bool
+	// Historico indica que o módulo implementa Historico
+	Historico bool
+	// Modal indica que o sistema pode exibir o modal "Intervalo Opcional"; sem ele, a
+	// política de intervalo não é aplicada nem a resposta ao modal é exibida
+	Modal bool
+}
+
+// Provedor descreve uma implementação de Module que pode ser escolhida pela configuração
+type Provedor struct {
+	// Nome identifica o provedor em Config.Provedor e no config.json
+	Nome string
+	// Descricao é exibida na listagem de provedores
+	Descricao string
+	// Capacidades informa quais recursos do fluxo se aplicam ao provedor
+	Capacidades Capacidades
+	// UsaNavegador indica que Criar recebe o contexto de uma sessão do Chromium já autenticada
+	// pelo módulo auth. Os demais provedores recebem um contexto comum e, se precisarem de
+	// login, implementam Autenticavel.
+	UsaNavegador bool
+	// Criar instancia o módulo
+	Criar func(ctx context.Context, config Config) (Module, error)
+}
+
+// Autenticavel é implementado pelos módulos que fazem o próprio login
+type Autenticavel interface {
+	Login(usuario, senha string) error
+}
+
+// Marcacao é um registro de ponto já efetuado
+type Marcacao struct {
+	Operacao TipoOperacao
+	Momento  time.Time
+}
+
+// Historico é implementado pelos módulos capazes de listar as marcações do dia
+type Historico interface {
+	ObterMarcacoes() ([]Marcacao, error)
+}
+
+var (
+	muProvedores sync.RWMutex
+	provedores   = make(map[string]Provedor)
+)
+
+// Registrar disponibiliza um provedor pelo nome. Deve ser chamado em init;
+// nomes vazios ou repetidos provocam panic.
+func Registrar(provedor Provedor) {
+	muProvedores.Lock()
+	defer muProvedores.Unlock()
+
+	if provedor.Nome == "" || provedor.Criar == nil {
+		panic("clockin: provedor sem nome ou sem função Criar")
+	}
+	if _, existe := provedores[provedor.Nome]; existe {
+		panic("clockin: provedor registrado duas vezes: " + provedor.Nome)
+	}
+	provedores[provedor.Nome] = provedor
+}
+
+// ObterProvedor retorna o provedor registrado com o nome informado
+func ObterProvedor(nome string) (Provedor, error) {
+	muProvedores.RLock()
+	defer muProvedores.RUnlock()
+
+	provedor, ok := provedores[nome]
+	if !ok {
+		nomes := make([]string, 0, len(provedores))
+		for n := range provedores {
+			nomes = append(nomes, n)
+		}
+		sort.Strings(nomes)
+		return Provedor{}, fmt.Errorf("provedor de ponto desconhecido %q (disponíveis: %s)", nome, strings.Join(nomes, ", "))
+	}
+	return provedor, nil
+}
+
+// Provedores retorna os provedores registrados em ordem alfabética
+func Provedores() []Provedor {
+	muProvedores.RLock()
+	defer muProvedores.RUnlock()
+
+	lista := make([]Provedor, 0, len(provedores))
+	for _, p := range provedores {
+		lista = append(lista, p)
+	}
+	sort.Slice(lista, func(i, j int) bool { return lista[i].Nome < lista[j].Nome })
+	return lista
+}
@@ -0,0 +1,117 @@
+package cofre
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const nomeCache = "batponto-cofre.json"
+
+// ErrSemCache indica que não há desbloqueio em cache válido
+var ErrSemCache = errors.New("nenhum desbloqueio em cache")
+
+// cache guarda a chave derivada para que processos de longa duração, como o monitor de
+// lembretes, usem o cofre sem pedir a senha. Fica em XDG_RUNTIME_DIR, que é exclusivo do
+// usuário, mantido em memória e apagado ao encerrar a sessão.
+type cache struct {
+	Sal    []byte    `json:"sal"`
+	Chave  []byte    `json:"chave"`
+	Expira time.Time `json:"expira"`
+}
+
+// caminhoCache retorna o arquivo de cache, ou erro se XDG_RUNTIME_DIR não estiver definido
+func caminhoCache() (string, error) {
+	diretorio := os.Getenv("XDG_RUNTIME_DIR")
+	if diretorio == "" {
+		return "", errors.New("XDG_RUNTIME_DIR não definido; o desbloqueio em cache não está disponível")
+	}
+	return filepath.Join(diretorio, nomeCache), nil
+}
+
+// GuardarDesbloqueio mantém o cofre desbloqueado para outros processos até expira
+func (c *Cofre) GuardarDesbloqueio(expira time.Time) error {
+	caminho, err := caminhoCache()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.chave == nil {
+		c.mu.Unlock()
+		return ErrBloqueado
+	}
+	dados, err := json.Marshal(cache{Sal: c.kdf.Sal, Chave: c.chave, Expira: expira})
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("erro ao serializar desbloqueio: %w", err)
+	}
+
+	return gravarAtomico(caminho, dados)
+}
+
+// DesbloquearPorCache usa a chave guardada por GuardarDesbloqueio, se ainda válida e do
+// mesmo cofre; caches expirados ou de outra senha são apagados
+func (c *Cofre) DesbloquearPorCache(agora time.Time) error {
+	caminho, err := caminhoCache()
+	if err != nil {
+		return ErrSemCache
+	}
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return ErrSemCache
+	}
+
+	var guardado cache
+	if err := json.Unmarshal(dados, &guardado); err != nil || !agora.Before(guardado.Expira) {
+		os.Remove(caminho)
+		return ErrSemCache
+	}
+
+	conteudo, err := c.lerArquivo()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(guardado.Sal, conteudo.KDF.Sal) {
+		os.Remove(caminho)
+		return ErrSemCache
+	}
+	if err := c.decifrar(conteudo, guardado.Chave); err != nil {
+		os.Remove(caminho)
+		return ErrSemCache
+	}
+	return nil
+}
+
+// EsquecerDesbloqueio apaga a chave em cache, bloqueando o cofre para outros processos
+func EsquecerDesbloqueio() error {
+	caminho, err := caminhoCache()
+	if err != nil {
+		return nil
+	}
+	if err := os.Remove(caminho); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("erro ao apagar desbloqueio em cache: %w", err)
+	}
+	return nil
+}
+
+// ExpiracaoDesbloqueio retorna até quando o desbloqueio em cache vale, ou zero se não houver
+func ExpiracaoDesbloqueio(agora time.Time) time.Time {
+	caminho, err := caminhoCache()
+	if err != nil {
+		return time.Time{}
+	}
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return time.Time{}
+	}
+	var guardado cache
+	if err := json.Unmarshal(dados, &guardado); err != nil || !agora.Before(guardado.Expira) {
+		return time.Time{}
+	}
+	return guardado.Expira
+}
@@ -0,0 +1,397 @@
+// Package cofre guarda segredos (credenciais e cookies de sessão) cifrados em disco. A
+// chave é derivada de uma senha mestra com Argon2id e os itens são cifrados com AES-GCM.
+package cofre
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	nomeArquivo   = "cofre.json"
+	versaoArquivo = 1
+	tamanhoChave  = 32
+	tamanhoSal    = 16
+)
+
+// Nomes dos itens guardados pelos módulos
+const (
+	ItemUsuarioPonto = "usuario_ponto"
+	ItemSenhaPonto   = "senha_ponto"
+	ItemCookiesSlack = "slack_cookies"
+)
+
+var (
+	// ErrInexistente indica que o cofre ainda não foi criado
+	ErrInexistente = errors.New("cofre não encontrado; crie-o com \"batponto cofre criar\"")
+	// ErrBloqueado indica que a operação exige o cofre desbloqueado
+	ErrBloqueado = errors.New("cofre bloqueado")
+	// ErrSenhaIncorreta indica que a senha mestra não decifra o cofre
+	ErrSenhaIncorreta = errors.New("senha do cofre incorreta")
+	// ErrItemNaoEncontrado indica que o item não está no cofre
+	ErrItemNaoEncontrado = errors.New("item não encontrado no cofre")
+)
+
+// ParametrosKDF são os parâmetros do Argon2id gravados junto com o cofre
+type ParametrosKDF struct {
+	Sal       []byte `json:"sal"`
+	Tempo     uint32 `json:"tempo"`
+	Memoria   uint32 `json:"memoria_kib"`
+	Threads   uint8  `json:"threads"`
+	Tamanho   uint32 `json:"tamanho"`
+	Algoritmo string `json:"algoritmo"`
+}
+
+// ParametrosPadrao seguem a recomendação do RFC 9106 para ambientes com memória limitada
+var ParametrosPadrao = ParametrosKDF{
+	Tempo:     3,
+	Memoria:   64 * 1024,
+	Threads:   4,
+	Tamanho:   tamanhoChave,
+	Algoritmo: "argon2id",
+}
+
+func (p ParametrosKDF) derivar(senha string) []byte {
+	return argon2.IDKey([]byte(senha), p.Sal, p.Tempo, p.Memoria, p.Threads, p.Tamanho)
+}
+
+// arquivo é o formato gravado em disco
+type arquivo struct {
+	Versao int           `json:"versao"`
+	KDF    ParametrosKDF `json:"kdf"`
+	Nonce  []byte        `json:"nonce"`
+	Dados  []byte        `json:"dados"`
+}
+
+// Cofre é o conjunto de itens cifrados em <diretorioConfig>/cofre.json. Os métodos aceitam
+// um *Cofre nil, que se comporta como um cofre inexistente.
+type Cofre struct {
+	mu      sync.Mutex
+	caminho string
+	kdf     ParametrosKDF
+	chave   []byte
+	itens   map[string][]byte
+}
+
+// Abrir retorna o cofre do diretório informado, bloqueado
+func Abrir(diretorioConfig string) *Cofre {
+	return &Cofre{caminho: filepath.Join(diretorioConfig, nomeArquivo)}
+}
+
+// Caminho retorna o arquivo do cofre
+func (c *Cofre) Caminho() string {
+	return c.caminho
+}
+
+// Existe informa se o arquivo do cofre foi criado
+func (c *Cofre) Existe() bool {
+	if c == nil {
+		return false
+	}
+	_, err := os.Stat(c.caminho)
+	return err == nil
+}
+
+// Criar cria um cofre vazio protegido pela senha e o deixa desbloqueado
+func (c *Cofre) Criar(senha string) error {
+	if c.Existe() {
+		return fmt.Errorf("o cofre já existe em %s", c.caminho)
+	}
+	if senha == "" {
+		return errors.New("a senha do cofre não pode ser vazia")
+	}
+
+	kdf, err := novosParametros()
+	if err != nil {
+		return err
+	}
+	chave := kdf.derivar(senha)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	liberar, err := travarArquivo(c.caminho)
+	if err != nil {
+		return err
+	}
+	defer liberar()
+	if c.Existe() {
+		return fmt.Errorf("o cofre já existe em %s", c.caminho)
+	}
+	c.kdf = kdf
+	c.chave = chave
+	c.itens = make(map[string][]byte)
+	return c.gravar()
+}
+
+// Desbloquear deriva a chave a partir da senha e decifra os itens
+func (c *Cofre) Desbloquear(senha string) error {
+	conteudo, err := c.lerArquivo()
+	if err != nil {
+		return err
+	}
+	return c.decifrar(conteudo, conteudo.KDF.derivar(senha))
+}
+
+// Bloquear descarta a chave e os itens da memória
+func (c *Cofre) Bloquear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.chave = nil
+	c.itens = nil
+}
+
+// AlterarSenha recifra o cofre desbloqueado com uma nova senha e um novo sal
+func (c *Cofre) AlterarSenha(nova string) error {
+	if nova == "" {
+		return errors.New("a senha do cofre não pode ser vazia")
+	}
+	kdf, err := novosParametros()
+	if err != nil {
+		return err
+	}
+	chave := kdf.derivar(nova)
+
+	return c.atualizar(func() {
+		c.kdf = kdf
+		c.chave = chave
+	})
+}
+
+// Obter retorna o valor de um item
+func (c *Cofre) Obter(nome string) ([]byte, error) {
+	if !c.Existe() {
+		return nil, ErrInexistente
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.chave == nil {
+		return nil, ErrBloqueado
+	}
+	valor, ok := c.itens[nome]
+	if !ok {
+		return nil, ErrItemNaoEncontrado
+	}
+	return append([]byte(nil), valor...), nil
+}
+
+// Definir grava ou substitui um item
+func (c *Cofre) Definir(nome string, valor []byte) error {
+	return c.alterar(func(itens map[string][]byte) {
+		itens[nome] = append([]byte(nil), valor...)
+	})
+}
+
+// Remover apaga um item; remover um item ausente não é um erro
+func (c *Cofre) Remover(nome string) error {
+	return c.alterar(func(itens map[string][]byte) {
+		delete(itens, nome)
+	})
+}
+
+func (c *Cofre) alterar(mudanca func(map[string][]byte)) error {
+	return c.atualizar(func() {
+		mudanca(c.itens)
+	})
+}
+
+// atualizar relê e decifra o arquivo sob a trava, para não descartar itens gravados por
+// outro processo desde o desbloqueio, aplica a mudança e grava o resultado
+func (c *Cofre) atualizar(mudanca func()) error {
+	if !c.Existe() {
+		return ErrInexistente
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.chave == nil {
+		return ErrBloqueado
+	}
+
+	liberar, err := travarArquivo(c.caminho)
+	if err != nil {
+		return err
+	}
+	defer liberar()
+
+	conteudo, err := c.lerArquivo()
+	if err != nil {
+		return err
+	}
+	itens, err := abrirItens(conteudo, c.chave)
+	if errors.Is(err, ErrSenhaIncorreta) {
+		return fmt.Errorf("a senha do cofre foi alterada por outro processo; desbloqueie-o novamente: %w", ErrBloqueado)
+	}
+	if err != nil {
+		return err
+	}
+	c.kdf = conteudo.KDF
+	c.itens = itens
+
+	mudanca()
+	return c.gravar()
+}
+
+func novosParametros() (ParametrosKDF, error) {
+	kdf := ParametrosPadrao
+	kdf.Sal = make([]byte, tamanhoSal)
+	if _, err := rand.Read(kdf.Sal); err != nil {
+		return ParametrosKDF{}, fmt.Errorf("erro ao gerar sal do cofre: %w", err)
+	}
+	return kdf, nil
+}
+
+func (c *Cofre) lerArquivo() (arquivo, error) {
+	if c == nil {
+		return arquivo{}, ErrInexistente
+	}
+	dados, err := os.ReadFile(c.caminho)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return arquivo{}, ErrInexistente
+		}
+		return arquivo{}, fmt.Errorf("erro ao ler cofre: %w", err)
+	}
+
+	var conteudo arquivo
+	if err := json.Unmarshal(dados, &conteudo); err != nil {
+		return arquivo{}, fmt.Errorf("erro ao interpretar %s: %w", nomeArquivo, err)
+	}
+	if conteudo.Versao != versaoArquivo || conteudo.KDF.Algoritmo != "argon2id" {
+		return arquivo{}, fmt.Errorf("formato de cofre não suportado (versão %d, %s)", conteudo.Versao, conteudo.KDF.Algoritmo)
+	}
+	return conteudo, nil
+}
+
+// decifrar abre o conteúdo com a chave; a autenticação do GCM falha com a senha errada
+func (c *Cofre) decifrar(conteudo arquivo, chave []byte) error {
+	itens, err := abrirItens(conteudo, chave)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.kdf = conteudo.KDF
+	c.chave = chave
+	c.itens = itens
+	return nil
+}
+
+// abrirItens decifra os itens do conteúdo com a chave
+func abrirItens(conteudo arquivo, chave []byte) (map[string][]byte, error) {
+	aead, err := novoAEAD(chave)
+	if err != nil {
+		return nil, err
+	}
+	texto, err := aead.Open(nil, conteudo.Nonce, conteudo.Dados, []byte(nomeArquivo))
+	if err != nil {
+		return nil, ErrSenhaIncorreta
+	}
+
+	itens := make(map[string][]byte)
+	if err := json.Unmarshal(texto, &itens); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar itens do cofre: %w", err)
+	}
+	return itens, nil
+}
+
+// gravar cifra os itens com um nonce novo e substitui o arquivo atomicamente; exige c.mu
+// e a trava do arquivo
+func (c *Cofre) gravar() error {
+	texto, err := json.Marshal(c.itens)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar itens do cofre: %w", err)
+	}
+
+	aead, err := novoAEAD(c.chave)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("erro ao gerar nonce do cofre: %w", err)
+	}
+
+	dados, err := json.MarshalIndent(arquivo{
+		Versao: versaoArquivo,
+		KDF:    c.kdf,
+		Nonce:  nonce,
+		Dados:  aead.Seal(nil, nonce, texto, []byte(nomeArquivo)),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar cofre: %w", err)
+	}
+
+	return gravarAtomico(c.caminho, dados)
+}
+
+func novoAEAD(chave []byte) (cipher.AEAD, error) {
+	bloco, err := aes.NewCipher(chave)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao preparar cifra do cofre: %w", err)
+	}
+	aead, err := cipher.NewGCM(bloco)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao preparar cifra do cofre: %w", err)
+	}
+	return aead, nil
+}
+
+// travarArquivo obtém a trava exclusiva de <caminho>.lock, que serializa as gravações de
+// processos diferentes; o próprio cofre é substituído a cada gravação e não serve de trava
+func travarArquivo(caminho string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(caminho), 0700); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório do cofre: %w", err)
+	}
+	trava, err := os.OpenFile(caminho+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao travar cofre: %w", err)
+	}
+	if err := syscall.Flock(int(trava.Fd()), syscall.LOCK_EX); err != nil {
+		trava.Close()
+		return nil, fmt.Errorf("erro ao travar cofre: %w", err)
+	}
+	return func() {
+		syscall.Flock(int(trava.Fd()), syscall.LOCK_UN)
+		trava.Close()
+	}, nil
+}
+
+// gravarAtomico escreve em um arquivo temporário e o renomeia, para não corromper o destino
+func gravarAtomico(caminho string, dados []byte) error {
+	if err := os.MkdirAll(filepath.Dir(caminho), 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório do cofre: %w", err)
+	}
+	temporario, err := os.CreateTemp(filepath.Dir(caminho), "."+filepath.Base(caminho)+"-*")
+	if err != nil {
+		return fmt.Errorf("erro ao gravar cofre: %w", err)
+	}
+	defer os.Remove(temporario.Name())
+
+	if _, err := temporario.Write(dados); err != nil {
+		temporario.Close()
+		return fmt.Errorf("erro ao gravar cofre: %w", err)
+	}
+	if err := temporario.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar cofre: %w", err)
+	}
+	if err := os.Chmod(temporario.Name(), 0600); err != nil {
+		return fmt.Errorf("erro ao gravar cofre: %w", err)
+	}
+	if err := os.Rename(temporario.Name(), caminho); err != nil {
+		return fmt.Errorf("erro ao gravar cofre: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package cofre
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// abrirDesbloqueado abre outra instância do cofre do diretório, como faria outro processo
+func abrirDesbloqueado(t *testing.T, diretorio, senha string) *Cofre {
+	t.Helper()
+	c := Abrir(diretorio)
+	if err := c.Desbloquear(senha); err != nil {
+		t.Fatalf("Desbloquear: %v", err)
+	}
+	return c
+}
+
+func TestCofreGravacoesConcorrentes(t *testing.T) {
+	diretorio := t.TempDir()
+	if err := Abrir(diretorio).Criar("mestra"); err != nil {
+		t.Fatalf("Criar: %v", err)
+	}
+
+	// Duas instâncias desbloqueadas antes de qualquer gravação
+	primeiro := abrirDesbloqueado(t, diretorio, "mestra")
+	segundo := abrirDesbloqueado(t, diretorio, "mestra")
+	if err := primeiro.Definir(ItemUsuarioPonto, []byte("usuario")); err != nil {
+		t.Fatalf("Definir no primeiro: %v", err)
+	}
+	if err := segundo.Definir(ItemSenhaPonto, []byte("senha")); err != nil {
+		t.Fatalf("Definir no segundo: %v", err)
+	}
+
+	final := abrirDesbloqueado(t, diretorio, "mestra")
+	for item, esperado := range map[string]string{ItemUsuarioPonto: "usuario", ItemSenhaPonto: "senha"} {
+		valor, err := final.Obter(item)
+		if err != nil || !bytes.Equal(valor, []byte(esperado)) {
+			t.Fatalf("Obter(%s) = %q, %v; esperado %q", item, valor, err, esperado)
+		}
+	}
+}
+
+func TestCofreSenhaAlteradaPorOutroProcesso(t *testing.T) {
+	diretorio := t.TempDir()
+	if err := Abrir(diretorio).Criar("mestra"); err != nil {
+		t.Fatalf("Criar: %v", err)
+	}
+
+	primeiro := abrirDesbloqueado(t, diretorio, "mestra")
+	segundo := abrirDesbloqueado(t, diretorio, "mestra")
+	if err := primeiro.AlterarSenha("nova"); err != nil {
+		t.Fatalf("AlterarSenha: %v", err)
+	}
+
+	// Gravar com a chave antiga recifraria o cofre com a senha anterior
+	if err := segundo.Definir(ItemUsuarioPonto, []byte("usuario")); !errors.Is(err, ErrBloqueado) {
+		t.Fatalf("Definir com a senha antiga = %v, esperado %v", err, ErrBloqueado)
+	}
+	abrirDesbloqueado(t, diretorio, "nova")
+}
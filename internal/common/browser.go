@@ -0,0 +1,152 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	browserWindowWidth  = 1280
+	browserWindowHeight = 720
+	browserUserAgent    = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+)
+
+// GerenciadorNavegador mantém uma única instância do Chromium compartilhada entre os módulos.
+// Cada aba é criada em um BrowserContext próprio, de modo que os módulos não compartilham
+// cookies nem armazenamento local.
+type GerenciadorNavegador struct {
+	mu        sync.Mutex
+	headless  bool
+	depuracao Depuracao
+
+	allocCtx        context.Context
+	cancelarAlloc   context.CancelFunc
+	browserCtx      context.Context
+	cancelarBrowser context.CancelFunc
+}
+
+// NovoGerenciadorNavegador cria um gerenciador; o Chromium só é iniciado na primeira aba solicitada
+func NovoGerenciadorNavegador(headless bool) *GerenciadorNavegador {
+	return &GerenciadorNavegador{headless: headless}
+}
+
+// NovoGerenciadorDepuracao cria um gerenciador com o Chromium visível e o modo de
+// depuração aplicado a todas as abas
+func NovoGerenciadorDepuracao(d Depuracao) *GerenciadorNavegador {
+	d.Ativa = true
+	return &GerenciadorNavegador{headless: false, depuracao: d}
+}
+
+// OpcoesNavegador retorna as opções padrão de inicialização do Chromium
+func OpcoesNavegador(headless bool) []chromedp.ExecAllocatorOption {
+	return append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", headless),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-setuid-sandbox", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("disable-gpu", headless),
+		chromedp.Flag("no-first-run", true),
+		chromedp.Flag("no-default-browser-check", true),
+		chromedp.Flag("ignore-certificate-errors", true),
+		chromedp.Flag("disable-extensions", true),
+		chromedp.WindowSize(browserWindowWidth, browserWindowHeight),
+		chromedp.UserAgent(browserUserAgent),
+	)
+}
+
+// Headless indica se a instância compartilhada roda sem janela
+func (g *GerenciadorNavegador) Headless() bool {
+	return g.headless
+}
+
+func (g *GerenciadorNavegador) iniciar() error {
+	if g.browserCtx != nil {
+		return nil
+	}
+
+	base, opcoes := context.Background(), OpcoesNavegador(g.headless)
+	if g.depuracao.Ativa {
+		base, opcoes = ComDepuracao(base, g.depuracao), OpcoesDepuracao(opcoes)
+	}
+
+	allocCtx, cancelarAlloc := chromedp.NewExecAllocator(base, opcoes...)
+	browserCtx, cancelarBrowser := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+
+	// Inicia o processo do navegador
+	if err := chromedp.Run(browserCtx); err != nil {
+		cancelarBrowser()
+		cancelarAlloc()
+		return fmt.Errorf("erro ao iniciar navegador: %w", err)
+	}
+
+	g.allocCtx, g.cancelarAlloc = allocCtx, cancelarAlloc
+	g.browserCtx, g.cancelarBrowser = browserCtx, cancelarBrowser
+	return nil
+}
+
+// NovaAba cria uma aba isolada na instância compartilhada.
+// O cancelamento retornado fecha apenas a aba e descarta seus cookies.
+func (g *GerenciadorNavegador) NovaAba() (context.Context, context.CancelFunc, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.iniciar(); err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancelar := chromedp.NewContext(g.browserCtx, chromedp.WithNewBrowserContext())
+	if err := chromedp.Run(ctx); err != nil {
+		cancelar()
+		return nil, nil, fmt.Errorf("erro ao abrir aba: %w", err)
+	}
+	if err := prepararAbaDepuracao(ctx); err != nil {
+		cancelar()
+		return nil, nil, fmt.Errorf("erro ao preparar aba para depuração: %w", err)
+	}
+
+	return ctx, cancelar, nil
+}
+
+// NovaJanelaInterativa abre uma aba visível para interação do usuário.
+// Se a instância compartilhada for headless, um Chromium visível temporário é iniciado
+// e encerrado junto com o cancelamento retornado.
+func (g *GerenciadorNavegador) NovaJanelaInterativa() (context.Context, context.CancelFunc, error) {
+	if !g.headless {
+		return g.NovaAba()
+	}
+
+	allocCtx, cancelarAlloc := chromedp.NewExecAllocator(context.Background(), OpcoesNavegador(false)...)
+	ctx, cancelarBrowser := chromedp.NewContext(allocCtx, chromedp.WithLogf(log.Printf))
+	cancelar := func() {
+		cancelarBrowser()
+		cancelarAlloc()
+	}
+
+	if err := chromedp.Run(ctx); err != nil {
+		cancelar()
+		return nil, nil, fmt.Errorf("erro ao iniciar navegador interativo: %w", err)
+	}
+
+	return ctx, cancelar, nil
+}
+
+// Close encerra o Chromium compartilhado e todas as abas abertas
+func (g *GerenciadorNavegador) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.cancelarBrowser != nil {
+		g.cancelarBrowser()
+		g.cancelarBrowser = nil
+	}
+	if g.cancelarAlloc != nil {
+		g.cancelarAlloc()
+		g.cancelarAlloc = nil
+	}
+	g.browserCtx = nil
+	g.allocCtx = nil
+}
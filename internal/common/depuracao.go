@@ -0,0 +1,159 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// Depuracao configura o modo --debug-browser: Chromium visível com o DevTools aberto, uma
+// pausa entre as ações, destaque dos elementos clicados e parada nos erros até o Enter
+type Depuracao struct {
+	// Ativa liga o modo de depuração
+	Ativa bool
+	// Atraso é a pausa após cada ação do chromedp
+	Atraso time.Duration
+}
+
+type chaveDepuracao struct{}
+
+// pausaErro serializa as paradas em erro, que leem a entrada padrão
+var pausaErro sync.Mutex
+
+// estiloDestaque é aplicado aos elementos antes do clique
+const estiloDestaque = "3px solid #e11d48"
+
+// scriptDestaque faz os cliques disparados por JavaScript (element.click()) também
+// destacarem o elemento
+const scriptDestaque = `(() => {
+	const clicar = HTMLElement.prototype.click;
+	HTMLElement.prototype.click = function() {
+		this.style.outline = '` + estiloDestaque + `';
+		this.style.outlineOffset = '2px';
+		return clicar.apply(this, arguments);
+	};
+})()`
+
+// ComDepuracao retorna um contexto que propaga a configuração de depuração às abas
+// criadas a partir dele
+func ComDepuracao(ctx context.Context, d Depuracao) context.Context {
+	return context.WithValue(ctx, chaveDepuracao{}, d)
+}
+
+// DepuracaoDe retorna a configuração de depuração do contexto; o valor zero indica inativa
+func DepuracaoDe(ctx context.Context) Depuracao {
+	d, _ := ctx.Value(chaveDepuracao{}).(Depuracao)
+	return d
+}
+
+// Executar roda as ações como chromedp.Run. Em depuração, roda uma a uma com o atraso
+// configurado entre elas e, se uma falhar, exibe o erro e aguarda o Enter antes de
+// retorná-lo, para que a página possa ser inspecionada no DevTools.
+func Executar(ctx context.Context, acoes ...chromedp.Action) error {
+	d := DepuracaoDe(ctx)
+	if !d.Ativa || len(acoes) == 0 {
+		return chromedp.Run(ctx, acoes...)
+	}
+
+	for _, acao := range acoes {
+		if err := chromedp.Run(ctx, acao); err != nil {
+			// Cancelamento e tempo limite esgotado não deixam página para inspecionar
+			if ctx.Err() == nil {
+				pausarNoErro(err)
+			}
+			return err
+		}
+		if err := Dormir(ctx, RelogioSistema, d.Atraso); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clicar equivale a chromedp.Click; em depuração, rola até o elemento e o destaca antes
+// do clique
+func Clicar(seletor interface{}, opcoes ...chromedp.QueryOption) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		d := DepuracaoDe(ctx)
+		if !d.Ativa {
+			return chromedp.Click(seletor, opcoes...).Do(ctx)
+		}
+
+		var nos []*cdp.Node
+		if err := chromedp.Nodes(seletor, &nos, append(opcoes, chromedp.NodeVisible)...).Do(ctx); err != nil {
+			return err
+		}
+		objeto, err := dom.ResolveNode().WithNodeID(nos[0].NodeID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		_, excecao, err := runtime.CallFunctionOn(`function() {
+			this.scrollIntoView({block: 'center'});
+			this.style.outline = '` + estiloDestaque + `';
+			this.style.outlineOffset = '2px';
+		}`).WithObjectID(objeto.ObjectID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if excecao != nil {
+			return excecao
+		}
+		if err := Dormir(ctx, RelogioSistema, d.Atraso); err != nil {
+			return err
+		}
+		return chromedp.Click([]cdp.NodeID{nos[0].NodeID}, chromedp.ByNodeID).Do(ctx)
+	})
+}
+
+// OpcoesDepuracao acrescenta às opções do Chromium a abertura do DevTools em cada aba
+func OpcoesDepuracao(opcoes []chromedp.ExecAllocatorOption) []chromedp.ExecAllocatorOption {
+	return append(opcoes, chromedp.Flag("auto-open-devtools-for-tabs", true))
+}
+
+// prepararAbaDepuracao instala na aba o destaque dos cliques feitos por JavaScript
+func prepararAbaDepuracao(ctx context.Context) error {
+	if !DepuracaoDe(ctx).Ativa {
+		return nil
+	}
+	return chromedp.Run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, err := page.AddScriptToEvaluateOnNewDocument(scriptDestaque).Do(ctx)
+		return err
+	}))
+}
+
+// pausarNoErro exibe o erro e aguarda o Enter. O spinner ativo é parado durante a pausa,
+// para não sobrescrever a mensagem, e retomado em seguida.
+func pausarNoErro(err error) {
+	pausaErro.Lock()
+	defer pausaErro.Unlock()
+
+	if spinner := SpinnerAtivo(); spinner != nil {
+		spinner.Stop()
+		defer spinner.Start()
+	}
+
+	fmt.Printf("\n🐞 Erro no navegador: %v\n", err)
+	fmt.Print("Inspecione a página no DevTools e pressione Enter para continuar...")
+	lerLinha(os.Stdin)
+}
+
+// lerLinha consome a entrada até o fim da linha, byte a byte, sem reter em buffer o que
+// vier depois e seria lido pelos prompts seguintes
+func lerLinha(entrada io.Reader) {
+	var b [1]byte
+	for {
+		n, err := entrada.Read(b[:])
+		if err != nil || (n == 1 && b[0] == '\n') {
+			return
+		}
+	}
+}
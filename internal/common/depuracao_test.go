@@ -0,0 +1,20 @@
+package common
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLerLinhaPreservaRestoDaEntrada(t *testing.T) {
+	entrada := strings.NewReader("continuar\nsenha do próximo prompt\n")
+	lerLinha(entrada)
+
+	resto, err := io.ReadAll(entrada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resto) != "senha do próximo prompt\n" {
+		t.Fatalf("restou %q na entrada", resto)
+	}
+}
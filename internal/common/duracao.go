@@ -0,0 +1,20 @@
+package common
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatarDuracao exibe a duração arredondada ao minuto em horas e minutos ("8h",
+// "1h05min", "45min")
+func FormatarDuracao(d time.Duration) string {
+	d = d.Round(time.Minute)
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dmin", int(d.Minutes()))
+	case int(d.Minutes())%60 == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dh%02dmin", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
@@ -0,0 +1,21 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatarDuracao(t *testing.T) {
+	casos := map[time.Duration]string{
+		45 * time.Minute:          "45min",
+		8 * time.Hour:             "8h",
+		time.Hour + 5*time.Minute: "1h05min",
+		time.Hour + 29*time.Minute + 40*time.Second: "1h30min",
+		59*time.Minute + 50*time.Second:             "1h",
+	}
+	for duracao, esperado := range casos {
+		if texto := FormatarDuracao(duracao); texto != esperado {
+			t.Errorf("FormatarDuracao(%s) = %q, esperado %q", duracao, texto, esperado)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package common
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// InjetorFalhas decide, de forma reproduzível, quais chamadas de um mock devem falhar.
+// Um *InjetorFalhas nil nunca falha.
+type InjetorFalhas struct {
+	mu            sync.Mutex
+	sorteio       *rand.Rand
+	probabilidade float64
+	falhas        map[string]map[int]bool
+	chamadas      map[string]int
+}
+
+// NovoInjetorFalhas cria um injetor que falha nas chamadas listadas em falhas (por método,
+// numeradas a partir de 1) e, nas demais, com a probabilidade informada, sorteada a partir
+// da semente
+func NovoInjetorFalhas(semente int64, probabilidade float64, falhas map[string][]int) *InjetorFalhas {
+	injetor := &InjetorFalhas{
+		sorteio:       rand.New(rand.NewSource(semente)),
+		probabilidade: probabilidade,
+		falhas:        make(map[string]map[int]bool),
+		chamadas:      make(map[string]int),
+	}
+	for metodo, chamadas := range falhas {
+		injetor.falhas[metodo] = make(map[int]bool)
+		for _, chamada := range chamadas {
+			injetor.falhas[metodo][chamada] = true
+		}
+	}
+	return injetor
+}
+
+// Falhar registra uma chamada do método e informa se ela deve falhar
+func (i *InjetorFalhas) Falhar(metodo string) bool {
+	if i == nil {
+		return false
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.chamadas[metodo]++
+	// O sorteio acontece em toda chamada para que a sequência não dependa das falhas explícitas
+	sorteada := i.sorteio.Float64() < i.probabilidade
+	return i.falhas[metodo][i.chamadas[metodo]] || sorteada
+}
@@ -1,5 +1,7 @@
 package common
 
+import "sync"
+
 // LoadingSpinner defines the interface for loading spinners
 type LoadingSpinner interface {
 	// Start starts the spinner animation
@@ -17,3 +19,32 @@ type LoadingSpinner interface {
 	// Update updates the spinner message
 	Update(message string)
 }
+
+var (
+	spinnerMu    sync.Mutex
+	spinnerAtivo LoadingSpinner
+)
+
+// MarcarSpinnerAtivo registra o spinner em exibição, que as pausas de leitura do terminal
+// param antes de escrever
+func MarcarSpinnerAtivo(s LoadingSpinner) {
+	spinnerMu.Lock()
+	defer spinnerMu.Unlock()
+	spinnerAtivo = s
+}
+
+// DesmarcarSpinnerAtivo remove o registro do spinner, se ele ainda for o ativo
+func DesmarcarSpinnerAtivo(s LoadingSpinner) {
+	spinnerMu.Lock()
+	defer spinnerMu.Unlock()
+	if spinnerAtivo == s {
+		spinnerAtivo = nil
+	}
+}
+
+// SpinnerAtivo retorna o spinner em exibição ou nil
+func SpinnerAtivo() LoadingSpinner {
+	spinnerMu.Lock()
+	defer spinnerMu.Unlock()
+	return spinnerAtivo
+}
@@ -0,0 +1,163 @@
+package common
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Relogio fornece o horário atual e as esperas usadas pelos módulos, permitindo que
+// horários de expediente, retentativas e tempos limite sejam simulados em testes
+type Relogio interface {
+	// Agora retorna o horário atual
+	Agora() time.Time
+	// Apos retorna um canal que recebe o horário depois de decorrida a duração
+	Apos(d time.Duration) <-chan time.Time
+}
+
+type relogioSistema struct{}
+
+func (relogioSistema) Agora() time.Time                      { return time.Now() }
+func (relogioSistema) Apos(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RelogioSistema usa o horário e os temporizadores reais
+var RelogioSistema Relogio = relogioSistema{}
+
+// RelogioOuSistema retorna r ou, se nil, RelogioSistema
+func RelogioOuSistema(r Relogio) Relogio {
+	if r == nil {
+		return RelogioSistema
+	}
+	return r
+}
+
+// Dormir aguarda a duração no relógio informado ou até ctx ser cancelado
+func Dormir(ctx context.Context, relogio Relogio, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-RelogioOuSistema(relogio).Apos(d):
+		return nil
+	}
+}
+
+// ComTempoLimite é o equivalente de context.WithTimeout medido no relógio informado.
+// Com um relógio simulado, o contexto é cancelado com causa context.DeadlineExceeded
+// quando o relógio avança além do limite; o limite em si nunca avança o relógio, nem
+// no modo automático.
+func ComTempoLimite(ctx context.Context, relogio Relogio, d time.Duration) (context.Context, context.CancelFunc) {
+	relogio = RelogioOuSistema(relogio)
+	if _, real := relogio.(relogioSistema); real {
+		return context.WithTimeout(ctx, d)
+	}
+
+	limitado, cancelar := context.WithCancelCause(ctx)
+	var limite <-chan time.Time
+	if simulado, ok := relogio.(*RelogioSimulado); ok {
+		limite = simulado.prazo(d)
+	} else {
+		limite = relogio.Apos(d)
+	}
+	go func() {
+		select {
+		case <-limite:
+			cancelar(context.DeadlineExceeded)
+		case <-limitado.Done():
+		}
+	}()
+	return limitado, func() { cancelar(context.Canceled) }
+}
+
+type esperaSimulada struct {
+	momento time.Time
+	canal   chan time.Time
+}
+
+// RelogioSimulado é um relógio manual: o horário só muda com Avancar e as esperas são
+// liberadas quando o horário as alcança. No modo automático, cada espera avança o
+// relógio imediatamente, o que permite percorrer um dia de trabalho inteiro em um teste.
+type RelogioSimulado struct {
+	mu         sync.Mutex
+	agora      time.Time
+	automatico bool
+	esperas    []esperaSimulada
+}
+
+// NovoRelogioSimulado cria um relógio parado em inicio; automatico faz as esperas
+// avançarem o relógio em vez de bloquear
+func NovoRelogioSimulado(inicio time.Time, automatico bool) *RelogioSimulado {
+	return &RelogioSimulado{agora: inicio, automatico: automatico}
+}
+
+// Agora retorna o horário simulado
+func (r *RelogioSimulado) Agora() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.agora
+}
+
+// Apos registra uma espera que termina quando o relógio alcançar agora+d; no modo
+// automático, o relógio avança até lá imediatamente
+func (r *RelogioSimulado) Apos(d time.Duration) <-chan time.Time {
+	canal, momento := r.registrar(d)
+	if r.automatico && d > 0 {
+		r.Definir(momento)
+	}
+	return canal
+}
+
+// prazo registra uma espera como Apos, mas nunca avança o relógio: usado pelos tempos
+// limite, que só devem expirar quando as esperas ou Avancar moverem o horário
+func (r *RelogioSimulado) prazo(d time.Duration) <-chan time.Time {
+	canal, _ := r.registrar(d)
+	return canal
+}
+
+// registrar cria a espera até agora+d, liberada de imediato se d não for positivo
+func (r *RelogioSimulado) registrar(d time.Duration) (<-chan time.Time, time.Time) {
+	canal := make(chan time.Time, 1)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	momento := r.agora.Add(d)
+	if d <= 0 {
+		canal <- momento
+		return canal, momento
+	}
+	r.esperas = append(r.esperas, esperaSimulada{momento: momento, canal: canal})
+	return canal, momento
+}
+
+// Avancar adianta o relógio e libera as esperas alcançadas
+func (r *RelogioSimulado) Avancar(d time.Duration) {
+	r.Definir(r.Agora().Add(d))
+}
+
+// Definir move o relógio para momento, sem voltar no tempo, e libera as esperas alcançadas
+func (r *RelogioSimulado) Definir(momento time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if momento.After(r.agora) {
+		r.agora = momento
+	}
+
+	sort.SliceStable(r.esperas, func(i, j int) bool {
+		return r.esperas[i].momento.Before(r.esperas[j].momento)
+	})
+
+	pendentes := r.esperas[:0]
+	for _, espera := range r.esperas {
+		if espera.momento.After(r.agora) {
+			pendentes = append(pendentes, espera)
+			continue
+		}
+		espera.canal <- espera.momento
+	}
+	r.esperas = pendentes
+}
@@ -0,0 +1,66 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var inicioSimulado = time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local)
+
+// liberada informa se a espera já recebeu o horário
+func liberada(canal <-chan time.Time) bool {
+	select {
+	case <-canal:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestRelogioSimuladoManual(t *testing.T) {
+	relogio := NovoRelogioSimulado(inicioSimulado, false)
+	espera := relogio.Apos(time.Hour)
+
+	relogio.Avancar(59 * time.Minute)
+	if liberada(espera) {
+		t.Fatal("espera liberada antes do horário")
+	}
+	relogio.Avancar(time.Minute)
+	if !liberada(espera) {
+		t.Fatal("espera não liberada ao alcançar o horário")
+	}
+	if agora := relogio.Agora(); !agora.Equal(inicioSimulado.Add(time.Hour)) {
+		t.Fatalf("Agora = %s", agora)
+	}
+}
+
+func TestComTempoLimiteRelogioAutomatico(t *testing.T) {
+	relogio := NovoRelogioSimulado(inicioSimulado, true)
+
+	ctx, cancelar := ComTempoLimite(context.Background(), relogio, 30*time.Second)
+	defer cancelar()
+
+	if !relogio.Agora().Equal(inicioSimulado) {
+		t.Fatalf("o tempo limite avançou o relógio para %s", relogio.Agora())
+	}
+	if err := Dormir(context.Background(), relogio, 20*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("contexto cancelado antes do limite: %v", context.Cause(ctx))
+	}
+
+	if err := Dormir(context.Background(), relogio, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("contexto não cancelado após o limite")
+	}
+	if causa := context.Cause(ctx); !errors.Is(causa, context.DeadlineExceeded) {
+		t.Fatalf("causa = %v, esperado %v", causa, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,140 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+)
+
+// PoliticaRetentativa define quantas vezes e com que espera uma operação é repetida
+type PoliticaRetentativa struct {
+	// MaxTentativas é o número total de execuções, incluindo a primeira
+	MaxTentativas int
+	// EsperaInicial é a espera antes da segunda tentativa
+	EsperaInicial time.Duration
+	// EsperaMaxima limita o crescimento exponencial da espera
+	EsperaMaxima time.Duration
+	// Multiplicador é o fator de crescimento da espera a cada tentativa
+	Multiplicador float64
+	// Jitter é a fração (0 a 1) da espera sorteada para evitar tentativas sincronizadas;
+	// nil usa o padrão e zero desliga o sorteio
+	Jitter *float64
+}
+
+// Fracao retorna o endereço de v, para preencher PoliticaRetentativa.Jitter
+func Fracao(v float64) *float64 {
+	return &v
+}
+
+// ErroRetentavel é implementado por erros que sabem se a operação pode ser repetida
+type ErroRetentavel interface {
+	Retentavel() bool
+}
+
+// ErroTentativas envolve o último erro de uma operação que esgotou ou abortou as tentativas
+type ErroTentativas struct {
+	Operacao   string
+	Tentativas int
+	Causa      error
+}
+
+func (e *ErroTentativas) Error() string {
+	return e.Causa.Error()
+}
+
+func (e *ErroTentativas) Unwrap() error {
+	return e.Causa
+}
+
+// Mesclar retorna a política com os campos zerados (Jitter nil) preenchidos a partir de padrao
+func (p PoliticaRetentativa) Mesclar(padrao PoliticaRetentativa) PoliticaRetentativa {
+	if p.MaxTentativas <= 0 {
+		p.MaxTentativas = padrao.MaxTentativas
+	}
+	if p.EsperaInicial <= 0 {
+		p.EsperaInicial = padrao.EsperaInicial
+	}
+	if p.EsperaMaxima <= 0 {
+		p.EsperaMaxima = padrao.EsperaMaxima
+	}
+	if p.Multiplicador < 1 {
+		p.Multiplicador = padrao.Multiplicador
+	}
+	if p.Jitter == nil {
+		p.Jitter = padrao.Jitter
+	}
+	return p
+}
+
+// espera calcula o intervalo antes da tentativa seguinte à tentativa informada (1-based)
+func (p PoliticaRetentativa) espera(tentativa int) time.Duration {
+	espera := float64(p.EsperaInicial)
+	for i := 1; i < tentativa; i++ {
+		espera *= p.Multiplicador
+		if p.EsperaMaxima > 0 && espera >= float64(p.EsperaMaxima) {
+			espera = float64(p.EsperaMaxima)
+			break
+		}
+	}
+
+	if p.Jitter != nil && *p.Jitter > 0 {
+		variacao := espera * *p.Jitter
+		espera += variacao*rand.Float64()*2 - variacao
+	}
+
+	return time.Duration(espera)
+}
+
+// Retentavel indica se err permite uma nova tentativa.
+// Cancelamentos de contexto são sempre fatais; erros sem classificação são repetidos.
+func Retentavel(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var classificado ErroRetentavel
+	if errors.As(err, &classificado) {
+		return classificado.Retentavel()
+	}
+
+	return true
+}
+
+// Retentar executa fn até obter sucesso, encontrar um erro fatal, esgotar as tentativas
+// ou ctx ser cancelado. As esperas entre tentativas são medidas em relogio (nil usa o
+// relógio do sistema). Retorna o número de tentativas realizadas; em caso de falha
+// o erro é um *ErroTentativas.
+func Retentar[T any](ctx context.Context, relogio Relogio, nome string, politica PoliticaRetentativa, fn func() (T, error)) (T, int, error) {
+	var (
+		resultado T
+		err       error
+	)
+
+	maxTentativas := politica.MaxTentativas
+	if maxTentativas <= 0 {
+		maxTentativas = 1
+	}
+
+	for tentativa := 1; ; tentativa++ {
+		resultado, err = fn()
+		if err == nil {
+			if tentativa > 1 {
+				log.Printf("[%s] sucesso na tentativa %d/%d", nome, tentativa, maxTentativas)
+			}
+			return resultado, tentativa, nil
+		}
+
+		if !Retentavel(err) || tentativa >= maxTentativas {
+			return resultado, tentativa, &ErroTentativas{Operacao: nome, Tentativas: tentativa, Causa: err}
+		}
+
+		espera := politica.espera(tentativa)
+		log.Printf("[%s] tentativa %d/%d falhou: %v; nova tentativa em %s", nome, tentativa, maxTentativas, err, espera.Round(time.Millisecond))
+
+		if errCtx := Dormir(ctx, relogio, espera); errCtx != nil {
+			return resultado, tentativa, &ErroTentativas{Operacao: nome, Tentativas: tentativa, Causa: errors.Join(err, errCtx)}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// erroClassificado é um erro que informa se pode ser repetido
+type erroClassificado struct{ retentavel bool }
+
+func (e erroClassificado) Error() string    { return fmt.Sprintf("retentável: %v", e.retentavel) }
+func (e erroClassificado) Retentavel() bool { return e.retentavel }
+
+// semJitter é uma política determinística: 100ms, 200ms, 400ms, ... até 1s
+var semJitter = PoliticaRetentativa{
+	MaxTentativas: 3,
+	EsperaInicial: 100 * time.Millisecond,
+	EsperaMaxima:  time.Second,
+	Multiplicador: 2,
+	Jitter:        Fracao(0),
+}
+
+func TestEsperaExponencialLimitada(t *testing.T) {
+	esperadas := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, esperada := range esperadas {
+		if espera := semJitter.espera(i + 1); espera != esperada*time.Millisecond {
+			t.Errorf("espera(%d) = %s, esperado %s", i+1, espera, esperada*time.Millisecond)
+		}
+	}
+}
+
+func TestEsperaComJitter(t *testing.T) {
+	politica := semJitter
+	politica.Jitter = Fracao(0.5)
+	for range 200 {
+		if espera := politica.espera(1); espera < 50*time.Millisecond || espera > 150*time.Millisecond {
+			t.Fatalf("espera com jitter de 50%% = %s, fora de [50ms, 150ms]", espera)
+		}
+	}
+}
+
+func TestMesclar(t *testing.T) {
+	padrao := PoliticaRetentativa{MaxTentativas: 5, EsperaInicial: time.Second, EsperaMaxima: 4 * time.Second, Multiplicador: 2, Jitter: Fracao(0.2)}
+
+	vazia := PoliticaRetentativa{}.Mesclar(padrao)
+	if vazia.MaxTentativas != 5 || vazia.EsperaInicial != time.Second || vazia.EsperaMaxima != 4*time.Second || vazia.Multiplicador != 2 || *vazia.Jitter != 0.2 {
+		t.Fatalf("política vazia mesclada = %+v", vazia)
+	}
+
+	ajustada := PoliticaRetentativa{MaxTentativas: 2, Jitter: Fracao(0)}.Mesclar(padrao)
+	if ajustada.MaxTentativas != 2 || ajustada.EsperaInicial != time.Second || *ajustada.Jitter != 0 {
+		t.Fatalf("jitter zero não foi mantido: %+v", ajustada)
+	}
+}
+
+func TestRetentar(t *testing.T) {
+	fatal := erroClassificado{retentavel: false}
+	transitorio := erroClassificado{retentavel: true}
+
+	casos := []struct {
+		nome       string
+		falhas     []error
+		tentativas int
+		esperado   error
+		espera     time.Duration
+	}{
+		{"sucesso na primeira", nil, 1, nil, 0},
+		{"sucesso após falhas retentáveis", []error{transitorio, errors.New("sem classificação")}, 3, nil, 300 * time.Millisecond},
+		{"erro fatal não é repetido", []error{fatal}, 1, fatal, 0},
+		{"tentativas esgotadas", []error{transitorio, transitorio, transitorio, transitorio}, 3, transitorio, 300 * time.Millisecond},
+		{"contexto cancelado é fatal", []error{context.Canceled}, 1, context.Canceled, 0},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			relogio := NovoRelogioSimulado(inicioSimulado, true)
+			chamadas := 0
+			resultado, tentativas, err := Retentar(context.Background(), relogio, "teste", semJitter, func() (int, error) {
+				chamadas++
+				if chamadas <= len(c.falhas) {
+					return 0, c.falhas[chamadas-1]
+				}
+				return 42, nil
+			})
+
+			if tentativas != c.tentativas || chamadas != c.tentativas {
+				t.Fatalf("tentativas = %d (%d chamadas), esperado %d", tentativas, chamadas, c.tentativas)
+			}
+			if decorrido := relogio.Agora().Sub(inicioSimulado); decorrido != c.espera {
+				t.Fatalf("esperou %s, esperado %s", decorrido, c.espera)
+			}
+			if c.esperado == nil {
+				if err != nil || resultado != 42 {
+					t.Fatalf("Retentar = %d, %v", resultado, err)
+				}
+				return
+			}
+
+			var erroTentativas *ErroTentativas
+			if !errors.As(err, &erroTentativas) || erroTentativas.Operacao != "teste" || erroTentativas.Tentativas != c.tentativas {
+				t.Fatalf("erro = %#v, esperado *ErroTentativas com %d tentativas", err, c.tentativas)
+			}
+			if !errors.Is(err, c.esperado) {
+				t.Fatalf("erro = %v, esperado envolver %v", err, c.esperado)
+			}
+		})
+	}
+}
+
+func TestRetentarCanceladoNaEspera(t *testing.T) {
+	// O relógio manual mantém a espera pendente até o cancelamento
+	relogio := NovoRelogioSimulado(inicioSimulado, false)
+	ctx, cancelar := context.WithCancel(context.Background())
+
+	fim := make(chan error, 1)
+	chamadas := 0
+	go func() {
+		_, _, err := Retentar(ctx, relogio, "teste", semJitter, func() (int, error) {
+			chamadas++
+			return 0, errors.New("falha")
+		})
+		fim <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancelar()
+	select {
+	case err := <-fim:
+		var erroTentativas *ErroTentativas
+		if !errors.Is(err, context.Canceled) || !errors.As(err, &erroTentativas) || erroTentativas.Tentativas != 1 || chamadas != 1 {
+			t.Fatalf("Retentar cancelado = %v após %d chamadas", err, chamadas)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Retentar não parou com o contexto cancelado")
+	}
+}
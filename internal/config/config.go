@@ -0,0 +1,413 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/deteccao"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slack"
+)
+
+const (
+	nomeDiretorio = ".batedorponto"
+	nomeArquivo   = "config.json"
+)
+
+// Config contém as configurações do batedor lidas de ~/.batedorponto/config.json
+type Config struct {
+	// Ponto agrupa as configurações do módulo de ponto
+	Ponto Ponto `json:"ponto"`
+
+	// Slack agrupa as configurações do módulo do Slack
+	Slack Slack `json:"slack"`
+
+	// Snapshots controla a gravação das páginas lidas pelos extratores
+	Snapshots Snapshots `json:"snapshots"`
+
+	// Deteccao configura a escolha automática da localização pela rede
+	Deteccao Deteccao `json:"deteccao"`
+
+	// Lembretes configura os alertas de marcações atrasadas
+	Lembretes Lembretes `json:"lembretes"`
+
+	// Credenciais define onde usuário, senha e cookies do Slack são guardados
+	Credenciais Credenciais `json:"credenciais"`
+
+	// Cofre configura o armazenamento cifrado de credenciais e cookies
+	Cofre Cofre `json:"cofre"`
+
+	// Depuracao configura a opção --debug-browser
+	Depuracao Depuracao `json:"depuracao"`
+}
+
+// Depuracao contém as opções do navegador visível aberto com --debug-browser
+type Depuracao struct {
+	// Atraso é a pausa entre as ações no navegador; "--debug-browser=<duração>" o substitui
+	Atraso Duracao `json:"atraso"`
+}
+
+// Credenciais contém a escolha do armazenamento e as fontes externas de credenciais
+type Credenciais struct {
+	// Armazenamento pode ser "auto" (cofre, se criado, ou arquivos), "arquivo", "cofre" ou
+	// "keyring" (Secret Service do desktop, com os arquivos como alternativa)
+	Armazenamento string `json:"armazenamento"`
+
+	// Fontes é a ordem de busca das credenciais antes de pedi-las: "ambiente"
+	// (USERNAME_PONTO e PASSWORD_PONTO), "comando", "stdin" e "armazenamento"; vazio usa
+	// ambiente, comando (se configurado) e armazenamento
+	Fontes []string `json:"fontes"`
+
+	// Comando imprime a senha na primeira linha e, opcionalmente, o usuário em uma linha
+	// "usuario: <nome>", como "pass show softtrade"
+	Comando string `json:"comando"`
+
+	// TempoLimite limita o comando e a leitura da entrada padrão; vazio usa 10 segundos
+	TempoLimite Duracao `json:"tempo_limite"`
+
+	// Usuario completa as fontes que informam apenas a senha
+	Usuario string `json:"usuario"`
+}
+
+// Fontes de credenciais aceitas em Credenciais.Fontes
+const (
+	FonteAmbiente      = "ambiente"
+	FonteComando       = "comando"
+	FonteStdin         = "stdin"
+	FonteArmazenamento = "armazenamento"
+)
+
+// OrdemFontes retorna as fontes configuradas ou a ordem padrão
+func (c Credenciais) OrdemFontes() []string {
+	if len(c.Fontes) > 0 {
+		return c.Fontes
+	}
+	if c.Comando != "" {
+		return []string{FonteAmbiente, FonteComando, FonteArmazenamento}
+	}
+	return []string{FonteAmbiente, FonteArmazenamento}
+}
+
+// Cofre contém as opções do cofre criado com "batponto cofre criar"
+type Cofre struct {
+	// TempoDesbloqueio é por quanto tempo "cofre desbloquear" mantém a chave em cache
+	TempoDesbloqueio Duracao `json:"tempo_desbloqueio"`
+}
+
+// Lembretes contém os horários de referência e os canais dos alertas de marcação
+type Lembretes struct {
+	// EntradaAte é o horário ("09:30") até o qual a entrada deve ser registrada
+	EntradaAte string `json:"entrada_ate"`
+
+	// DuracaoAlmoco é a duração planejada do intervalo; vazio usa 1 hora
+	DuracaoAlmoco Duracao `json:"duracao_almoco"`
+
+	// CargaHoraria é o tempo de trabalho diário usado para calcular a saída; vazio usa 8 horas
+	CargaHoraria Duracao `json:"carga_horaria"`
+
+	// DiasUteis lista os dias da semana com expediente ("segunda" a "domingo"); vazio usa
+	// segunda a sexta
+	DiasUteis []string `json:"dias_uteis"`
+
+	// Folgas lista datas ("2026-12-25") sem expediente, como feriados e férias
+	Folgas []string `json:"folgas"`
+
+	// Adiamento é o tempo padrão de "lembretes adiar"
+	Adiamento Duracao `json:"adiamento"`
+
+	// Intervalo é a frequência de verificação de "lembretes monitorar"
+	Intervalo Duracao `json:"intervalo"`
+
+	// Repeticao é o tempo mínimo entre dois avisos do mesmo alerta durante o monitoramento
+	Repeticao Duracao `json:"repeticao"`
+
+	// Canais define por onde os alertas são enviados
+	Canais CanaisLembrete `json:"canais"`
+}
+
+// CanaisLembrete habilita os canais de entrega dos alertas
+type CanaisLembrete struct {
+	// Sino emite a campainha do terminal
+	Sino bool `json:"sino"`
+
+	// Desktop exibe uma notificação com notify-send
+	Desktop bool `json:"desktop"`
+
+	// Slack envia uma mensagem aos destinos de slack.destinos.por_tipo.lembrete ou à conversa do batedor
+	Slack bool `json:"slack"`
+}
+
+// HorarioEntrada converte EntradaAte para o tempo desde a meia-noite; vazio retorna zero
+func (l Lembretes) HorarioEntrada() (time.Duration, error) {
+	if l.EntradaAte == "" {
+		return 0, nil
+	}
+	horario, err := time.Parse("15:04", l.EntradaAte)
+	if err != nil {
+		return 0, fmt.Errorf("lembretes.entrada_ate inválido %q: use HH:MM", l.EntradaAte)
+	}
+	return time.Duration(horario.Hour())*time.Hour + time.Duration(horario.Minute())*time.Minute, nil
+}
+
+// diasSemana associa os nomes aceitos em dias_uteis ao dia da semana
+var diasSemana = map[string]time.Weekday{
+	"domingo": time.Sunday,
+	"segunda": time.Monday,
+	"terca":   time.Tuesday,
+	"terça":   time.Tuesday,
+	"quarta":  time.Wednesday,
+	"quinta":  time.Thursday,
+	"sexta":   time.Friday,
+	"sabado":  time.Saturday,
+	"sábado":  time.Saturday,
+}
+
+// DiasDaSemana converte DiasUteis; vazio retorna nil
+func (l Lembretes) DiasDaSemana() ([]time.Weekday, error) {
+	var dias []time.Weekday
+	for _, nome := range l.DiasUteis {
+		dia, ok := diasSemana[strings.TrimSuffix(strings.ToLower(strings.TrimSpace(nome)), "-feira")]
+		if !ok {
+			return nil, fmt.Errorf("lembretes.dias_uteis inválido %q: use segunda, terça, ..., domingo", nome)
+		}
+		dias = append(dias, dia)
+	}
+	return dias, nil
+}
+
+// DatasFolga converte Folgas para datas no fuso local
+func (l Lembretes) DatasFolga() ([]time.Time, error) {
+	var datas []time.Time
+	for _, texto := range l.Folgas {
+		data, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(texto), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("lembretes.folgas inválido %q: use AAAA-MM-DD", texto)
+		}
+		datas = append(datas, data)
+	}
+	return datas, nil
+}
+
+// Deteccao contém as regras de detecção da localização a partir do ambiente de rede
+type Deteccao struct {
+	// Habilitada ativa a detecção ao gerenciar a localização
+	Habilitada bool `json:"habilitada"`
+
+	// Automatica seleciona a localização detectada sem pedir confirmação
+	Automatica bool `json:"automatica"`
+
+	// ConfiancaMinima (0 a 1) é a confiança necessária para sugerir a localização detectada
+	ConfiancaMinima float64 `json:"confianca_minima"`
+
+	// Regras associam gateway, sub-rede, Wi-Fi ou VPN a uma localização
+	Regras []deteccao.Regra `json:"regras"`
+}
+
+// Snapshots contém as opções de gravação de snapshots das páginas
+type Snapshots struct {
+	// Gravar habilita a gravação a cada leitura de localização, operações ou status do Slack
+	Gravar bool `json:"gravar"`
+
+	// Diretorio é onde os snapshots são salvos; vazio usa ~/.batedorponto/snapshots
+	Diretorio string `json:"diretorio"`
+
+	// Ocultar lista textos substituídos por "[oculto]" nos snapshots, como o nome e a
+	// matrícula exibidos no Softtrade; e-mails, CPFs e tokens do Slack são sempre ocultados
+	Ocultar []string `json:"ocultar"`
+
+	// PadroesOcultos lista expressões regulares ocultadas da mesma forma
+	PadroesOcultos []string `json:"padroes_ocultos"`
+}
+
+// DiretorioSnapshots retorna o diretório de snapshots configurado
+func (c *Config) DiretorioSnapshots() string {
+	if c.Snapshots.Diretorio != "" {
+		return c.Snapshots.Diretorio
+	}
+	return filepath.Join(Diretorio(), "snapshots")
+}
+
+// Ponto contém as configurações do módulo de ponto
+type Ponto struct {
+	// Provedor é o nome do provedor de ponto: "softtrade" (padrão), "softtrade-http" ou "mock"
+	Provedor string `json:"provedor"`
+
+	// URLBase é o endereço do Softtrade; vazio usa o endereço de produção
+	URLBase string `json:"url_base"`
+
+	// IntervaloOpcional define como responder ao modal "Intervalo Opcional"
+	IntervaloOpcional IntervaloOpcional `json:"intervalo_opcional"`
+
+	// Retentativas define a política de retentativas por operação
+	Retentativas map[string]Retentativa `json:"retentativas"`
+
+	// Jornada define a validação das marcações contra o histórico do dia
+	Jornada Jornada `json:"jornada"`
+
+	// Localizacoes é o catálogo de localizações, com apelidos e modalidade remota ou presencial
+	Localizacoes []clockin.LocalizacaoConhecida `json:"localizacoes"`
+
+	// CenarioMock é o arquivo de cenário usado pelo provedor "mock"; vazio usa o comportamento padrão
+	CenarioMock string `json:"cenario_mock"`
+
+	// VerificacaoSessao é o intervalo entre as verificações que mantêm a sessão do Softtrade
+	// aberta e refazem o login quando ela expira; vazio usa 5 minutos
+	VerificacaoSessao Duracao `json:"verificacao_sessao"`
+}
+
+// Jornada contém os limites da validação da sequência diária de marcações
+type Jornada struct {
+	// Validar confere cada marcação contra as marcações do dia antes de executá-la
+	Validar bool `json:"validar"`
+
+	// JanelaDuplicidade é o tempo em que repetir a última marcação é recusado; vazio usa 10 minutos
+	JanelaDuplicidade Duracao `json:"janela_duplicidade"`
+
+	// IntervaloMinimo é a duração mínima do intervalo de refeição; vazio usa 1 hora
+	IntervaloMinimo Duracao `json:"intervalo_minimo"`
+}
+
+// Slack contém as configurações do módulo do Slack
+type Slack struct {
+	// Retentativas define a política de retentativas por operação
+	Retentativas map[string]Retentativa `json:"retentativas"`
+
+	// Backend pode ser "navegador" (padrão) ou "api", que usa a Web API do Slack sem navegador
+	Backend string `json:"backend"`
+
+	// URLBase é o endereço do workspace usado pelo backend "api"; vazio usa o workspace do batedor
+	URLBase string `json:"url_base"`
+
+	// Destinos associa, em por_tipo, o tipo ("entrada", "refeicao", "saida", "lembrete") e,
+	// em por_mensagem, o texto de uma mensagem às conversas que a recebem: IDs, "#canal" ou
+	// "@usuario"; vazio usa a conversa padrão do batedor. Tipos desconhecidos são recusados.
+	Destinos slack.Destinos `json:"destinos"`
+}
+
+// Retentativa define limites de tentativas de uma operação; campos omitidos usam o padrão do módulo
+type Retentativa struct {
+	MaxTentativas int     `json:"max_tentativas"`
+	EsperaInicial Duracao `json:"espera_inicial"`
+	EsperaMaxima  Duracao `json:"espera_maxima"`
+	Multiplicador float64 `json:"multiplicador"`
+	// Jitter omitido usa o padrão do módulo; 0 desliga o sorteio da espera
+	Jitter *float64 `json:"jitter"`
+}
+
+// Politica converte a configuração em uma common.PoliticaRetentativa
+func (r Retentativa) Politica() common.PoliticaRetentativa {
+	return common.PoliticaRetentativa{
+		MaxTentativas: r.MaxTentativas,
+		EsperaInicial: time.Duration(r.EsperaInicial),
+		EsperaMaxima:  time.Duration(r.EsperaMaxima),
+		Multiplicador: r.Multiplicador,
+		Jitter:        r.Jitter,
+	}
+}
+
+// Politicas converte um mapa de retentativas da configuração
+func Politicas(retentativas map[string]Retentativa) map[string]common.PoliticaRetentativa {
+	politicas := make(map[string]common.PoliticaRetentativa, len(retentativas))
+	for operacao, r := range retentativas {
+		politicas[operacao] = r.Politica()
+	}
+	return politicas
+}
+
+// Duracao é uma time.Duration representada no JSON como texto ("500ms", "2s", "1m30s")
+type Duracao time.Duration
+
+func (d *Duracao) UnmarshalJSON(dados []byte) error {
+	var texto string
+	if err := json.Unmarshal(dados, &texto); err != nil {
+		return fmt.Errorf("duração deve ser um texto como \"500ms\": %w", err)
+	}
+
+	duracao, err := time.ParseDuration(texto)
+	if err != nil {
+		return fmt.Errorf("duração inválida %q: %w", texto, err)
+	}
+
+	*d = Duracao(duracao)
+	return nil
+}
+
+func (d Duracao) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// IntervaloOpcional contém a política de resposta ao modal "Intervalo Opcional"
+type IntervaloOpcional struct {
+	// Politica pode ser "sim", "nao", "acima_de_minutos" ou "perguntar"
+	Politica string `json:"politica"`
+
+	// MinutosMinimos é usado pela política "acima_de_minutos"
+	MinutosMinimos int `json:"minutos_minimos"`
+}
+
+// Padrao retorna a configuração usada quando não há arquivo de configuração
+func Padrao() *Config {
+	return &Config{
+		Ponto: Ponto{
+			IntervaloOpcional: IntervaloOpcional{
+				Politica: "perguntar",
+			},
+			Jornada: Jornada{
+				Validar: true,
+			},
+		},
+		Deteccao: Deteccao{
+			ConfiancaMinima: 0.8,
+		},
+		Credenciais: Credenciais{
+			Armazenamento: "auto",
+		},
+		Cofre: Cofre{
+			TempoDesbloqueio: Duracao(8 * time.Hour),
+		},
+		Depuracao: Depuracao{
+			Atraso: Duracao(500 * time.Millisecond),
+		},
+		Lembretes: Lembretes{
+			EntradaAte: "09:30",
+			Adiamento:  Duracao(15 * time.Minute),
+			Intervalo:  Duracao(time.Minute),
+			Repeticao:  Duracao(15 * time.Minute),
+			Canais: CanaisLembrete{
+				Sino:    true,
+				Desktop: true,
+			},
+		},
+	}
+}
+
+// Diretorio retorna o diretório de configuração do usuário
+func Diretorio() string {
+	return filepath.Join(os.Getenv("HOME"), nomeDiretorio)
+}
+
+// Carregar lê o arquivo de configuração do diretório informado.
+// Campos ausentes mantêm os valores padrão; a ausência do arquivo não é um erro.
+func Carregar(diretorio string) (*Config, error) {
+	cfg := Padrao()
+
+	dados, err := os.ReadFile(filepath.Join(diretorio, nomeArquivo))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("erro ao ler configuração: %w", err)
+	}
+
+	if err := json.Unmarshal(dados, cfg); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", nomeArquivo, err)
+	}
+
+	return cfg, nil
+}
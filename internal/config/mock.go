@@ -0,0 +1,51 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// CenarioMock descreve, em um arquivo JSON, o comportamento dos mocks de autenticação e de ponto
+type CenarioMock struct {
+	// Semente inicializa o sorteio das falhas aleatórias
+	Semente int64 `json:"semente"`
+
+	// ProbabilidadeFalha é a chance (0 a 1) de cada chamada falhar; 0 desabilita
+	ProbabilidadeFalha float64 `json:"probabilidade_falha"`
+
+	// Falhas lista, pelo nome do método ("Login", "ExecutarOperacao", ...), as chamadas que falham
+	Falhas map[string][]int `json:"falhas"`
+
+	// Usuario e Senha, se informados, são as únicas credenciais aceitas pelo mock de login
+	Usuario string `json:"usuario"`
+	Senha   string `json:"senha"`
+
+	// Localizacoes disponíveis e a selecionada ao iniciar
+	Localizacoes       []string `json:"localizacoes"`
+	LocalizacaoInicial string   `json:"localizacao_inicial"`
+
+	// Operacoes lista as operações ("entrada", "almoco", "saida") disponíveis após cada marcação
+	Operacoes [][]string `json:"operacoes"`
+
+	// Inicio fixa o relógio do mock; ausente usa o horário real
+	Inicio time.Time `json:"inicio"`
+
+	// AvancoPorMarcacao adianta o relógio fixo a cada marcação
+	AvancoPorMarcacao Duracao `json:"avanco_por_marcacao"`
+}
+
+// CarregarCenarioMock lê um arquivo de cenário dos mocks
+func CarregarCenarioMock(caminho string) (*CenarioMock, error) {
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cenário do mock: %w", err)
+	}
+
+	var cenario CenarioMock
+	if err := json.Unmarshal(dados, &cenario); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", caminho, err)
+	}
+	return &cenario, nil
+}
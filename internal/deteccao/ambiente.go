@@ -0,0 +1,285 @@
+// Package deteccao identifica a localização de trabalho a partir do ambiente de rede
+// (gateway padrão, sub-rede local, rede Wi-Fi conectada e interfaces de VPN ativas).
+package deteccao
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// executar roda um comando e retorna a saída padrão; substituído nos testes
+var executar = func(nome string, args ...string) ([]byte, error) {
+	return exec.Command(nome, args...).Output()
+}
+
+// prefixosVPN identificam interfaces de túnel criadas por clientes de VPN
+var prefixosVPN = []string{"tun", "tap", "wg", "ppp", "ipsec", "vpn", "gpd", "cscotun"}
+
+// Ambiente reúne os dados de rede usados pelas regras
+type Ambiente struct {
+	// GatewayIP e GatewayMAC identificam o roteador da rota padrão
+	GatewayIP  net.IP
+	GatewayMAC string
+	// IPs são os endereços IPv4 e IPv6 das interfaces ativas
+	IPs []net.IP
+	// SSIDs são as redes Wi-Fi conectadas
+	SSIDs []string
+	// InterfacesVPN são as interfaces de túnel ativas
+	InterfacesVPN []string
+	// Avisos descrevem fontes que não puderam ser lidas e podem impedir regras de valer
+	Avisos []string
+}
+
+// ColetarAmbiente lê o ambiente de rede da máquina. raiz é prefixada aos caminhos de /proc,
+// /run e /etc ("" usa a raiz do sistema). Fontes indisponíveis são ignoradas.
+func ColetarAmbiente(raiz string) (Ambiente, error) {
+	var ambiente Ambiente
+
+	gateway, err := gatewayPadrao(filepath.Join(raiz, "/proc/net/route"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return ambiente, err
+	}
+	if gateway != nil {
+		ambiente.GatewayIP = gateway
+		ambiente.GatewayMAC, err = enderecoMAC(filepath.Join(raiz, "/proc/net/arp"), gateway)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return ambiente, err
+		}
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return ambiente, fmt.Errorf("erro ao listar interfaces de rede: %w", err)
+	}
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if interfaceVPN(iface.Name) {
+			ambiente.InterfacesVPN = append(ambiente.InterfacesVPN, iface.Name)
+		}
+		enderecos, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, endereco := range enderecos {
+			if rede, ok := endereco.(*net.IPNet); ok {
+				ambiente.IPs = append(ambiente.IPs, rede.IP)
+			}
+		}
+	}
+
+	ambiente.SSIDs, ambiente.Avisos = redesWiFi(raiz)
+	return ambiente, nil
+}
+
+func interfaceVPN(nome string) bool {
+	for _, prefixo := range prefixosVPN {
+		if strings.HasPrefix(nome, prefixo) {
+			return true
+		}
+	}
+	return false
+}
+
+// gatewayPadrao lê o gateway da rota padrão em /proc/net/route
+func gatewayPadrao(caminho string) (net.IP, error) {
+	arquivo, err := os.Open(caminho)
+	if err != nil {
+		return nil, err
+	}
+	defer arquivo.Close()
+
+	scanner := bufio.NewScanner(arquivo)
+	scanner.Scan() // cabeçalho
+	for scanner.Scan() {
+		campos := strings.Fields(scanner.Text())
+		if len(campos) < 4 || campos[1] != "00000000" {
+			continue
+		}
+		flags, err := strconv.ParseUint(campos[3], 16, 32)
+		if err != nil || flags&0x2 == 0 { // RTF_GATEWAY
+			continue
+		}
+		bytes, err := hex.DecodeString(campos[2])
+		if err != nil || len(bytes) != 4 {
+			continue
+		}
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(bytes))
+		return ip, nil
+	}
+	return nil, scanner.Err()
+}
+
+// enderecoMAC procura o endereço físico de ip na tabela ARP em /proc/net/arp
+func enderecoMAC(caminho string, ip net.IP) (string, error) {
+	arquivo, err := os.Open(caminho)
+	if err != nil {
+		return "", err
+	}
+	defer arquivo.Close()
+
+	scanner := bufio.NewScanner(arquivo)
+	scanner.Scan() // cabeçalho
+	for scanner.Scan() {
+		campos := strings.Fields(scanner.Text())
+		if len(campos) >= 4 && campos[0] == ip.String() && campos[3] != "00:00:00:00:00:00" {
+			return strings.ToLower(campos[3]), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// redesWiFi retorna os SSIDs das redes Wi-Fi conectadas. Usa o nmcli e, sem ele, o iw nas
+// interfaces de /proc/net/wireless, ambos acessíveis ao usuário comum; por último recorre
+// aos arquivos de conexão do NetworkManager, normalmente legíveis apenas pelo root. Sem
+// SSID e com conexões ativas que não puderam ser lidas, retorna um aviso.
+func redesWiFi(raiz string) ([]string, []string) {
+	if saida, err := executar("nmcli", "-t", "-f", "ACTIVE,SSID", "device", "wifi", "list", "--rescan", "no"); err == nil {
+		return ssidsNmcli(string(saida)), nil
+	}
+
+	var ssids []string
+	interfaces, _ := interfacesSemFio(filepath.Join(raiz, "/proc/net/wireless"))
+	for _, iface := range interfaces {
+		saida, err := executar("iw", "dev", iface, "link")
+		if err != nil {
+			continue
+		}
+		if ssid := ssidIw(string(saida)); ssid != "" {
+			ssids = append(ssids, ssid)
+		}
+	}
+	if len(ssids) > 0 {
+		return ssids, nil
+	}
+
+	return redesNetworkManager(raiz)
+}
+
+// ssidsNmcli extrai as redes ativas da saída de "nmcli -t -f ACTIVE,SSID device wifi list",
+// em que ":" no SSID aparece escapado como "\:"
+func ssidsNmcli(saida string) []string {
+	var ssids []string
+	for _, linha := range strings.Split(saida, "\n") {
+		ativa, ssid, ok := strings.Cut(strings.TrimSpace(linha), ":")
+		if !ok || (ativa != "yes" && ativa != "sim") {
+			continue
+		}
+		ssid = strings.NewReplacer(`\:`, ":", `\\`, `\`).Replace(ssid)
+		if ssid != "" && !slices.Contains(ssids, ssid) {
+			ssids = append(ssids, ssid)
+		}
+	}
+	return ssids
+}
+
+// ssidIw extrai o SSID da saída de "iw dev <interface> link"; vazio se desconectada
+func ssidIw(saida string) string {
+	for _, linha := range strings.Split(saida, "\n") {
+		if ssid, ok := strings.CutPrefix(strings.TrimSpace(linha), "SSID:"); ok {
+			return strings.TrimSpace(ssid)
+		}
+	}
+	return ""
+}
+
+// interfacesSemFio lista as interfaces de /proc/net/wireless, cujas duas primeiras linhas
+// são cabeçalho
+func interfacesSemFio(caminho string) ([]string, error) {
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return nil, err
+	}
+	linhas := strings.Split(string(dados), "\n")
+	var interfaces []string
+	for _, linha := range linhas[min(2, len(linhas)):] {
+		if nome, _, ok := strings.Cut(strings.TrimSpace(linha), ":"); ok && nome != "" {
+			interfaces = append(interfaces, nome)
+		}
+	}
+	return interfaces, nil
+}
+
+// redesNetworkManager retorna os SSIDs das conexões ativas do NetworkManager: os
+// dispositivos em /run/NetworkManager/devices indicam o UUID da conexão, cujo SSID está nos
+// arquivos de /etc/NetworkManager/system-connections
+func redesNetworkManager(raiz string) ([]string, []string) {
+	dispositivos, _ := filepath.Glob(filepath.Join(raiz, "/run/NetworkManager/devices/*"))
+	ativas := make(map[string]bool)
+	for _, dispositivo := range dispositivos {
+		valores, _ := lerChaveValor(dispositivo)
+		if uuid := valores["device.connection-uuid"]; uuid != "" {
+			ativas[uuid] = true
+		}
+	}
+	if len(ativas) == 0 {
+		return nil, nil
+	}
+
+	diretorio := filepath.Join(raiz, "/etc/NetworkManager/system-connections")
+	entradas, err := os.ReadDir(diretorio)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("não foi possível ler as conexões do NetworkManager (%v); instale o nmcli ou o iw para que as regras por SSID funcionem", err)}
+	}
+
+	var ssids []string
+	ilegiveis := 0
+	for _, entrada := range entradas {
+		valores, err := lerChaveValor(filepath.Join(diretorio, entrada.Name()))
+		if err != nil {
+			ilegiveis++
+			continue
+		}
+		if !ativas[valores["connection.uuid"]] {
+			continue
+		}
+		// Arquivos antigos usam a seção [802-11-wireless]
+		for _, chave := range []string{"wifi.ssid", "802-11-wireless.ssid"} {
+			if ssid := valores[chave]; ssid != "" {
+				ssids = append(ssids, ssid)
+				break
+			}
+		}
+	}
+	if len(ssids) == 0 && ilegiveis > 0 {
+		return nil, []string{fmt.Sprintf("%d arquivo(s) de conexão do NetworkManager não puderam ser lidos; instale o nmcli ou o iw para que as regras por SSID funcionem", ilegiveis)}
+	}
+	return ssids, nil
+}
+
+// lerChaveValor lê um arquivo no formato keyfile do NetworkManager, retornando as chaves
+// prefixadas pela seção ("wifi.ssid"); com erro de leitura, o mapa vem vazio
+func lerChaveValor(caminho string) (map[string]string, error) {
+	valores := make(map[string]string)
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		return valores, err
+	}
+
+	secao := ""
+	for _, linha := range strings.Split(string(dados), "\n") {
+		linha = strings.TrimSpace(linha)
+		switch {
+		case linha == "" || strings.HasPrefix(linha, "#"):
+		case strings.HasPrefix(linha, "[") && strings.HasSuffix(linha, "]"):
+			secao = strings.Trim(linha, "[]")
+		default:
+			if chave, valor, ok := strings.Cut(linha, "="); ok {
+				valores[secao+"."+strings.TrimSpace(chave)] = strings.TrimSpace(valor)
+			}
+		}
+	}
+	return valores, nil
+}
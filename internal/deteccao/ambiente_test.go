@@ -0,0 +1,178 @@
+package deteccao
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// escrever cria o arquivo com o conteúdo sob a raiz falsa
+func escrever(t *testing.T, raiz, caminho, conteudo string) string {
+	t.Helper()
+	completo := filepath.Join(raiz, caminho)
+	if err := os.MkdirAll(filepath.Dir(completo), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(completo, []byte(conteudo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return completo
+}
+
+// comandos substitui executar pelas saídas informadas; comandos ausentes falham
+func comandos(t *testing.T, saidas map[string]string) {
+	t.Helper()
+	original := executar
+	t.Cleanup(func() { executar = original })
+	executar = func(nome string, args ...string) ([]byte, error) {
+		linha := strings.Join(append([]string{nome}, args...), " ")
+		for prefixo, saida := range saidas {
+			if strings.HasPrefix(linha, prefixo) {
+				return []byte(saida), nil
+			}
+		}
+		return nil, errors.New("comando não encontrado: " + nome)
+	}
+}
+
+const cabecalhoRotas = "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"
+
+func TestGatewayPadrao(t *testing.T) {
+	casos := []struct {
+		nome     string
+		rotas    string
+		esperado string
+	}{
+		{"rota padrão em little-endian", "eth0\t00000000\t0101A8C0\t0003\t0\t0\t100\t00000000\t0\t0\t0\n", "192.168.1.1"},
+		{"rota local antes da padrão", "eth0\t0001A8C0\t00000000\t0001\t0\t0\t100\t00FFFFFF\t0\t0\t0\nwlan0\t00000000\tFE14000A\t0003\t0\t0\t600\t00000000\t0\t0\t0\n", "10.0.20.254"},
+		{"rota padrão sem RTF_GATEWAY", "tun0\t00000000\t00000000\t0001\t0\t0\t50\t00000000\t0\t0\t0\n", ""},
+		{"gateway em hexadecimal inválido", "eth0\t00000000\tXYZ\t0003\t0\t0\t100\t00000000\t0\t0\t0\n", ""},
+		{"sem rotas", "", ""},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			caminho := escrever(t, t.TempDir(), "/proc/net/route", cabecalhoRotas+c.rotas)
+			ip, err := gatewayPadrao(caminho)
+			if err != nil {
+				t.Fatalf("gatewayPadrao: %v", err)
+			}
+			obtido := ""
+			if ip != nil {
+				obtido = ip.String()
+			}
+			if obtido != c.esperado {
+				t.Fatalf("gateway = %q, esperado %q", obtido, c.esperado)
+			}
+		})
+	}
+}
+
+func TestEnderecoMAC(t *testing.T) {
+	arp := "IP address       HW type     Flags       HW address            Mask     Device\n" +
+		"192.168.1.1      0x1         0x2         AA:BB:CC:DD:EE:FF     *        eth0\n" +
+		"192.168.1.7      0x1         0x0         00:00:00:00:00:00     *        eth0\n"
+	caminho := escrever(t, t.TempDir(), "/proc/net/arp", arp)
+
+	casos := map[string]string{
+		"192.168.1.1": "aa:bb:cc:dd:ee:ff",
+		"192.168.1.7": "", // entrada incompleta
+		"192.168.1.9": "",
+	}
+	for ip, esperado := range casos {
+		mac, err := enderecoMAC(caminho, net.ParseIP(ip))
+		if err != nil || mac != esperado {
+			t.Errorf("enderecoMAC(%s) = %q, %v; esperado %q", ip, mac, err, esperado)
+		}
+	}
+}
+
+func TestLerChaveValor(t *testing.T) {
+	conteudo := "# comentário\n[connection]\nid = Empresa\nuuid=1234\n\n[wifi]\nssid=Empresa-Corp\nlinha sem igual\n[802-11-wireless]\nssid=Antiga=Rede\n"
+	valores, err := lerChaveValor(escrever(t, t.TempDir(), "conexao.nmconnection", conteudo))
+	if err != nil {
+		t.Fatalf("lerChaveValor: %v", err)
+	}
+
+	esperados := map[string]string{
+		"connection.id":        "Empresa",
+		"connection.uuid":      "1234",
+		"wifi.ssid":            "Empresa-Corp",
+		"802-11-wireless.ssid": "Antiga=Rede",
+	}
+	if len(valores) != len(esperados) {
+		t.Fatalf("valores = %v, esperados %v", valores, esperados)
+	}
+	for chave, esperado := range esperados {
+		if valores[chave] != esperado {
+			t.Errorf("%s = %q, esperado %q", chave, valores[chave], esperado)
+		}
+	}
+
+	if valores, err := lerChaveValor(filepath.Join(t.TempDir(), "ausente")); err == nil || len(valores) != 0 {
+		t.Errorf("arquivo ausente = %v, %v", valores, err)
+	}
+}
+
+func TestRedesWiFi(t *testing.T) {
+	wireless := "Inter-| sta-|   Quality        |   Discarded packets\n face | tus | link level noise |  nwid  crypt\nwlp2s0: 0000   60.  -50.  -256        0      0\n"
+
+	t.Run("nmcli", func(t *testing.T) {
+		comandos(t, map[string]string{"nmcli": "no:Vizinho\nyes:Empresa\\:5G\nyes:Empresa\\:5G\n"})
+		ssids, avisos := redesWiFi(t.TempDir())
+		if !slices.Equal(ssids, []string{"Empresa:5G"}) || len(avisos) != 0 {
+			t.Fatalf("redesWiFi = %v, %v", ssids, avisos)
+		}
+	})
+
+	t.Run("iw sem nmcli", func(t *testing.T) {
+		raiz := t.TempDir()
+		escrever(t, raiz, "/proc/net/wireless", wireless)
+		comandos(t, map[string]string{"iw dev wlp2s0 link": "Connected to aa:bb:cc:dd:ee:ff (on wlp2s0)\n\tSSID: Empresa-Corp\n\tfreq: 5180\n"})
+		ssids, avisos := redesWiFi(raiz)
+		if !slices.Equal(ssids, []string{"Empresa-Corp"}) || len(avisos) != 0 {
+			t.Fatalf("redesWiFi = %v, %v", ssids, avisos)
+		}
+	})
+
+	t.Run("arquivos do NetworkManager", func(t *testing.T) {
+		raiz := t.TempDir()
+		comandos(t, nil)
+		escrever(t, raiz, "/run/NetworkManager/devices/3", "[device]\nconnection-uuid=1234\n")
+		escrever(t, raiz, "/etc/NetworkManager/system-connections/Empresa.nmconnection", "[connection]\nuuid=1234\n[wifi]\nssid=Empresa-Corp\n")
+		escrever(t, raiz, "/etc/NetworkManager/system-connections/Casa.nmconnection", "[connection]\nuuid=9999\n[wifi]\nssid=Casa\n")
+		ssids, avisos := redesWiFi(raiz)
+		if !slices.Equal(ssids, []string{"Empresa-Corp"}) || len(avisos) != 0 {
+			t.Fatalf("redesWiFi = %v, %v", ssids, avisos)
+		}
+	})
+
+	t.Run("conexões ilegíveis geram aviso", func(t *testing.T) {
+		raiz := t.TempDir()
+		comandos(t, nil)
+		escrever(t, raiz, "/run/NetworkManager/devices/3", "[device]\nconnection-uuid=1234\n")
+		ssids, avisos := redesWiFi(raiz)
+		if len(ssids) != 0 || len(avisos) != 1 {
+			t.Fatalf("sem diretório de conexões: redesWiFi = %v, %v", ssids, avisos)
+		}
+
+		// Um diretório no lugar do arquivo falha na leitura como um arquivo sem permissão
+		if err := os.MkdirAll(filepath.Join(raiz, "/etc/NetworkManager/system-connections/Empresa.nmconnection"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		ssids, avisos = redesWiFi(raiz)
+		if len(ssids) != 0 || len(avisos) != 1 {
+			t.Fatalf("com arquivo ilegível: redesWiFi = %v, %v", ssids, avisos)
+		}
+	})
+
+	t.Run("sem conexões ativas", func(t *testing.T) {
+		comandos(t, nil)
+		if ssids, avisos := redesWiFi(t.TempDir()); len(ssids) != 0 || len(avisos) != 0 {
+			t.Fatalf("redesWiFi = %v, %v", ssids, avisos)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package deteccao
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Peso de cada critério na confiança de uma regra: o MAC do gateway identifica um roteador
+// específico, enquanto sub-redes privadas se repetem entre redes diferentes
+const (
+	pesoGatewayMAC = 0.9
+	pesoSSID       = 0.8
+	pesoVPN        = 0.7
+	pesoSubRede    = 0.5
+)
+
+// Regra associa critérios do ambiente de rede a uma localização do sistema de ponto.
+// Basta um critério coincidir para a regra valer; cada critério a mais aumenta a confiança.
+type Regra struct {
+	// Localizacao é o nome da clockin.Localizacao selecionada quando a regra vale
+	Localizacao string `json:"localizacao"`
+	// GatewayMAC é o endereço físico do roteador da rota padrão ("aa:bb:cc:dd:ee:ff")
+	GatewayMAC string `json:"gateway_mac,omitempty"`
+	// SubRede é uma rede em notação CIDR que contém um dos endereços locais ("10.20.0.0/16")
+	SubRede string `json:"sub_rede,omitempty"`
+	// SSID é o nome da rede Wi-Fi conectada
+	SSID string `json:"ssid,omitempty"`
+	// VPN é o nome ou prefixo de uma interface de túnel ativa ("wg0", "tun")
+	VPN string `json:"vpn,omitempty"`
+}
+
+// Candidata é uma localização compatível com o ambiente e as evidências encontradas
+type Candidata struct {
+	Localizacao string
+	Confianca   float64
+	Evidencias  []string
+}
+
+// Resultado é o relatório da detecção. Localizacao fica vazia se nenhuma regra valer.
+type Resultado struct {
+	Candidata
+	// Alternativas são as demais localizações compatíveis, em ordem de confiança
+	Alternativas []Candidata
+}
+
+// Validar verifica se as regras têm localização e critérios válidos
+func Validar(regras []Regra) error {
+	for i, regra := range regras {
+		if regra.Localizacao == "" {
+			return fmt.Errorf("regra %d: localização não informada", i+1)
+		}
+		if regra.GatewayMAC == "" && regra.SubRede == "" && regra.SSID == "" && regra.VPN == "" {
+			return fmt.Errorf("regra %d (%s): nenhum critério informado", i+1, regra.Localizacao)
+		}
+		if regra.SubRede != "" {
+			if _, _, err := net.ParseCIDR(regra.SubRede); err != nil {
+				return fmt.Errorf("regra %d (%s): sub-rede inválida %q", i+1, regra.Localizacao, regra.SubRede)
+			}
+		}
+		if regra.GatewayMAC != "" {
+			if _, err := net.ParseMAC(regra.GatewayMAC); err != nil {
+				return fmt.Errorf("regra %d (%s): MAC inválido %q", i+1, regra.Localizacao, regra.GatewayMAC)
+			}
+		}
+	}
+	return nil
+}
+
+// avaliar retorna as evidências do ambiente que satisfazem a regra
+func (r Regra) avaliar(ambiente Ambiente) (evidencias []string, pesos []float64) {
+	if r.GatewayMAC != "" && ambiente.GatewayMAC != "" && mesmoMAC(r.GatewayMAC, ambiente.GatewayMAC) {
+		evidencias = append(evidencias, "gateway "+ambiente.GatewayMAC)
+		pesos = append(pesos, pesoGatewayMAC)
+	}
+
+	if r.SSID != "" {
+		for _, ssid := range ambiente.SSIDs {
+			if ssid == r.SSID {
+				evidencias = append(evidencias, "Wi-Fi "+ssid)
+				pesos = append(pesos, pesoSSID)
+				break
+			}
+		}
+	}
+
+	if r.VPN != "" {
+		for _, iface := range ambiente.InterfacesVPN {
+			if strings.HasPrefix(iface, r.VPN) {
+				evidencias = append(evidencias, "VPN "+iface)
+				pesos = append(pesos, pesoVPN)
+				break
+			}
+		}
+	}
+
+	if r.SubRede != "" {
+		if _, rede, err := net.ParseCIDR(r.SubRede); err == nil {
+			for _, ip := range ambiente.IPs {
+				if rede.Contains(ip) {
+					evidencias = append(evidencias, fmt.Sprintf("endereço %s em %s", ip, rede))
+					pesos = append(pesos, pesoSubRede)
+					break
+				}
+			}
+		}
+	}
+	return evidencias, pesos
+}
+
+func mesmoMAC(a, b string) bool {
+	macA, errA := net.ParseMAC(a)
+	macB, errB := net.ParseMAC(b)
+	if errA != nil || errB != nil {
+		return strings.EqualFold(a, b)
+	}
+	return macA.String() == macB.String()
+}
+
+// Detectar aplica as regras ao ambiente. As evidências de regras da mesma localização
+// são somadas; quando duas localizações empatam, a confiança da escolhida é reduzida à metade.
+func Detectar(regras []Regra, ambiente Ambiente) Resultado {
+	porLocalizacao := make(map[string]*Candidata)
+	naoConfianca := make(map[string]float64)
+	var ordem []string
+
+	for _, regra := range regras {
+		evidencias, pesos := regra.avaliar(ambiente)
+		if len(evidencias) == 0 {
+			continue
+		}
+
+		candidata, ok := porLocalizacao[regra.Localizacao]
+		if !ok {
+			candidata = &Candidata{Localizacao: regra.Localizacao}
+			porLocalizacao[regra.Localizacao] = candidata
+			naoConfianca[regra.Localizacao] = 1
+			ordem = append(ordem, regra.Localizacao)
+		}
+		candidata.Evidencias = append(candidata.Evidencias, evidencias...)
+		for _, peso := range pesos {
+			naoConfianca[regra.Localizacao] *= 1 - peso
+		}
+		candidata.Confianca = 1 - naoConfianca[regra.Localizacao]
+	}
+
+	var candidatas []Candidata
+	for _, nome := range ordem {
+		candidatas = append(candidatas, *porLocalizacao[nome])
+	}
+	sort.SliceStable(candidatas, func(i, j int) bool {
+		return candidatas[i].Confianca > candidatas[j].Confianca
+	})
+
+	if len(candidatas) == 0 {
+		return Resultado{}
+	}
+
+	resultado := Resultado{Candidata: candidatas[0], Alternativas: candidatas[1:]}
+	if len(candidatas) > 1 && candidatas[1].Confianca == candidatas[0].Confianca {
+		resultado.Confianca /= 2
+	}
+	return resultado
+}
+
+// Relatorio descreve o resultado em texto para exibição ao usuário
+func (r Resultado) Relatorio() string {
+	if r.Localizacao == "" {
+		return "nenhuma regra de localização corresponde à rede atual"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (confiança %.0f%%: %s)", r.Localizacao, r.Confianca*100, strings.Join(r.Evidencias, ", "))
+	for _, alternativa := range r.Alternativas {
+		fmt.Fprintf(&b, "\n  também compatível: %s (%.0f%%: %s)", alternativa.Localizacao, alternativa.Confianca*100, strings.Join(alternativa.Evidencias, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package deteccao
+
+import (
+	"math"
+	"net"
+	"testing"
+)
+
+func TestValidar(t *testing.T) {
+	casos := []struct {
+		nome   string
+		regras []Regra
+		valida bool
+	}{
+		{"sem regras", nil, true},
+		{"regra completa", []Regra{{Localizacao: "Escritório", GatewayMAC: "AA-BB-CC-DD-EE-FF", SubRede: "10.20.0.0/16", SSID: "Corp", VPN: "wg"}}, true},
+		{"sem localização", []Regra{{SSID: "Corp"}}, false},
+		{"sem critérios", []Regra{{Localizacao: "Escritório"}}, false},
+		{"sub-rede inválida", []Regra{{Localizacao: "Escritório", SubRede: "10.20.0.0/33"}}, false},
+		{"MAC inválido", []Regra{{Localizacao: "Escritório", GatewayMAC: "aa:bb:cc"}}, false},
+		{"segunda regra inválida", []Regra{{Localizacao: "Casa", VPN: "tun"}, {Localizacao: "Escritório", SubRede: "rede"}}, false},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if err := Validar(c.regras); (err == nil) != c.valida {
+				t.Fatalf("Validar = %v, válida esperada: %v", err, c.valida)
+			}
+		})
+	}
+}
+
+func TestDetectar(t *testing.T) {
+	ambiente := Ambiente{
+		GatewayMAC:    "aa:bb:cc:dd:ee:ff",
+		IPs:           []net.IP{net.ParseIP("10.20.3.4")},
+		SSIDs:         []string{"Corp"},
+		InterfacesVPN: []string{"wg0"},
+	}
+
+	casos := []struct {
+		nome         string
+		regras       []Regra
+		localizacao  string
+		confianca    float64
+		alternativas int
+	}{
+		{"nenhuma regra vale", []Regra{{Localizacao: "Casa", SSID: "Casa"}}, "", 0, 0},
+		{"um critério", []Regra{{Localizacao: "Escritório", SubRede: "10.20.0.0/16"}}, "Escritório", pesoSubRede, 0},
+		{"MAC em outro formato", []Regra{{Localizacao: "Escritório", GatewayMAC: "AA-BB-CC-DD-EE-FF"}}, "Escritório", pesoGatewayMAC, 0},
+		{
+			"critérios da mesma regra se somam",
+			[]Regra{{Localizacao: "Escritório", GatewayMAC: "aa:bb:cc:dd:ee:ff", SSID: "Corp"}},
+			"Escritório", 1 - (1-pesoGatewayMAC)*(1-pesoSSID), 0,
+		},
+		{
+			"regras da mesma localização se somam",
+			[]Regra{{Localizacao: "Escritório", SSID: "Corp"}, {Localizacao: "Escritório", SubRede: "10.20.0.0/16"}},
+			"Escritório", 1 - (1-pesoSSID)*(1-pesoSubRede), 0,
+		},
+		{
+			"maior confiança vence",
+			[]Regra{{Localizacao: "Casa", VPN: "wg"}, {Localizacao: "Escritório", GatewayMAC: "aa:bb:cc:dd:ee:ff"}},
+			"Escritório", pesoGatewayMAC, 1,
+		},
+		{
+			"empate escolhe a primeira regra e reduz a confiança à metade",
+			[]Regra{{Localizacao: "Filial", SubRede: "10.0.0.0/8"}, {Localizacao: "Escritório", SubRede: "10.20.0.0/16"}},
+			"Filial", pesoSubRede / 2, 1,
+		},
+	}
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			resultado := Detectar(c.regras, ambiente)
+			if resultado.Localizacao != c.localizacao || math.Abs(resultado.Confianca-c.confianca) > 1e-9 || len(resultado.Alternativas) != c.alternativas {
+				t.Fatalf("Detectar = %+v; esperado %s com %.4f e %d alternativa(s)", resultado, c.localizacao, c.confianca, c.alternativas)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Package jornada modela a sequência diária de marcações (entrada, intervalo opcional,
+// retorno e saída) e valida cada nova marcação contra o histórico do dia, evitando
+// marcações duplicadas ou fora de ordem.
+package jornada
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/journal"
+)
+
+// Estado é a situação da jornada após a última marcação do dia
+type Estado int
+
+const (
+	// NaoIniciada indica que não há marcações no dia
+	NaoIniciada Estado = iota
+	// Trabalhando indica que a última marcação foi uma entrada
+	Trabalhando
+	// EmIntervalo indica que a última marcação foi a saída para refeição/descanso
+	EmIntervalo
+	// Encerrada indica que a última marcação foi a saída
+	Encerrada
+)
+
+func (e Estado) String() string {
+	switch e {
+	case NaoIniciada:
+		return "jornada não iniciada"
+	case Trabalhando:
+		return "trabalhando"
+	case EmIntervalo:
+		return "em intervalo"
+	case Encerrada:
+		return "jornada encerrada"
+	default:
+		return "desconhecido"
+	}
+}
+
+// Regras define os limites usados na validação
+type Regras struct {
+	// JanelaDuplicidade é o tempo em que repetir a última marcação é tratado como duplicidade
+	JanelaDuplicidade time.Duration
+	// IntervaloMinimo é a duração mínima do intervalo de refeição
+	IntervaloMinimo time.Duration
+}
+
+// RegrasPadrao são usadas nos campos de Regras não informados
+var RegrasPadrao = Regras{
+	JanelaDuplicidade: 10 * time.Minute,
+	IntervaloMinimo:   time.Hour,
+}
+
+// Mesclar retorna as regras com os campos zerados preenchidos a partir de padrao
+func (r Regras) Mesclar(padrao Regras) Regras {
+	if r.JanelaDuplicidade <= 0 {
+		r.JanelaDuplicidade = padrao.JanelaDuplicidade
+	}
+	if r.IntervaloMinimo <= 0 {
+		r.IntervaloMinimo = padrao.IntervaloMinimo
+	}
+	return r
+}
+
+// Gravidade classifica uma violação
+type Gravidade int
+
+const (
+	// Aviso permite a marcação após confirmação do usuário
+	Aviso Gravidade = iota
+	// Bloqueio recusa a marcação
+	Bloqueio
+)
+
+// Violacao descreve por que a marcação solicitada não segue a sequência esperada
+type Violacao struct {
+	Operacao  clockin.TipoOperacao
+	Gravidade Gravidade
+	Motivo    string
+}
+
+func (v *Violacao) Error() string {
+	return fmt.Sprintf("%s: %s", v.Operacao, v.Motivo)
+}
+
+// Jornada acompanha as marcações de um dia
+type Jornada struct {
+	regras    Regras
+	marcacoes []clockin.Marcacao
+}
+
+// Nova cria a jornada a partir das marcações já realizadas no dia, em ordem cronológica
+func Nova(regras Regras, marcacoes []clockin.Marcacao) *Jornada {
+	j := &Jornada{regras: regras.Mesclar(RegrasPadrao)}
+	for _, marcacao := range marcacoes {
+		j.Registrar(marcacao)
+	}
+	return j
+}
+
+// Registrar acrescenta uma marcação realizada
+func (j *Jornada) Registrar(marcacao clockin.Marcacao) {
+	j.marcacoes = append(j.marcacoes, marcacao)
+}
+
+// Marcacoes retorna as marcações do dia
+func (j *Jornada) Marcacoes() []clockin.Marcacao {
+	return append([]clockin.Marcacao(nil), j.marcacoes...)
+}
+
+// Estado retorna a situação após a última marcação
+func (j *Jornada) Estado() Estado {
+	if len(j.marcacoes) == 0 {
+		return NaoIniciada
+	}
+	switch j.marcacoes[len(j.marcacoes)-1].Operacao {
+	case clockin.Entrada:
+		return Trabalhando
+	case clockin.Almoco:
+		return EmIntervalo
+	default:
+		return Encerrada
+	}
+}
+
+// intervalos conta as saídas para refeição do dia
+func (j *Jornada) intervalos() int {
+	total := 0
+	for _, marcacao := range j.marcacoes {
+		if marcacao.Operacao == clockin.Almoco {
+			total++
+		}
+	}
+	return total
+}
+
+// Validar verifica se a operação pode ser marcada no momento informado. Retorna nil se a
+// marcação segue a sequência do dia.
+func (j *Jornada) Validar(operacao clockin.TipoOperacao, momento time.Time) *Violacao {
+	violacao := func(gravidade Gravidade, formato string, args ...any) *Violacao {
+		return &Violacao{Operacao: operacao, Gravidade: gravidade, Motivo: fmt.Sprintf(formato, args...)}
+	}
+
+	if len(j.marcacoes) > 0 {
+		ultima := j.marcacoes[len(j.marcacoes)-1]
+		decorrido := momento.Sub(ultima.Momento)
+		if ultima.Operacao == operacao && decorrido < j.regras.JanelaDuplicidade {
+			return violacao(Bloqueio, "%s já marcada às %s (há %s); provável marcação duplicada",
+				operacao, ultima.Momento.Format("15:04"), common.FormatarDuracao(decorrido))
+		}
+	}
+
+	switch j.Estado() {
+	case NaoIniciada:
+		switch operacao {
+		case clockin.Almoco:
+			return violacao(Bloqueio, "não há entrada registrada hoje")
+		case clockin.Saida:
+			return violacao(Bloqueio, "saída sem entrada registrada hoje")
+		}
+
+	case Trabalhando:
+		switch operacao {
+		case clockin.Entrada:
+			return violacao(Aviso, "já existe uma entrada às %s sem saída correspondente",
+				j.marcacoes[len(j.marcacoes)-1].Momento.Format("15:04"))
+		case clockin.Almoco:
+			if j.intervalos() > 0 {
+				return violacao(Aviso, "o intervalo de refeição já foi registrado hoje")
+			}
+		}
+
+	case EmIntervalo:
+		inicio := j.marcacoes[len(j.marcacoes)-1].Momento
+		switch operacao {
+		case clockin.Entrada:
+			if duracao := momento.Sub(inicio); duracao < j.regras.IntervaloMinimo {
+				return violacao(Aviso, "intervalo de %s, menor que o mínimo de %s",
+					common.FormatarDuracao(duracao), common.FormatarDuracao(j.regras.IntervaloMinimo))
+			}
+		case clockin.Almoco:
+			return violacao(Bloqueio, "o intervalo iniciado às %s ainda não foi encerrado", inicio.Format("15:04"))
+		case clockin.Saida:
+			return violacao(Aviso, "saída durante o intervalo iniciado às %s, sem retorno registrado", inicio.Format("15:04"))
+		}
+
+	case Encerrada:
+		switch operacao {
+		case clockin.Entrada:
+			return violacao(Aviso, "a jornada foi encerrada às %s; uma nova entrada reabre o dia",
+				j.marcacoes[len(j.marcacoes)-1].Momento.Format("15:04"))
+		case clockin.Almoco:
+			return violacao(Bloqueio, "a jornada já foi encerrada")
+		case clockin.Saida:
+			return violacao(Bloqueio, "saída já registrada às %s", j.marcacoes[len(j.marcacoes)-1].Momento.Format("15:04"))
+		}
+	}
+
+	return nil
+}
+
+// DoDiario extrai as marcações bem-sucedidas dos registros do diário
+func DoDiario(registros []journal.Registro) []clockin.Marcacao {
+	var marcacoes []clockin.Marcacao
+	for _, registro := range registros {
+		if registro.Evento != journal.EventoPonto || !registro.Sucesso {
+			continue
+		}
+		for _, operacao := range []clockin.TipoOperacao{clockin.Entrada, clockin.Almoco, clockin.Saida} {
+			if registro.Operacao == operacao.String() {
+				marcacoes = append(marcacoes, clockin.Marcacao{Operacao: operacao, Momento: registro.Momento})
+				break
+			}
+		}
+	}
+	return marcacoes
+}
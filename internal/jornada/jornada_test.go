@@ -0,0 +1,153 @@
+package jornada
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/journal"
+)
+
+// dia é a data das marcações dos testes; horários após as 24h caem no dia seguinte
+var dia = time.Date(2026, time.March, 10, 0, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+
+// as retorna o momento hh:mm a partir da meia-noite de dia
+func as(horas, minutos int) time.Time {
+	return dia.Add(time.Duration(horas)*time.Hour + time.Duration(minutos)*time.Minute)
+}
+
+// marcacoes monta o histórico alternando operação e momento
+func marcacoes(pares ...any) []clockin.Marcacao {
+	var historico []clockin.Marcacao
+	for i := 0; i < len(pares); i += 2 {
+		historico = append(historico, clockin.Marcacao{Operacao: pares[i].(clockin.TipoOperacao), Momento: pares[i+1].(time.Time)})
+	}
+	return historico
+}
+
+func TestValidar(t *testing.T) {
+	// semViolacao indica que a marcação deve ser aceita
+	const semViolacao Gravidade = -1
+
+	casos := []struct {
+		nome      string
+		historico []clockin.Marcacao
+		estado    Estado
+		operacao  clockin.TipoOperacao
+		momento   time.Time
+		gravidade Gravidade
+		motivo    string
+	}{
+		{"histórico vazio, entrada", nil, NaoIniciada, clockin.Entrada, as(8, 0), semViolacao, ""},
+		{"histórico vazio, refeição", nil, NaoIniciada, clockin.Almoco, as(12, 0), Bloqueio, "não há entrada registrada hoje"},
+		{"saída sem entrada", nil, NaoIniciada, clockin.Saida, as(17, 0), Bloqueio, "saída sem entrada registrada hoje"},
+
+		{"segunda entrada em minutos", marcacoes(clockin.Entrada, as(8, 0)), Trabalhando,
+			clockin.Entrada, as(8, 4), Bloqueio, "Entrada já marcada às 08:00 (há 4min); provável marcação duplicada"},
+		{"segunda entrada fora da janela", marcacoes(clockin.Entrada, as(8, 0)), Trabalhando,
+			clockin.Entrada, as(9, 0), Aviso, "já existe uma entrada às 08:00 sem saída correspondente"},
+		{"saída para refeição", marcacoes(clockin.Entrada, as(8, 0)), Trabalhando,
+			clockin.Almoco, as(12, 0), semViolacao, ""},
+		{"segunda refeição", marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0), clockin.Entrada, as(13, 0)), Trabalhando,
+			clockin.Almoco, as(15, 0), Aviso, "o intervalo de refeição já foi registrado hoje"},
+		{"saída após o retorno", marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0), clockin.Entrada, as(13, 0)), Trabalhando,
+			clockin.Saida, as(17, 0), semViolacao, ""},
+
+		{"intervalo menor que o mínimo", marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0)), EmIntervalo,
+			clockin.Entrada, as(12, 40), Aviso, "intervalo de 40min, menor que o mínimo de 1h"},
+		{"intervalo no mínimo", marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0)), EmIntervalo,
+			clockin.Entrada, as(13, 0), semViolacao, ""},
+		{"refeição repetida em minutos", marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0)), EmIntervalo,
+			clockin.Almoco, as(12, 2), Bloqueio, "provável marcação duplicada"},
+		{"refeição durante o intervalo", marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0)), EmIntervalo,
+			clockin.Almoco, as(12, 30), Bloqueio, "o intervalo iniciado às 12:00 ainda não foi encerrado"},
+		{"saída durante o intervalo", marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0)), EmIntervalo,
+			clockin.Saida, as(14, 0), Aviso, "saída durante o intervalo iniciado às 12:00"},
+
+		{"entrada após a saída", marcacoes(clockin.Entrada, as(8, 0), clockin.Saida, as(17, 0)), Encerrada,
+			clockin.Entrada, as(19, 0), Aviso, "a jornada foi encerrada às 17:00; uma nova entrada reabre o dia"},
+		{"refeição após a saída", marcacoes(clockin.Entrada, as(8, 0), clockin.Saida, as(17, 0)), Encerrada,
+			clockin.Almoco, as(19, 0), Bloqueio, "a jornada já foi encerrada"},
+		{"saída repetida", marcacoes(clockin.Entrada, as(8, 0), clockin.Saida, as(17, 0)), Encerrada,
+			clockin.Saida, as(17, 30), Bloqueio, "saída já registrada às 17:00"},
+
+		{"intervalo atravessando a meia-noite", marcacoes(clockin.Entrada, as(22, 0), clockin.Almoco, as(24, 50)), EmIntervalo,
+			clockin.Entrada, as(25, 20), Aviso, "intervalo de 30min, menor que o mínimo de 1h"},
+		{"retorno após a meia-noite", marcacoes(clockin.Entrada, as(22, 0), clockin.Almoco, as(23, 30)), EmIntervalo,
+			clockin.Entrada, as(24, 45), semViolacao, ""},
+		{"duplicidade atravessando a meia-noite", marcacoes(clockin.Entrada, as(23, 58)), Trabalhando,
+			clockin.Entrada, as(24, 3), Bloqueio, "Entrada já marcada às 23:58 (há 5min)"},
+		{"saída no dia seguinte", marcacoes(clockin.Entrada, as(22, 0), clockin.Almoco, as(26, 0), clockin.Entrada, as(27, 0)), Trabalhando,
+			clockin.Saida, as(30, 0), semViolacao, ""},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			j := Nova(Regras{}, caso.historico)
+			if estado := j.Estado(); estado != caso.estado {
+				t.Errorf("Estado = %s, esperado %s", estado, caso.estado)
+			}
+
+			violacao := j.Validar(caso.operacao, caso.momento)
+			if caso.gravidade == semViolacao {
+				if violacao != nil {
+					t.Fatalf("Validar(%s) = %v, esperado nil", caso.operacao, violacao)
+				}
+				return
+			}
+			if violacao == nil {
+				t.Fatalf("Validar(%s) = nil, esperado %q", caso.operacao, caso.motivo)
+			}
+			if violacao.Gravidade != caso.gravidade || violacao.Operacao != caso.operacao || !strings.Contains(violacao.Motivo, caso.motivo) {
+				t.Fatalf("Validar(%s) = %+v, esperado gravidade %d com %q", caso.operacao, violacao, caso.gravidade, caso.motivo)
+			}
+		})
+	}
+}
+
+func TestValidarRegras(t *testing.T) {
+	historico := marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0))
+	j := Nova(Regras{JanelaDuplicidade: time.Hour, IntervaloMinimo: 30 * time.Minute}, historico)
+
+	if violacao := j.Validar(clockin.Entrada, as(12, 40)); violacao != nil {
+		t.Fatalf("intervalo de 40min com mínimo de 30min: %v", violacao)
+	}
+	if violacao := j.Validar(clockin.Almoco, as(12, 45)); violacao == nil || !strings.Contains(violacao.Motivo, "duplicada") {
+		t.Fatalf("refeição repetida dentro da janela de 1h = %v, esperada duplicidade", violacao)
+	}
+}
+
+func TestRegistrar(t *testing.T) {
+	j := Nova(Regras{}, nil)
+	j.Registrar(clockin.Marcacao{Operacao: clockin.Entrada, Momento: as(8, 0)})
+
+	if j.Estado() != Trabalhando || len(j.Marcacoes()) != 1 {
+		t.Fatalf("após Registrar: %s com %d marcações", j.Estado(), len(j.Marcacoes()))
+	}
+	j.Marcacoes()[0].Operacao = clockin.Saida
+	if j.Estado() != Trabalhando {
+		t.Fatal("Marcacoes expôs o histórico interno")
+	}
+}
+
+func TestDoDiario(t *testing.T) {
+	registros := []journal.Registro{
+		{Momento: as(8, 0), Evento: journal.EventoPonto, Operacao: clockin.Entrada.String(), Sucesso: true},
+		{Momento: as(8, 1), Evento: journal.EventoPonto, Operacao: clockin.Entrada.String(), Sucesso: false},
+		{Momento: as(8, 2), Evento: journal.EventoLocalizacao, Operacao: clockin.Entrada.String(), Sucesso: true},
+		{Momento: as(12, 0), Evento: journal.EventoPonto, Operacao: clockin.Almoco.String(), Sucesso: true},
+		{Momento: as(12, 5), Evento: journal.EventoPonto, Operacao: "Desconhecida", Sucesso: true},
+	}
+
+	historico := DoDiario(registros)
+	esperado := marcacoes(clockin.Entrada, as(8, 0), clockin.Almoco, as(12, 0))
+	if len(historico) != len(esperado) {
+		t.Fatalf("DoDiario = %+v, esperado %+v", historico, esperado)
+	}
+	for i := range esperado {
+		if historico[i].Operacao != esperado[i].Operacao || !historico[i].Momento.Equal(esperado[i].Momento) {
+			t.Fatalf("DoDiario = %+v, esperado %+v", historico, esperado)
+		}
+	}
+}
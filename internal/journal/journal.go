@@ -0,0 +1,118 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+)
+
+const (
+	nomeDiretorio = "diario"
+	formatoDia    = "2006-01-02"
+)
+
+// Tipos de evento registrados no diário
+const (
+	EventoPonto         = "ponto"
+	EventoLocalizacao   = "localizacao"
+	EventoStatusSlack   = "slack_status"
+	EventoMensagemSlack = "slack_mensagem"
+)
+
+// Registro é uma linha do diário de operações
+type Registro struct {
+	Momento    time.Time `json:"momento"`
+	Evento     string    `json:"evento"`
+	Operacao   string    `json:"operacao,omitempty"`
+	Detalhe    string    `json:"detalhe,omitempty"`
+	Tentativas int       `json:"tentativas,omitempty"`
+	Sucesso    bool      `json:"sucesso"`
+	Erro       string    `json:"erro,omitempty"`
+}
+
+// Diario grava as operações realizadas em um arquivo JSON Lines por dia
+type Diario struct {
+	mu        sync.Mutex
+	diretorio string
+	relogio   common.Relogio
+}
+
+// Abrir retorna o diário armazenado em <diretorioConfig>/diario; relogio define o
+// horário dos registros sem Momento (nil usa o relógio do sistema)
+func Abrir(diretorioConfig string, relogio common.Relogio) *Diario {
+	return &Diario{
+		diretorio: filepath.Join(diretorioConfig, nomeDiretorio),
+		relogio:   common.RelogioOuSistema(relogio),
+	}
+}
+
+func (d *Diario) caminho(dia time.Time) string {
+	return filepath.Join(d.diretorio, dia.Format(formatoDia)+".jsonl")
+}
+
+// Registrar acrescenta um registro ao arquivo do dia; Momento vazio usa o horário atual
+func (d *Diario) Registrar(registro Registro) error {
+	if registro.Momento.IsZero() {
+		registro.Momento = d.relogio.Agora()
+	}
+
+	dados, err := json.Marshal(registro)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar registro do diário: %w", err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.MkdirAll(d.diretorio, 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório do diário: %w", err)
+	}
+
+	arquivo, err := os.OpenFile(d.caminho(registro.Momento), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir diário: %w", err)
+	}
+	defer arquivo.Close()
+
+	if _, err := arquivo.Write(append(dados, '\n')); err != nil {
+		return fmt.Errorf("erro ao gravar diário: %w", err)
+	}
+	return nil
+}
+
+// Ler retorna os registros do dia informado em ordem de gravação
+func (d *Diario) Ler(dia time.Time) ([]Registro, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	arquivo, err := os.Open(d.caminho(dia))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("erro ao abrir diário: %w", err)
+	}
+	defer arquivo.Close()
+
+	var registros []Registro
+	scanner := bufio.NewScanner(arquivo)
+	for scanner.Scan() {
+		var registro Registro
+		if err := json.Unmarshal(scanner.Bytes(), &registro); err != nil {
+			return nil, fmt.Errorf("erro ao interpretar diário: %w", err)
+		}
+		registros = append(registros, registro)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler diário: %w", err)
+	}
+
+	return registros, nil
+}
@@ -0,0 +1,95 @@
+package lembretes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const nomeArquivoAdiamentos = "lembretes.json"
+
+// Adiamentos guarda até quando cada tipo de alerta está silenciado
+type Adiamentos struct {
+	mu      sync.Mutex
+	caminho string
+}
+
+// AbrirAdiamentos retorna os adiamentos armazenados em <diretorioConfig>/lembretes.json
+func AbrirAdiamentos(diretorioConfig string) *Adiamentos {
+	return &Adiamentos{caminho: filepath.Join(diretorioConfig, nomeArquivoAdiamentos)}
+}
+
+func (a *Adiamentos) ler() (map[string]time.Time, error) {
+	adiados := make(map[string]time.Time)
+	dados, err := os.ReadFile(a.caminho)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return adiados, nil
+		}
+		return nil, fmt.Errorf("erro ao ler adiamentos: %w", err)
+	}
+	if err := json.Unmarshal(dados, &adiados); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", nomeArquivoAdiamentos, err)
+	}
+	return adiados, nil
+}
+
+// Adiar silencia o tipo de alerta até o momento informado
+func (a *Adiamentos) Adiar(tipo string, ate time.Time) error {
+	if !tipoValido(tipo) {
+		return fmt.Errorf("tipo de alerta desconhecido %q (use %v)", tipo, Tipos)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	adiados, err := a.ler()
+	if err != nil {
+		return err
+	}
+	adiados[tipo] = ate
+
+	dados, err := json.MarshalIndent(adiados, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar adiamentos: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(a.caminho), 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório de configuração: %w", err)
+	}
+	if err := os.WriteFile(a.caminho, dados, 0600); err != nil {
+		return fmt.Errorf("erro ao gravar adiamentos: %w", err)
+	}
+	return nil
+}
+
+// Filtrar remove os alertas adiados até depois de agora
+func (a *Adiamentos) Filtrar(alertas []Alerta, agora time.Time) ([]Alerta, error) {
+	a.mu.Lock()
+	adiados, err := a.ler()
+	a.mu.Unlock()
+	if err != nil {
+		return alertas, err
+	}
+
+	var ativos []Alerta
+	for _, alerta := range alertas {
+		if ate, ok := adiados[alerta.Tipo]; ok && agora.Before(ate) {
+			continue
+		}
+		ativos = append(ativos, alerta)
+	}
+	return ativos, nil
+}
+
+func tipoValido(tipo string) bool {
+	for _, t := range Tipos {
+		if t == tipo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+// Package lembretes avisa quando uma marcação esperada está atrasada: entrada não registrada
+// até o horário configurado, intervalo mais longo que o planejado ou jornada cumprida sem
+// saída. Só há alertas nos dias úteis que não sejam folga, e eles podem ser adiados por um período.
+package lembretes
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+)
+
+// Tipos de alerta, usados também para adiá-los
+const (
+	AlertaEntrada = "entrada"
+	AlertaAlmoco  = "almoco"
+	AlertaSaida   = "saida"
+)
+
+// Tipos lista os tipos de alerta válidos
+var Tipos = []string{AlertaEntrada, AlertaAlmoco, AlertaSaida}
+
+// Regras define quando cada alerta é disparado
+type Regras struct {
+	// EntradaAte é o horário do dia (desde a meia-noite) até o qual a entrada deve ser registrada
+	EntradaAte time.Duration
+	// DuracaoAlmoco é a duração planejada do intervalo de refeição
+	DuracaoAlmoco time.Duration
+	// CargaHoraria é o tempo de trabalho diário; cumprido sem saída, gera o alerta de saída
+	CargaHoraria time.Duration
+	// DiasUteis são os dias da semana com expediente; fora deles não há alertas
+	DiasUteis []time.Weekday
+	// Folgas são datas sem expediente, como feriados e férias; só o dia é considerado
+	Folgas []time.Time
+}
+
+// RegrasPadrao são usadas nos campos de Regras não informados
+var RegrasPadrao = Regras{
+	EntradaAte:    9*time.Hour + 30*time.Minute,
+	DuracaoAlmoco: time.Hour,
+	CargaHoraria:  8 * time.Hour,
+	DiasUteis:     []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+}
+
+// Mesclar retorna as regras com os campos zerados preenchidos a partir de padrao
+func (r Regras) Mesclar(padrao Regras) Regras {
+	if r.EntradaAte <= 0 {
+		r.EntradaAte = padrao.EntradaAte
+	}
+	if r.DuracaoAlmoco <= 0 {
+		r.DuracaoAlmoco = padrao.DuracaoAlmoco
+	}
+	if r.CargaHoraria <= 0 {
+		r.CargaHoraria = padrao.CargaHoraria
+	}
+	if len(r.DiasUteis) == 0 {
+		r.DiasUteis = padrao.DiasUteis
+	}
+	return r
+}
+
+// DiaUtil indica se o dia tem expediente: é um dos dias úteis e não é folga
+func (r Regras) DiaUtil(dia time.Time) bool {
+	if !slices.Contains(r.DiasUteis, dia.Weekday()) {
+		return false
+	}
+	ano, mes, d := dia.Date()
+	for _, folga := range r.Folgas {
+		if a, m, df := folga.Date(); a == ano && m == mes && df == d {
+			return false
+		}
+	}
+	return true
+}
+
+// Alerta é um lembrete de marcação atrasada
+type Alerta struct {
+	Tipo     string
+	Mensagem string
+	// Desde é o momento em que a marcação passou a estar atrasada
+	Desde time.Time
+}
+
+// Verificar retorna os alertas cabíveis no momento, dadas as marcações do dia em ordem.
+// Fins de semana e folgas não geram alertas.
+func Verificar(regras Regras, marcacoes []clockin.Marcacao, agora time.Time) []Alerta {
+	regras = regras.Mesclar(RegrasPadrao)
+	if !regras.DiaUtil(agora) {
+		return nil
+	}
+
+	if len(marcacoes) == 0 {
+		ano, mes, dia := agora.Date()
+		limite := time.Date(ano, mes, dia, 0, 0, 0, 0, agora.Location()).Add(regras.EntradaAte)
+		if agora.After(limite) {
+			return []Alerta{{
+				Tipo:     AlertaEntrada,
+				Mensagem: fmt.Sprintf("Nenhuma entrada registrada até %s", limite.Format("15:04")),
+				Desde:    limite,
+			}}
+		}
+		return nil
+	}
+
+	ultima := marcacoes[len(marcacoes)-1]
+	switch ultima.Operacao {
+	case clockin.Almoco:
+		limite := ultima.Momento.Add(regras.DuracaoAlmoco)
+		if agora.After(limite) {
+			return []Alerta{{
+				Tipo: AlertaAlmoco,
+				Mensagem: fmt.Sprintf("Intervalo iniciado às %s já passou de %s; registre o retorno",
+					ultima.Momento.Format("15:04"), common.FormatarDuracao(regras.DuracaoAlmoco)),
+				Desde: limite,
+			}}
+		}
+
+	case clockin.Entrada:
+		trabalhado := tempoTrabalhado(marcacoes, agora)
+		if trabalhado >= regras.CargaHoraria {
+			return []Alerta{{
+				Tipo: AlertaSaida,
+				Mensagem: fmt.Sprintf("Jornada de %s cumprida (%s trabalhadas) sem saída registrada",
+					common.FormatarDuracao(regras.CargaHoraria), common.FormatarDuracao(trabalhado)),
+				Desde: agora.Add(regras.CargaHoraria - trabalhado),
+			}}
+		}
+	}
+	return nil
+}
+
+// tempoTrabalhado soma os períodos entre cada entrada e a marcação seguinte; uma entrada
+// em aberto conta até agora
+func tempoTrabalhado(marcacoes []clockin.Marcacao, agora time.Time) time.Duration {
+	var total time.Duration
+	for i, marcacao := range marcacoes {
+		if marcacao.Operacao != clockin.Entrada {
+			continue
+		}
+		fim := agora
+		if i+1 < len(marcacoes) {
+			fim = marcacoes[i+1].Momento
+		}
+		total += fim.Sub(marcacao.Momento)
+	}
+	return total
+}
@@ -0,0 +1,34 @@
+package lembretes
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerificarDiasUteis(t *testing.T) {
+	segunda := time.Date(2026, 10, 19, 11, 0, 0, 0, time.Local)
+	sabado := time.Date(2026, 10, 17, 11, 0, 0, 0, time.Local)
+	feriado := time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)
+
+	casos := []struct {
+		nome    string
+		regras  Regras
+		agora   time.Time
+		alertas int
+	}{
+		{"segunda sem entrada", Regras{}, segunda, 1},
+		{"sábado fora dos dias úteis padrão", Regras{}, sabado, 0},
+		{"sábado configurado como dia útil", Regras{DiasUteis: []time.Weekday{time.Saturday}}, sabado, 1},
+		{"segunda fora dos dias configurados", Regras{DiasUteis: []time.Weekday{time.Saturday}}, segunda, 0},
+		{"folga", Regras{Folgas: []time.Time{feriado}}, feriado.Add(11 * time.Hour), 0},
+		{"dia seguinte à folga", Regras{Folgas: []time.Time{feriado}}, feriado.Add(35 * time.Hour), 1},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			if alertas := Verificar(caso.regras, nil, caso.agora); len(alertas) != caso.alertas {
+				t.Fatalf("Verificar = %+v, esperados %d alertas", alertas, caso.alertas)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package lembretes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+)
+
+// Notificador entrega um alerta ao usuário
+type Notificador interface {
+	Notificar(alerta Alerta) error
+}
+
+// Sino escreve o caractere de campainha e o alerta no terminal
+type Sino struct {
+	Saida io.Writer
+}
+
+func (s Sino) Notificar(alerta Alerta) error {
+	_, err := fmt.Fprintf(s.Saida, "\a⏰ %s\n", alerta.Mensagem)
+	return err
+}
+
+// Desktop exibe uma notificação na área de trabalho com notify-send
+type Desktop struct{}
+
+func (Desktop) Notificar(alerta Alerta) error {
+	caminho, err := exec.LookPath("notify-send")
+	if err != nil {
+		return fmt.Errorf("notify-send não encontrado: %w", err)
+	}
+	saida, err := exec.Command(caminho, "--urgency=critical", "--app-name=batponto", "Batedor de ponto", alerta.Mensagem).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("erro ao executar notify-send: %w: %s", err, saida)
+	}
+	return nil
+}
+
+// Mensagem envia o alerta como texto, por exemplo pelo GerenciadorMensagem do Slack
+type Mensagem func(texto string) error
+
+func (m Mensagem) Notificar(alerta Alerta) error {
+	return m("⏰ " + alerta.Mensagem)
+}
+
+// Notificar entrega o alerta a todos os notificadores, mesmo que algum falhe
+func Notificar(notificadores []Notificador, alerta Alerta) error {
+	var erros []error
+	for _, notificador := range notificadores {
+		if err := notificador.Notificar(alerta); err != nil {
+			erros = append(erros, err)
+		}
+	}
+	return errors.Join(erros...)
+}
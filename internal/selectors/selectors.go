@@ -0,0 +1,190 @@
+package selectors
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// VersaoEsquema é a versão do formato do mapa de seletores suportada por este binário
+const VersaoEsquema = 1
+
+// NomeArquivo é o nome do arquivo de sobrescrita no diretório de configuração
+const NomeArquivo = "seletores.json"
+
+//go:embed seletores.json
+var mapaPadrao []byte
+
+// Mapa contém os seletores CSS e rótulos usados para automatizar o Softtrade e o Slack
+type Mapa struct {
+	// Versao é a versão do esquema do mapa
+	Versao    int       `json:"versao"`
+	Softtrade Softtrade `json:"softtrade"`
+	Slack     Slack     `json:"slack"`
+}
+
+// Softtrade contém os seletores do portal de ponto.
+// A tag pagina indica em qual página o seletor é validado; dinamico indica elementos
+// que só existem após uma interação (modais, tabelas abertas por clique).
+type Softtrade struct {
+	LoginUsuario       string   `json:"login_usuario" pagina:"login"`
+	LoginSenha         string   `json:"login_senha" pagina:"login"`
+	LoginEntrar        string   `json:"login_entrar" pagina:"login"`
+	MensagensErro      []string `json:"mensagens_erro" pagina:"login" dinamico:"true"`
+	Formulario         string   `json:"formulario" pagina:"marcacao"`
+	BotaoLocalizacao   string   `json:"botao_localizacao" pagina:"marcacao"`
+	TextoLocalizacao   string   `json:"texto_localizacao" pagina:"marcacao" dinamico:"true"`
+	TabelaLocalizacoes string   `json:"tabela_localizacoes" pagina:"marcacao"`
+	TabelaMarcacoes    string   `json:"tabela_marcacoes" pagina:"marcacao"`
+	BloqueioAjax       string   `json:"bloqueio_ajax" pagina:"marcacao"`
+	Dialogo            string   `json:"dialogo" pagina:"marcacao" dinamico:"true"`
+	DialogoTitulo      string   `json:"dialogo_titulo" pagina:"marcacao" dinamico:"true"`
+	DialogoConteudo    string   `json:"dialogo_conteudo" pagina:"marcacao" dinamico:"true"`
+	TrocaSenhaCampos   string   `json:"troca_senha_campos" pagina:"troca_senha" dinamico:"true"`
+	TrocaSenhaSalvar   string   `json:"troca_senha_salvar" pagina:"troca_senha" dinamico:"true"`
+	CodigoCampos       string   `json:"codigo_campos" pagina:"codigo" dinamico:"true"`
+	CodigoConfirmar    string   `json:"codigo_confirmar" pagina:"codigo" dinamico:"true"`
+	Rotulos            Rotulos  `json:"rotulos"`
+}
+
+// Rotulos contém os textos usados para localizar botões pelo conteúdo
+type Rotulos struct {
+	Entrada        string `json:"entrada"`
+	Almoco         string `json:"almoco"`
+	Saida          string `json:"saida"`
+	ModalIntervalo string `json:"modal_intervalo"`
+	Sim            string `json:"sim"`
+	Nao            string `json:"nao"`
+}
+
+// Slack contém os seletores da interface web do Slack
+type Slack struct {
+	CampoMensagem   string `json:"campo_mensagem" pagina:"app"`
+	BotaoUsuario    string `json:"botao_usuario" pagina:"app"`
+	ItemStatus      string `json:"item_status" pagina:"app" dinamico:"true"`
+	ModalStatus     string `json:"modal_status" pagina:"app" dinamico:"true"`
+	LimparStatus    string `json:"limpar_status" pagina:"app" dinamico:"true"`
+	SecaoPresets    string `json:"secao_presets" pagina:"app" dinamico:"true"`
+	ContainerPreset string `json:"container_preset" pagina:"app" dinamico:"true"`
+	BotaoPreset     string `json:"botao_preset" pagina:"app" dinamico:"true"`
+	TextoPreset     string `json:"texto_preset" pagina:"app" dinamico:"true"`
+	SalvarStatus    string `json:"salvar_status" pagina:"app" dinamico:"true"`
+	FecharModal     string `json:"fechar_modal" pagina:"app" dinamico:"true"`
+	CorpoStatus     string `json:"corpo_status" pagina:"app" dinamico:"true"`
+	TextoStatus     string `json:"texto_status" pagina:"app" dinamico:"true"`
+	EmojiStatus     string `json:"emoji_status" pagina:"app" dinamico:"true"`
+}
+
+// Padrao retorna o mapa embutido no binário
+func Padrao() *Mapa {
+	var mapa Mapa
+	if err := json.Unmarshal(mapaPadrao, &mapa); err != nil {
+		panic(fmt.Sprintf("mapa de seletores embutido inválido: %v", err))
+	}
+	return &mapa
+}
+
+// Carregar retorna o mapa embutido sobrescrito pelo arquivo seletores.json do diretório
+// de configuração, se existir. Apenas os campos presentes no arquivo são substituídos e
+// chaves desconhecidas são um erro.
+func Carregar(diretorio string) (*Mapa, error) {
+	mapa := Padrao()
+
+	caminho := filepath.Join(diretorio, NomeArquivo)
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return mapa, nil
+		}
+		return nil, fmt.Errorf("erro ao ler %s: %w", caminho, err)
+	}
+
+	var cabecalho struct {
+		Versao int `json:"versao"`
+	}
+	if err := json.Unmarshal(dados, &cabecalho); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", caminho, err)
+	}
+	if cabecalho.Versao != VersaoEsquema {
+		return nil, fmt.Errorf("%s usa a versão de esquema %d, mas esta versão do batedor suporta apenas a %d", caminho, cabecalho.Versao, VersaoEsquema)
+	}
+
+	// Chaves desconhecidas, como um seletor com o nome digitado errado, são recusadas em
+	// vez de ignoradas
+	decodificador := json.NewDecoder(bytes.NewReader(dados))
+	decodificador.DisallowUnknownFields()
+	if err := decodificador.Decode(mapa); err != nil {
+		return nil, fmt.Errorf("erro ao interpretar %s: %w", caminho, err)
+	}
+
+	return mapa, nil
+}
+
+// Seletor descreve um seletor do mapa para fins de validação
+type Seletor struct {
+	// Nome identifica o seletor no formato "softtrade.botao_localizacao"
+	Nome string
+	// Valor é o seletor CSS
+	Valor string
+	// Pagina é a página onde o seletor deve existir ("login", "marcacao", "troca_senha", "codigo" ou "app")
+	Pagina string
+	// Dinamico indica que o elemento só aparece após uma interação
+	Dinamico bool
+}
+
+// Listar retorna todos os seletores do mapa com seus metadados de validação
+func (m *Mapa) Listar() []Seletor {
+	var seletores []Seletor
+	seletores = append(seletores, listarCampos("softtrade", reflect.ValueOf(m.Softtrade))...)
+	seletores = append(seletores, listarCampos("slack", reflect.ValueOf(m.Slack))...)
+	return seletores
+}
+
+func listarCampos(prefixo string, valor reflect.Value) []Seletor {
+	var seletores []Seletor
+	tipo := valor.Type()
+	for i := 0; i < tipo.NumField(); i++ {
+		campo := tipo.Field(i)
+		pagina := campo.Tag.Get("pagina")
+		if pagina == "" {
+			continue
+		}
+
+		base := Seletor{
+			Nome:     prefixo + "." + campo.Tag.Get("json"),
+			Pagina:   pagina,
+			Dinamico: campo.Tag.Get("dinamico") == "true",
+		}
+
+		switch v := valor.Field(i).Interface().(type) {
+		case string:
+			base.Valor = v
+			seletores = append(seletores, base)
+		case []string:
+			for j, item := range v {
+				s := base
+				s.Nome = fmt.Sprintf("%s[%d]", base.Nome, j)
+				s.Valor = item
+				seletores = append(seletores, s)
+			}
+		}
+	}
+	return seletores
+}
+
+// JS retorna o valor como literal de string JavaScript, para uso seguro em scripts injetados
+func JS(valor string) string {
+	literal, _ := json.Marshal(valor)
+	return string(literal)
+}
+
+// JSLista retorna os valores como literal de array JavaScript
+func JSLista(valores []string) string {
+	literal, _ := json.Marshal(valores)
+	return string(literal)
+}
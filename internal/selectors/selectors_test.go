@@ -0,0 +1,39 @@
+package selectors
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCarregarSobrescrita(t *testing.T) {
+	diretorio := t.TempDir()
+	conteudo := `{"versao": 1, "softtrade": {"botao_localizacao": "#novo"}}`
+	if err := os.WriteFile(filepath.Join(diretorio, NomeArquivo), []byte(conteudo), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mapa, err := Carregar(diretorio)
+	if err != nil {
+		t.Fatalf("Carregar: %v", err)
+	}
+	if mapa.Softtrade.BotaoLocalizacao != "#novo" || mapa.Softtrade.Formulario != Padrao().Softtrade.Formulario {
+		t.Fatalf("sobrescrita aplicada incorretamente: %+v", mapa.Softtrade)
+	}
+}
+
+func TestCarregarChaveDesconhecida(t *testing.T) {
+	for _, conteudo := range []string{
+		`{"versao": 1, "softtrade": {"botao_localizaco": "#novo"}}`,
+		`{"versao": 1, "softrade": {}}`,
+	} {
+		diretorio := t.TempDir()
+		if err := os.WriteFile(filepath.Join(diretorio, NomeArquivo), []byte(conteudo), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Carregar(diretorio); err == nil || !strings.Contains(err.Error(), "unknown field") {
+			t.Errorf("Carregar(%s) = %v, esperado erro de chave desconhecida", conteudo, err)
+		}
+	}
+}
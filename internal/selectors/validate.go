@@ -0,0 +1,81 @@
+package selectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Situações possíveis de um seletor validado
+const (
+	SituacaoEncontrado    = "encontrado"
+	SituacaoAusente       = "ausente"
+	SituacaoNaoVerificado = "nao_verificado"
+	SituacaoInvalido      = "invalido"
+)
+
+// ResultadoValidacao é o resultado da verificação de um seletor na página
+type ResultadoValidacao struct {
+	Seletor  Seletor
+	Situacao string
+	Detalhe  string
+}
+
+// Falhou indica se o resultado deve ser tratado como erro.
+// Seletores dinâmicos ausentes são apenas não verificados, pois dependem de interação.
+func (r ResultadoValidacao) Falhou() bool {
+	return r.Situacao == SituacaoAusente || r.Situacao == SituacaoInvalido
+}
+
+// Filtrar retorna os seletores do mapa que pertencem à página informada
+func (m *Mapa) Filtrar(pagina string) []Seletor {
+	var filtrados []Seletor
+	for _, s := range m.Listar() {
+		if s.Pagina == pagina {
+			filtrados = append(filtrados, s)
+		}
+	}
+	return filtrados
+}
+
+// Validar verifica cada seletor na página atualmente carregada em ctx
+func Validar(ctx context.Context, seletores []Seletor) ([]ResultadoValidacao, error) {
+	resultados := make([]ResultadoValidacao, 0, len(seletores))
+	for _, seletor := range seletores {
+		var verificacao struct {
+			Valido     bool   `json:"valido"`
+			Encontrado bool   `json:"encontrado"`
+			Erro       string `json:"erro"`
+		}
+
+		err := chromedp.Run(ctx, chromedp.Evaluate(fmt.Sprintf(`
+			(function() {
+				try {
+					return {valido: true, encontrado: document.querySelector(%s) !== null};
+				} catch (e) {
+					return {valido: false, erro: e.message};
+				}
+			})()
+		`, JS(seletor.Valor)), &verificacao))
+		if err != nil {
+			return resultados, fmt.Errorf("erro ao verificar seletor %s: %w", seletor.Nome, err)
+		}
+
+		resultado := ResultadoValidacao{Seletor: seletor}
+		switch {
+		case !verificacao.Valido:
+			resultado.Situacao = SituacaoInvalido
+			resultado.Detalhe = verificacao.Erro
+		case verificacao.Encontrado:
+			resultado.Situacao = SituacaoEncontrado
+		case seletor.Dinamico:
+			resultado.Situacao = SituacaoNaoVerificado
+			resultado.Detalhe = "elemento depende de interação na página"
+		default:
+			resultado.Situacao = SituacaoAusente
+		}
+		resultados = append(resultados, resultado)
+	}
+	return resultados, nil
+}
@@ -0,0 +1,232 @@
+// Package sessao mantém a sessão do Softtrade aberta enquanto o programa roda: verifica
+// periodicamente se ela continua válida e, quando o Softtrade a encerra, refaz o login e
+// repete uma vez a chamada do módulo de ponto que falhou. Marcações só são repetidas se
+// a operação continuar disponível após o novo login.
+package sessao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/auth"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+)
+
+// IntervaloPadrao é o intervalo entre as verificações quando Config.Intervalo é zero
+const IntervaloPadrao = 5 * time.Minute
+
+// tempoLimiteVerificacao limita cada verificação da sessão
+const tempoLimiteVerificacao = 30 * time.Second
+
+// Config contém as opções do Gerenciador
+type Config struct {
+	// Intervalo entre as verificações da sessão; zero usa IntervaloPadrao
+	Intervalo time.Duration
+
+	// Seletores identificam a página de login e a de marcação; nil usa o mapa embutido
+	Seletores *selectors.Softtrade
+
+	// Relogio mede o intervalo e os tempos limite; nil usa o relógio do sistema
+	Relogio common.Relogio
+}
+
+// Gerenciador implementa clockin.Module sobre um módulo de ponto que usa a aba autenticada
+// pelo módulo auth. As chamadas são serializadas com as verificações periódicas; uma
+// chamada que falha com a sessão expirada é repetida uma vez após novo login.
+type Gerenciador struct {
+	mu        sync.Mutex
+	auth      auth.Module
+	ponto     clockin.Module
+	creds     auth.Credentials
+	seletores selectors.Softtrade
+	relogio   common.Relogio
+	intervalo time.Duration
+
+	// ativo é cancelado por Close e encerra as verificações periódicas
+	ativo context.Context
+	parar context.CancelFunc
+}
+
+// Novo cria o gerenciador para a sessão já autenticada com creds
+func Novo(authModule auth.Module, ponto clockin.Module, creds auth.Credentials, config Config) *Gerenciador {
+	seletores := config.Seletores
+	if seletores == nil {
+		seletores = &selectors.Padrao().Softtrade
+	}
+	intervalo := config.Intervalo
+	if intervalo <= 0 {
+		intervalo = IntervaloPadrao
+	}
+
+	ativo, parar := context.WithCancel(context.Background())
+	return &Gerenciador{
+		ativo:     ativo,
+		parar:     parar,
+		auth:      authModule,
+		ponto:     ponto,
+		creds:     creds,
+		seletores: *seletores,
+		relogio:   common.RelogioOuSistema(config.Relogio),
+		intervalo: intervalo,
+	}
+}
+
+// Iniciar começa as verificações periódicas em segundo plano; elas terminam com Close
+func (g *Gerenciador) Iniciar() {
+	go func() {
+		for {
+			if err := common.Dormir(g.ativo, g.relogio, g.intervalo); err != nil {
+				return
+			}
+			if err := g.Verificar(); err != nil {
+				fmt.Printf("\n⚠️  Aviso: Falha ao manter a sessão do Softtrade: %v\n", err)
+			}
+		}
+	}()
+}
+
+// Verificar mantém a sessão ativa recarregando a página de marcação e refaz o login se o
+// Softtrade responder com a página de login
+func (g *Gerenciador) Verificar() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	expirada, err := g.sessaoExpirada()
+	if err != nil || !expirada {
+		return err
+	}
+	return g.reconectar()
+}
+
+// sessaoExpirada recarrega a aba e informa se o Softtrade passou a exibir o formulário de
+// login. No JSF cada GET cria uma view nova e pode descartar as antigas; buscar a página em
+// segundo plano deixaria a aba com um ViewState recusado, enquanto o recarregamento faz a
+// aba usar a view recém-criada.
+func (g *Gerenciador) sessaoExpirada() (bool, error) {
+	ctx, cancelar := common.ComTempoLimite(g.auth.GetContext(), g.relogio, tempoLimiteVerificacao)
+	defer cancelar()
+
+	var expirada bool
+	err := common.Executar(ctx,
+		chromedp.Reload(),
+		chromedp.WaitReady("body"),
+		chromedp.Evaluate(fmt.Sprintf(`document.querySelector(%s) !== null && document.querySelector(%s) === null`,
+			selectors.JS(g.seletores.LoginUsuario), selectors.JS(g.seletores.Formulario)), &expirada),
+	)
+	if err != nil {
+		return false, fmt.Errorf("falha ao verificar a sessão: %w", err)
+	}
+	return expirada, nil
+}
+
+// reconectar refaz o login com as credenciais da sessão; exige g.mu
+func (g *Gerenciador) reconectar() error {
+	fmt.Println("\n🔄 Sessão do Softtrade expirada; refazendo o login")
+	if err := g.auth.Login(g.creds); err != nil {
+		return fmt.Errorf("falha ao refazer o login: %w", err)
+	}
+	return nil
+}
+
+// perdeuSessao indica se o erro de uma chamada decorre da sessão encerrada: o módulo de
+// ponto o classificou como "sessao", o JSF respondeu ViewExpired ou o Softtrade voltou à
+// página de login
+func (g *Gerenciador) perdeuSessao(err error) bool {
+	var erroPonto *clockin.ErroPonto
+	if errors.As(err, &erroPonto) && erroPonto.Tipo == "sessao" {
+		return true
+	}
+	if strings.Contains(err.Error(), "ViewExpired") {
+		return true
+	}
+	expirada, errVerificacao := g.sessaoExpirada()
+	return errVerificacao == nil && expirada
+}
+
+// chamar executa a chamada do módulo de ponto e, se ela falhar com a sessão perdida,
+// refaz o login e a repete uma vez
+func chamar[T any](g *Gerenciador, chamada func() (T, error)) (T, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	resultado, err := chamada()
+	if err == nil || !g.perdeuSessao(err) {
+		return resultado, err
+	}
+	if errLogin := g.reconectar(); errLogin != nil {
+		return resultado, errors.Join(err, errLogin)
+	}
+	return chamada()
+}
+
+func (g *Gerenciador) ObterLocalizacaoAtual() (string, error) {
+	return chamar(g, g.ponto.ObterLocalizacaoAtual)
+}
+
+func (g *Gerenciador) ObterLocalizacoesDisponiveis() ([]clockin.Localizacao, error) {
+	return chamar(g, g.ponto.ObterLocalizacoesDisponiveis)
+}
+
+func (g *Gerenciador) SelecionarLocalizacao(localizacao clockin.Localizacao) error {
+	_, err := chamar(g, func() (struct{}, error) {
+		return struct{}{}, g.ponto.SelecionarLocalizacao(localizacao)
+	})
+	return err
+}
+
+func (g *Gerenciador) ObterOperacoesDisponiveis() ([]clockin.TipoOperacao, error) {
+	return chamar(g, g.ponto.ObterOperacoesDisponiveis)
+}
+
+// ExecutarOperacao não repete a marcação às cegas: a chamada que falhou pode ter chegado ao
+// Softtrade antes de a sessão cair. Após o novo login, só marca de novo se a operação
+// continuar disponível.
+func (g *Gerenciador) ExecutarOperacao(operacao clockin.TipoOperacao) (*clockin.ResultadoOperacao, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	resultado, err := g.ponto.ExecutarOperacao(operacao)
+	if err == nil || !g.perdeuSessao(err) {
+		return resultado, err
+	}
+	if errLogin := g.reconectar(); errLogin != nil {
+		return resultado, errors.Join(err, errLogin)
+	}
+
+	disponiveis, errOperacoes := g.ponto.ObterOperacoesDisponiveis()
+	if errOperacoes != nil {
+		return resultado, errors.Join(err, errOperacoes)
+	}
+	if !slices.Contains(disponiveis, operacao) {
+		return nil, &clockin.ErroPonto{
+			Operacao: operacao,
+			Tipo:     "confirmacao",
+			Mensagem: "a operação não é mais oferecida após o novo login; a tentativa anterior pode ter registrado a marcação",
+			Causa:    err,
+		}
+	}
+	return g.ponto.ExecutarOperacao(operacao)
+}
+
+// ObterMarcacoes repassa o histórico do módulo de ponto, quando ele implementa clockin.Historico
+func (g *Gerenciador) ObterMarcacoes() ([]clockin.Marcacao, error) {
+	historico, ok := g.ponto.(clockin.Historico)
+	if !ok {
+		return nil, &clockin.ErroPonto{Tipo: "historico", Mensagem: "o provedor de ponto não lista as marcações do dia"}
+	}
+	return chamar(g, historico.ObterMarcacoes)
+}
+
+// Close encerra as verificações periódicas e o módulo de ponto
+func (g *Gerenciador) Close() {
+	g.parar()
+	g.ponto.Close()
+}
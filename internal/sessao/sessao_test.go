@@ -0,0 +1,102 @@
+package sessao
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/auth"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/softtradefake"
+)
+
+// exigirChromium pula o teste quando não há Chromium instalado ou com -short
+func exigirChromium(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("teste com navegador ignorado em -short")
+	}
+	for _, nome := range []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless-shell", "headless_shell"} {
+		if _, err := exec.LookPath(nome); err == nil {
+			return
+		}
+	}
+	t.Skip("Chromium não instalado")
+}
+
+// novoGerenciadorFake inicia o Softtrade falso, faz o login em uma sessão headless e
+// retorna o gerenciador de sessão sobre o módulo de ponto do navegador
+func novoGerenciadorFake(t *testing.T) (*softtradefake.Servidor, *Gerenciador) {
+	t.Helper()
+	exigirChromium(t)
+
+	cenario := softtradefake.CenarioPadrao()
+	cenario.LocalizacaoInicial = "Home Office"
+	servidor := softtradefake.Iniciar(cenario)
+	t.Cleanup(servidor.Close)
+
+	navegador := common.NovoGerenciadorNavegador(true)
+	t.Cleanup(navegador.Close)
+
+	creds := auth.Credentials{Username: cenario.Usuario, Password: cenario.Senha}
+	sessao := auth.NewAuthSession(auth.Config{Navegador: navegador, URLBase: servidor.URL()})
+	t.Cleanup(sessao.Close)
+	if err := sessao.Login(creds); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	ponto := clockin.NewGerenciadorPonto(sessao.GetContext(), clockin.Config{
+		Intervalo: clockin.ConfigIntervalo{Politica: clockin.IntervaloSempreSim},
+	})
+	g := Novo(sessao, ponto, creds, Config{})
+	t.Cleanup(g.Close)
+	return servidor, g
+}
+
+func TestVerificarMantemViewDaAba(t *testing.T) {
+	servidor, g := novoGerenciadorFake(t)
+
+	// Cada verificação cria uma view nova no servidor; a aba precisa passar a usá-la
+	for range 3 {
+		if err := g.Verificar(); err != nil {
+			t.Fatalf("Verificar: %v", err)
+		}
+	}
+
+	resultado, err := g.ExecutarOperacao(clockin.Entrada)
+	if err != nil {
+		t.Fatalf("ExecutarOperacao: %v", err)
+	}
+	if resultado.Tentativas != 1 || servidor.Logins() != 1 {
+		t.Fatalf("marcação após as verificações: %d tentativa(s), %d login(s); esperado 1 e 1", resultado.Tentativas, servidor.Logins())
+	}
+}
+
+func TestVerificarSessaoExpirada(t *testing.T) {
+	servidor, g := novoGerenciadorFake(t)
+
+	servidor.ExpirarSessoes()
+	if err := g.Verificar(); err != nil {
+		t.Fatalf("Verificar: %v", err)
+	}
+	if servidor.Logins() != 2 {
+		t.Fatalf("logins = %d, esperado novo login após a expiração", servidor.Logins())
+	}
+}
+
+func TestExecutarOperacaoSessaoExpirada(t *testing.T) {
+	servidor, g := novoGerenciadorFake(t)
+
+	servidor.ExpirarSessoes()
+	if _, err := g.ExecutarOperacao(clockin.Entrada); err != nil {
+		t.Fatalf("ExecutarOperacao: %v", err)
+	}
+
+	if servidor.Logins() != 2 {
+		t.Fatalf("logins = %d, esperado exatamente um novo login", servidor.Logins())
+	}
+	marcacoes := servidor.Marcacoes()
+	if len(marcacoes) != 1 || marcacoes[0].Operacao != softtradefake.Entrada {
+		t.Fatalf("marcações = %+v, esperada uma Entrada", marcacoes)
+	}
+}
@@ -0,0 +1,407 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+)
+
+// Backends aceitos em Configuracao.Backend
+const (
+	BackendNavegador = "navegador"
+	BackendAPI       = "api"
+)
+
+const (
+	// EnvToken é a variável de ambiente com o token da API do Slack (xoxp ou xoxc)
+	EnvToken = "SLACK_TOKEN"
+
+	// ChaveToken identifica o token da API no armazenamento de segredos
+	ChaveToken = "slack_token"
+
+	// esperaMaximaLimite limita a espera pedida pelo Slack no Retry-After
+	esperaMaximaLimite = 30 * time.Second
+)
+
+// OpDefinirStatus nomeia a alteração de status pela API nas retentativas e nos logs
+const OpDefinirStatus = "definir_status"
+
+// tokenPagina encontra o token xoxc que o Slack embute na página do workspace
+var tokenPagina = regexp.MustCompile(`"api_token":"(xoxc-[^"]+)"`)
+
+// ErroAPI é uma resposta de erro da Web API do Slack
+type ErroAPI struct {
+	Metodo string
+	// Codigo é o campo "error" da resposta ou "http_<status>" quando não houve corpo JSON
+	Codigo string
+	Status int
+}
+
+func (e *ErroAPI) Error() string {
+	return fmt.Sprintf("slack %s: %s", e.Metodo, e.Codigo)
+}
+
+// Retentavel repete as chamadas limitadas por taxa e as falhas do servidor do Slack
+func (e *ErroAPI) Retentavel() bool {
+	return e.Codigo == "ratelimited" || e.Status >= 500
+}
+
+// ClienteAPI implementa OperacoesSlack pela Web API do Slack, sem navegador. Autentica com
+// um token de usuário (xoxp) ou com o token xoxc da sessão web acompanhado do cookie d.
+type ClienteAPI struct {
+	ctx          context.Context
+	cancelar     context.CancelFunc
+	http         *http.Client
+	urlBase      string
+	tokenConfig  string
+	token        string
+	cookie       string
+	retentativas map[string]common.PoliticaRetentativa
+	relogio      common.Relogio
+	segredos     ArmazemSegredos
+	destinos     Destinos
+	diretorio    string
+
+	// conversas guarda o ID resolvido de cada destino, persistido em arquivoConversas;
+	// canais e usuarios indexam os IDs pelo nome e são carregados na primeira consulta
+	mu        sync.Mutex
+	conversas map[string]conversaResolvida
+	canais    map[string]string
+	usuarios  map[string]string
+}
+
+// NovoClienteAPI cria o cliente da API; as credenciais são obtidas em CarregarCookies
+func NovoClienteAPI(ctx context.Context, config Configuracao) *ClienteAPI {
+	ctx, cancelar := context.WithCancel(ctx)
+
+	urlBase := strings.TrimSuffix(config.URLBase, "/")
+	if urlBase == "" {
+		urlBase = slackBaseURL
+	}
+
+	return &ClienteAPI{
+		ctx:          ctx,
+		cancelar:     cancelar,
+		http:         &http.Client{},
+		urlBase:      urlBase,
+		tokenConfig:  config.Token,
+		retentativas: config.Retentativas,
+		relogio:      common.RelogioOuSistema(config.Relogio),
+		segredos:     config.Segredos,
+		destinos:     config.Destinos,
+		diretorio:    config.DiretorioConfig,
+		conversas:    carregarConversas(config.DiretorioConfig, urlBase),
+	}
+}
+
+// novoModuloAPI cria o cliente da API e confere as credenciais com auth.test
+func novoModuloAPI(ctx context.Context, config Configuracao) (OperacoesSlack, error) {
+	cliente := NovoClienteAPI(ctx, config)
+	if err := cliente.CarregarCookies(config.DiretorioConfig); err != nil {
+		cliente.Close()
+		return nil, err
+	}
+	if err := cliente.ValidarSessao(); err != nil {
+		cliente.Close()
+		return nil, fmt.Errorf("falha ao validar token do Slack: %w", err)
+	}
+	return cliente, nil
+}
+
+func (c *ClienteAPI) comTempoLimite() (context.Context, context.CancelFunc) {
+	return common.ComTempoLimite(c.ctx, c.relogio, tempoLimiteOperacao)
+}
+
+// CarregarCookies obtém as credenciais da API: o token configurado, a variável SLACK_TOKEN,
+// o token salvo no armazenamento de segredos ou, por fim, o token xoxc lido da página do
+// workspace com o cookie d salvo pelo login no navegador
+func (c *ClienteAPI) CarregarCookies(diretorio string) error {
+	token := c.tokenConfig
+	if token == "" {
+		token = os.Getenv(EnvToken)
+	}
+	if token == "" && c.segredos != nil {
+		dados, err := c.segredos.LoadSecret(ChaveToken)
+		if err == nil {
+			token = strings.TrimSpace(string(dados))
+		}
+	}
+	if token != "" {
+		c.token, c.cookie = token, ""
+		return nil
+	}
+
+	dados, err := lerCookies(c.segredos, diretorio)
+	if err != nil {
+		return fmt.Errorf("nenhum token do Slack configurado (%s ou \"credenciais slack-token\") e cookies do login indisponíveis: %w", EnvToken, err)
+	}
+	var cookies []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(dados, &cookies); err != nil {
+		return fmt.Errorf("erro ao deserializar cookies: %w", err)
+	}
+	for _, cookie := range cookies {
+		if cookie.Name == "d" {
+			c.cookie = cookie.Value
+		}
+	}
+	if c.cookie == "" {
+		return fmt.Errorf("cookie d não encontrado nos cookies salvos do Slack")
+	}
+
+	ctx, cancelar := c.comTempoLimite()
+	defer cancelar()
+	err = retentar(ctx, c.relogio, c.retentativas, OpCarregarCookies, func() error {
+		token, err := c.tokenDaPagina(ctx)
+		if err != nil {
+			return err
+		}
+		c.token = token
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao obter o token da sessão web do Slack: %w", err)
+	}
+	return nil
+}
+
+// tokenDaPagina abre a página do workspace com o cookie d e extrai o token xoxc
+func (c *ClienteAPI) tokenDaPagina(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.urlBase+"/", nil)
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.Header.Set("Cookie", "d="+c.cookie)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("erro ao abrir o workspace: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return "", &ErroAPI{Metodo: "workspace", Codigo: "http_" + strconv.Itoa(resp.StatusCode), Status: resp.StatusCode}
+	}
+	pagina, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler a página do workspace: %w", err)
+	}
+	encontrado := tokenPagina.FindSubmatch(pagina)
+	if encontrado == nil {
+		return "", &ErroAPI{Metodo: "workspace", Codigo: "token xoxc não encontrado; sessão expirada?", Status: resp.StatusCode}
+	}
+	return string(encontrado[1]), nil
+}
+
+// chamar executa o método da API com os parâmetros como formulário e decodifica a
+// resposta em resultado, quando não nil
+func (c *ClienteAPI) chamar(ctx context.Context, metodo string, parametros url.Values, resultado any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.urlBase+"/api/"+metodo, strings.NewReader(parametros.Encode()))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	if c.cookie != "" {
+		req.Header.Set("Cookie", "d="+c.cookie)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro ao chamar %s: %w", metodo, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// Respeita a espera pedida antes de devolver o erro às retentativas
+		if segundos, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			espera := min(time.Duration(segundos)*time.Second, esperaMaximaLimite)
+			if err := common.Dormir(ctx, c.relogio, espera); err != nil {
+				return err
+			}
+		}
+		return &ErroAPI{Metodo: metodo, Codigo: "ratelimited", Status: resp.StatusCode}
+	}
+
+	corpo, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("erro ao ler resposta de %s: %w", metodo, err)
+	}
+	var envelope struct {
+		Ok    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(corpo, &envelope); err != nil {
+		return &ErroAPI{Metodo: metodo, Codigo: "http_" + strconv.Itoa(resp.StatusCode), Status: resp.StatusCode}
+	}
+	if !envelope.Ok {
+		return &ErroAPI{Metodo: metodo, Codigo: envelope.Error, Status: resp.StatusCode}
+	}
+	if resultado == nil {
+		return nil
+	}
+	if err := json.Unmarshal(corpo, resultado); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta de %s: %w", metodo, err)
+	}
+	return nil
+}
+
+// ValidarSessao confere o token com auth.test
+func (c *ClienteAPI) ValidarSessao() error {
+	if c.token == "" {
+		return fmt.Errorf("token do Slack não carregado")
+	}
+	ctx, cancelar := c.comTempoLimite()
+	defer cancelar()
+	return retentar(ctx, c.relogio, c.retentativas, OpValidarSessao, func() error {
+		return c.chamar(ctx, "auth.test", url.Values{}, nil)
+	})
+}
+
+// perfil é o trecho de users.profile usado pelo batedor
+type perfil struct {
+	StatusText  string `json:"status_text"`
+	StatusEmoji string `json:"status_emoji"`
+}
+
+func (p perfil) status() *Status {
+	if p.StatusText == "" && p.StatusEmoji == "" {
+		return nil
+	}
+	return &Status{Emoji: p.StatusEmoji, Mensagem: p.StatusText}
+}
+
+// ObterStatusAtual lê o status com users.profile.get; nil indica nenhum status definido
+func (c *ClienteAPI) ObterStatusAtual() (*Status, error) {
+	ctx, cancelar := c.comTempoLimite()
+	defer cancelar()
+
+	var resposta struct {
+		Profile perfil `json:"profile"`
+	}
+	err := retentar(ctx, c.relogio, c.retentativas, OpObterStatus, func() error {
+		return c.chamar(ctx, "users.profile.get", url.Values{}, &resposta)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter status: %w", err)
+	}
+	return resposta.Profile.status(), nil
+}
+
+// definirPerfil grava o status com users.profile.set e confere o perfil devolvido
+func (c *ClienteAPI) definirPerfil(novo perfil) error {
+	ctx, cancelar := c.comTempoLimite()
+	defer cancelar()
+
+	dados, err := json.Marshal(map[string]any{
+		"status_text":       novo.StatusText,
+		"status_emoji":      novo.StatusEmoji,
+		"status_expiration": 0,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar status: %w", err)
+	}
+
+	var resposta struct {
+		Profile perfil `json:"profile"`
+	}
+	err = retentar(ctx, c.relogio, c.retentativas, OpDefinirStatus, func() error {
+		return c.chamar(ctx, "users.profile.set", url.Values{"profile": {string(dados)}}, &resposta)
+	})
+	if err != nil {
+		return err
+	}
+	if resposta.Profile.StatusText != novo.StatusText {
+		return fmt.Errorf("status não foi alterado corretamente")
+	}
+	return nil
+}
+
+// DefinirStatus define o status do usuário no Slack
+func (c *ClienteAPI) DefinirStatus(status Status) error {
+	if err := c.definirPerfil(perfil{StatusText: status.Mensagem, StatusEmoji: status.Emoji}); err != nil {
+		return fmt.Errorf("erro ao definir status: %w", err)
+	}
+	return nil
+}
+
+// LimparStatus limpa o status do usuário no Slack
+func (c *ClienteAPI) LimparStatus() error {
+	if err := c.definirPerfil(perfil{}); err != nil {
+		return fmt.Errorf("erro ao limpar status: %w", err)
+	}
+	return nil
+}
+
+// EnviarMensagem posta a mensagem com chat.postMessage em cada destino configurado para o
+// tipo ou o texto da mensagem
+func (c *ClienteAPI) EnviarMensagem(tipoMensagem, msg string) ([]ResultadoEnvio, error) {
+	if msg == "" {
+		return nil, fmt.Errorf("mensagem vazia")
+	}
+
+	ctx, cancelar := c.comTempoLimite()
+	defer cancelar()
+
+	var resultados []ResultadoEnvio
+	for _, destino := range c.destinos.Para(tipoMensagem, msg) {
+		resultado := ResultadoEnvio{Destino: destino}
+		resultado.Canal, resultado.Erro = c.resolverDestino(ctx, destino)
+		if resultado.Erro == nil {
+			resultado.Erro = c.postar(ctx, resultado.Canal, msg)
+		}
+
+		// Um ID salvo pode ter deixado de existir: o nome é resolvido de novo uma única vez
+		var erroAPI *ErroAPI
+		if errors.As(resultado.Erro, &erroAPI) && erroAPI.Codigo == "channel_not_found" && !idConversa.MatchString(strings.TrimSpace(destino)) {
+			c.esquecerDestino(destino)
+			resultado.Canal, resultado.Erro = c.resolverDestino(ctx, destino)
+			if resultado.Erro == nil {
+				resultado.Erro = c.postar(ctx, resultado.Canal, msg)
+			}
+		}
+		resultados = append(resultados, resultado)
+	}
+	return resultados, erroEnvios(resultados)
+}
+
+// postar envia a mensagem à conversa com chat.postMessage
+func (c *ClienteAPI) postar(ctx context.Context, canal, msg string) error {
+	return retentar(ctx, c.relogio, c.retentativas, OpEnviarMensagem, func() error {
+		return c.chamar(ctx, "chat.postMessage", url.Values{"channel": {canal}, "text": {msg}}, nil)
+	})
+}
+
+// PrepararMensagem prepara uma mensagem baseada no tipo
+func (c *ClienteAPI) PrepararMensagem(tipoMensagem string) (bool, string, error) {
+	return prepararMensagem(tipoMensagem)
+}
+
+// SalvarCookies não tem efeito: o backend da API não cria sessão própria
+func (c *ClienteAPI) SalvarCookies(diretorio string) error {
+	return nil
+}
+
+// Autenticar não está disponível sem navegador
+func (c *ClienteAPI) Autenticar() error {
+	return errors.New("o backend api não faz login interativo; entre no Slack com o backend navegador ou salve um token com \"credenciais slack-token\"")
+}
+
+func (c *ClienteAPI) Close() {
+	c.cancelar()
+}
@@ -0,0 +1,259 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slackfake"
+)
+
+// semEspera repete as chamadas da API sem aguardar entre as tentativas
+var semEspera = common.PoliticaRetentativa{EsperaInicial: time.Millisecond, EsperaMaxima: time.Millisecond}
+
+// novoClienteFake inicia o Slack falso e cria o cliente da API apontado para ele
+func novoClienteFake(t *testing.T, cenario slackfake.Cenario, config Configuracao) (*slackfake.Servidor, *ClienteAPI) {
+	t.Helper()
+	t.Setenv(EnvToken, "")
+
+	servidor := slackfake.Iniciar(cenario)
+	t.Cleanup(servidor.Close)
+	return servidor, novoClienteNoServidor(t, servidor, config)
+}
+
+// novoClienteNoServidor cria outro cliente da API apontado para um Slack falso já iniciado
+func novoClienteNoServidor(t *testing.T, servidor *slackfake.Servidor, config Configuracao) *ClienteAPI {
+	t.Helper()
+
+	config.URLBase = servidor.URL()
+	if config.DiretorioConfig == "" {
+		config.DiretorioConfig = t.TempDir()
+	}
+	config.Retentativas = map[string]common.PoliticaRetentativa{
+		OpValidarSessao: semEspera, OpObterStatus: semEspera, OpDefinirStatus: semEspera,
+		OpEnviarMensagem: semEspera, OpResolverDestino: semEspera, OpNavegarDM: semEspera, OpCarregarCookies: semEspera,
+	}
+	cliente := NovoClienteAPI(context.Background(), config)
+	t.Cleanup(cliente.Close)
+	return cliente
+}
+
+// codigoErro retorna o código do ErroAPI em err, vazio se não houver
+func codigoErro(err error) string {
+	var erroAPI *ErroAPI
+	if errors.As(err, &erroAPI) {
+		return erroAPI.Codigo
+	}
+	return ""
+}
+
+func TestClienteAPIStatus(t *testing.T) {
+	servidor, cliente := novoClienteFake(t, slackfake.CenarioPadrao(), Configuracao{Token: "xoxp-fake"})
+	if err := cliente.CarregarCookies(""); err != nil {
+		t.Fatalf("CarregarCookies: %v", err)
+	}
+
+	if status, err := cliente.ObterStatusAtual(); err != nil || status != nil {
+		t.Fatalf("ObterStatusAtual inicial = %+v, %v", status, err)
+	}
+
+	novo := Status{Emoji: ":hamburger:", Mensagem: "Almoçando"}
+	if err := cliente.DefinirStatus(novo); err != nil {
+		t.Fatalf("DefinirStatus: %v", err)
+	}
+	if status, err := cliente.ObterStatusAtual(); err != nil || status == nil || *status != novo {
+		t.Fatalf("ObterStatusAtual = %+v, %v, esperado %+v", status, err, novo)
+	}
+
+	if err := cliente.LimparStatus(); err != nil {
+		t.Fatalf("LimparStatus: %v", err)
+	}
+	if status := servidor.Status(); status != (slackfake.Status{}) {
+		t.Fatalf("status após limpar = %+v", status)
+	}
+}
+
+func TestClienteAPIEnviarMensagem(t *testing.T) {
+	servidor, cliente := novoClienteFake(t, slackfake.CenarioPadrao(), Configuracao{
+		Token:    "xoxp-fake",
+		Destinos: Destinos{PorTipo: map[string][]string{TipoEntrada: {"#equipe", "@gestor"}}},
+	})
+	if err := cliente.CarregarCookies(""); err != nil {
+		t.Fatalf("CarregarCookies: %v", err)
+	}
+
+	resultados, err := cliente.EnviarMensagem(TipoEntrada, "bom dia")
+	if err != nil {
+		t.Fatalf("EnviarMensagem: %v", err)
+	}
+	if len(resultados) != 2 || resultados[0].Canal != "C0000000002" || resultados[1].Canal != "D0000000002" {
+		t.Fatalf("resultados = %+v", resultados)
+	}
+
+	if _, err := cliente.EnviarMensagem(TipoSaida, "até amanhã"); err != nil {
+		t.Fatalf("EnviarMensagem ao destino padrão: %v", err)
+	}
+
+	mensagens := servidor.Mensagens()
+	canais := make([]string, len(mensagens))
+	for i, m := range mensagens {
+		canais[i] = m.Canal
+	}
+	esperados := []string{"C0000000002", "D0000000002", canalMensagens}
+	if len(canais) != len(esperados) || canais[0] != esperados[0] || canais[1] != esperados[1] || canais[2] != esperados[2] {
+		t.Fatalf("mensagens em %v, esperadas em %v", canais, esperados)
+	}
+}
+
+func TestClienteAPIDestinoInexistente(t *testing.T) {
+	_, cliente := novoClienteFake(t, slackfake.CenarioPadrao(), Configuracao{
+		Token:    "xoxp-fake",
+		Destinos: Destinos{PorTipo: map[string][]string{TipoEntrada: {"#equipe", "C0000000099"}}},
+	})
+	if err := cliente.CarregarCookies(""); err != nil {
+		t.Fatalf("CarregarCookies: %v", err)
+	}
+
+	resultados, err := cliente.EnviarMensagem(TipoEntrada, "bom dia")
+	if err == nil || len(resultados) != 2 || resultados[0].Erro != nil || codigoErro(resultados[1].Erro) != "channel_not_found" {
+		t.Fatalf("EnviarMensagem = %+v, %v", resultados, err)
+	}
+}
+
+func TestClienteAPICacheDeDestinos(t *testing.T) {
+	diretorio := t.TempDir()
+	relogio := common.NovoRelogioSimulado(time.Date(2026, 10, 19, 9, 0, 0, 0, time.Local), true)
+	config := Configuracao{
+		Token:           "xoxp-fake",
+		Relogio:         relogio,
+		DiretorioConfig: diretorio,
+		Destinos:        Destinos{PorTipo: map[string][]string{TipoEntrada: {"#equipe"}}},
+	}
+	enviar := func(cliente *ClienteAPI) {
+		t.Helper()
+		if err := cliente.CarregarCookies(""); err != nil {
+			t.Fatalf("CarregarCookies: %v", err)
+		}
+		resultados, err := cliente.EnviarMensagem(TipoEntrada, "bom dia")
+		if err != nil || resultados[0].Canal != "C0000000002" {
+			t.Fatalf("EnviarMensagem = %+v, %v", resultados, err)
+		}
+	}
+
+	servidor, cliente := novoClienteFake(t, slackfake.CenarioPadrao(), config)
+	enviar(cliente)
+	if _, err := os.Stat(filepath.Join(diretorio, arquivoConversas)); err != nil {
+		t.Fatalf("destinos resolvidos não foram salvos: %v", err)
+	}
+
+	// Uma nova execução reutiliza o ID salvo sem listar os canais
+	enviar(novoClienteNoServidor(t, servidor, config))
+	if n := servidor.Chamadas("conversations.list"); n != 1 {
+		t.Fatalf("conversations.list chamado %d vezes, esperado 1", n)
+	}
+
+	// Vencida a validade, o nome é consultado de novo
+	relogio.Avancar(validadeConversa)
+	enviar(novoClienteNoServidor(t, servidor, config))
+	if n := servidor.Chamadas("conversations.list"); n != 2 {
+		t.Fatalf("conversations.list chamado %d vezes após a validade, esperado 2", n)
+	}
+}
+
+func TestClienteAPIDestinoSalvoRemovido(t *testing.T) {
+	t.Setenv(EnvToken, "")
+	servidor := slackfake.Iniciar(slackfake.CenarioPadrao())
+	t.Cleanup(servidor.Close)
+
+	// O cache aponta #equipe para um canal que não existe mais no workspace
+	diretorio := t.TempDir()
+	cache := `{"workspace":"` + servidor.URL() + `","conversas":{"#equipe":{"canal":"C0000000099","resolvido":"` + time.Now().Format(time.RFC3339) + `"}}}`
+	if err := os.WriteFile(filepath.Join(diretorio, arquivoConversas), []byte(cache), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cliente := novoClienteNoServidor(t, servidor, Configuracao{
+		Token:           "xoxp-fake",
+		DiretorioConfig: diretorio,
+		Destinos:        Destinos{PorTipo: map[string][]string{TipoEntrada: {"#equipe"}}},
+	})
+	if err := cliente.CarregarCookies(""); err != nil {
+		t.Fatalf("CarregarCookies: %v", err)
+	}
+
+	resultados, err := cliente.EnviarMensagem(TipoEntrada, "bom dia")
+	if err != nil || resultados[0].Canal != "C0000000002" {
+		t.Fatalf("EnviarMensagem = %+v, %v", resultados, err)
+	}
+	if mensagens := servidor.Mensagens(); len(mensagens) != 1 || mensagens[0].Canal != "C0000000002" {
+		t.Fatalf("mensagens = %+v", mensagens)
+	}
+	if canal, ok := novoClienteNoServidor(t, servidor, Configuracao{DiretorioConfig: diretorio}).conversaEmCache("#equipe"); !ok || canal != "C0000000002" {
+		t.Fatalf("cache de #equipe = %q, %v", canal, ok)
+	}
+}
+
+func TestClienteAPILimiteDeTaxa(t *testing.T) {
+	cenario := slackfake.CenarioPadrao()
+	cenario.LimitarTaxa = 1
+	cenario.EsperaLimite = time.Second
+	_, cliente := novoClienteFake(t, cenario, Configuracao{Token: "xoxp-fake"})
+	if err := cliente.CarregarCookies(""); err != nil {
+		t.Fatalf("CarregarCookies: %v", err)
+	}
+
+	inicio := time.Now()
+	if err := cliente.ValidarSessao(); err != nil {
+		t.Fatalf("ValidarSessao após 429: %v", err)
+	}
+	if decorrido := time.Since(inicio); decorrido < time.Second {
+		t.Fatalf("nova tentativa após %s, antes do Retry-After", decorrido)
+	}
+}
+
+func TestClienteAPITokenDaSessao(t *testing.T) {
+	diretorio := t.TempDir()
+	cookies := `[{"name":"b","value":"outro","domain":".slack.com"},{"name":"d","value":"xoxd-fake","domain":".slack.com"}]`
+	if err := os.WriteFile(obterCaminhoCookies(diretorio), []byte(cookies), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, cliente := novoClienteFake(t, slackfake.CenarioPadrao(), Configuracao{DiretorioConfig: diretorio})
+	if err := cliente.CarregarCookies(diretorio); err != nil {
+		t.Fatalf("CarregarCookies: %v", err)
+	}
+	if cliente.token != "xoxc-fake" || cliente.cookie != "xoxd-fake" {
+		t.Fatalf("token %q e cookie %q, esperados xoxc-fake e xoxd-fake", cliente.token, cliente.cookie)
+	}
+	if err := cliente.ValidarSessao(); err != nil {
+		t.Fatalf("ValidarSessao com xoxc e cookie d: %v", err)
+	}
+}
+
+func TestClienteAPICookieExpirado(t *testing.T) {
+	diretorio := t.TempDir()
+	if err := os.WriteFile(obterCaminhoCookies(diretorio), []byte(`[{"name":"d","value":"xoxd-velho"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, cliente := novoClienteFake(t, slackfake.CenarioPadrao(), Configuracao{DiretorioConfig: diretorio})
+	if err := cliente.CarregarCookies(diretorio); err == nil {
+		t.Fatal("CarregarCookies com cookie d expirado não falhou")
+	}
+}
+
+func TestClienteAPIInvalidAuth(t *testing.T) {
+	_, cliente := novoClienteFake(t, slackfake.CenarioPadrao(), Configuracao{Token: "xoxp-revogado"})
+	if err := cliente.CarregarCookies(""); err != nil {
+		t.Fatalf("CarregarCookies: %v", err)
+	}
+
+	err := cliente.ValidarSessao()
+	if codigo := codigoErro(err); codigo != "invalid_auth" {
+		t.Fatalf("ValidarSessao = %v, esperado invalid_auth", err)
+	}
+}
@@ -0,0 +1,332 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Tipos de mensagem usados na escolha dos destinos
+const (
+	TipoEntrada  = "entrada"
+	TipoRefeicao = "refeicao"
+	TipoSaida    = "saida"
+	TipoLembrete = "lembrete"
+)
+
+// OpResolverDestino nomeia a consulta de canais e usuários nas retentativas e nos logs
+const OpResolverDestino = "resolver_destino"
+
+const (
+	// arquivoConversas guarda no diretório de configuração os IDs resolvidos dos destinos
+	arquivoConversas = "slack_conversas.json"
+
+	// validadeConversa é o tempo em que um ID resolvido é usado sem nova consulta à API
+	validadeConversa = 7 * 24 * time.Hour
+)
+
+// TiposMensagem lista os tipos aceitos em Destinos.PorTipo
+var TiposMensagem = []string{TipoEntrada, TipoRefeicao, TipoSaida, TipoLembrete}
+
+// idConversa reconhece IDs de canal (C, G), DM (D) e usuário (U, W)
+var idConversa = regexp.MustCompile(`^[CGDUW][A-Z0-9]{8,}$`)
+
+// Destinos define as conversas que recebem as mensagens: IDs, "#canal" ou "@usuario" para a DM
+type Destinos struct {
+	// PorTipo associa um tipo de mensagem (TipoEntrada, ...) aos destinos
+	PorTipo map[string][]string `json:"por_tipo"`
+	// PorMensagem associa o texto de uma mensagem ("já volto") aos destinos e tem
+	// prioridade sobre PorTipo
+	PorMensagem map[string][]string `json:"por_mensagem"`
+}
+
+// Validar recusa tipos de mensagem desconhecidos em PorTipo
+func (d Destinos) Validar() error {
+	for tipo := range d.PorTipo {
+		if !slices.Contains(TiposMensagem, tipo) {
+			return fmt.Errorf("tipo de mensagem desconhecido %q em destinos.por_tipo: use %s", tipo, strings.Join(TiposMensagem, ", "))
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON valida os tipos ao carregar a configuração e recusa chaves fora de
+// por_tipo e por_mensagem, como as do formato antigo, em que tipos e textos ficavam juntos
+func (d *Destinos) UnmarshalJSON(dados []byte) error {
+	var chaves map[string]json.RawMessage
+	if err := json.Unmarshal(dados, &chaves); err != nil {
+		return err
+	}
+	for chave := range chaves {
+		if chave != "por_tipo" && chave != "por_mensagem" {
+			return fmt.Errorf("chave desconhecida %q em destinos: separe os destinos em por_tipo e por_mensagem", chave)
+		}
+	}
+
+	type destinos Destinos
+	var lidos destinos
+	if err := json.Unmarshal(dados, &lidos); err != nil {
+		return err
+	}
+	if err := Destinos(lidos).Validar(); err != nil {
+		return err
+	}
+	*d = Destinos(lidos)
+	return nil
+}
+
+// Para retorna os destinos da mensagem: os configurados para o texto, senão os do tipo,
+// senão a conversa padrão do batedor
+func (d Destinos) Para(tipoMensagem, msg string) []string {
+	for texto, destinos := range d.PorMensagem {
+		if len(destinos) > 0 && strings.EqualFold(strings.TrimSpace(texto), strings.TrimSpace(msg)) {
+			return destinos
+		}
+	}
+	if destinos := d.PorTipo[tipoMensagem]; len(destinos) > 0 {
+		return destinos
+	}
+	return []string{canalMensagens}
+}
+
+// ResultadoEnvio é o resultado do envio de uma mensagem a um destino
+type ResultadoEnvio struct {
+	// Destino é o destino como configurado
+	Destino string
+	// Canal é o ID da conversa resolvido; vazio se o destino não foi encontrado
+	Canal string
+	Erro  error
+}
+
+// erroEnvios resume as falhas dos envios; nil se todos os destinos receberam a mensagem
+func erroEnvios(resultados []ResultadoEnvio) error {
+	var falhas []error
+	for _, r := range resultados {
+		if r.Erro != nil {
+			falhas = append(falhas, fmt.Errorf("%s: %w", r.Destino, r.Erro))
+		}
+	}
+	if len(falhas) == 0 {
+		return nil
+	}
+	return fmt.Errorf("falha em %d de %d destinos: %w", len(falhas), len(resultados), errors.Join(falhas...))
+}
+
+// conversaResolvida é o ID resolvido de um destino e o momento da consulta
+type conversaResolvida struct {
+	Canal     string    `json:"canal"`
+	Resolvido time.Time `json:"resolvido"`
+}
+
+// cacheConversas é o conteúdo de arquivoConversas; Workspace impede reutilizar os IDs
+// resolvidos em outro workspace
+type cacheConversas struct {
+	Workspace string                       `json:"workspace"`
+	Conversas map[string]conversaResolvida `json:"conversas"`
+}
+
+// carregarConversas lê os destinos resolvidos em execuções anteriores; um arquivo ausente,
+// inválido ou de outro workspace resulta em cache vazio
+func carregarConversas(diretorio, workspace string) map[string]conversaResolvida {
+	conversas := make(map[string]conversaResolvida)
+	if diretorio == "" {
+		return conversas
+	}
+	dados, err := os.ReadFile(filepath.Join(diretorio, arquivoConversas))
+	if err != nil {
+		return conversas
+	}
+	var cache cacheConversas
+	if err := json.Unmarshal(dados, &cache); err != nil || cache.Workspace != workspace {
+		return conversas
+	}
+	for destino, conversa := range cache.Conversas {
+		conversas[destino] = conversa
+	}
+	return conversas
+}
+
+// salvarConversas grava o cache de destinos no diretório de configuração; deve ser chamada
+// com c.mu travado
+func (c *ClienteAPI) salvarConversas() error {
+	if c.diretorio == "" {
+		return nil
+	}
+	dados, err := json.MarshalIndent(cacheConversas{Workspace: c.urlBase, Conversas: c.conversas}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar destinos: %w", err)
+	}
+	if err := os.MkdirAll(c.diretorio, 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(c.diretorio, arquivoConversas), dados, 0600); err != nil {
+		return fmt.Errorf("erro ao salvar destinos: %w", err)
+	}
+	return nil
+}
+
+// conversaEmCache retorna o ID resolvido do destino enquanto estiver dentro da validade
+func (c *ClienteAPI) conversaEmCache(destino string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	conversa, ok := c.conversas[strings.TrimSpace(destino)]
+	if !ok || c.relogio.Agora().Sub(conversa.Resolvido) >= validadeConversa {
+		return "", false
+	}
+	return conversa.Canal, true
+}
+
+// resolverDestino converte o destino no ID da conversa. IDs de canal e DM são usados
+// diretamente; nomes e IDs de usuário são consultados na API e os IDs resolvidos ficam
+// salvos no diretório de configuração por validadeConversa.
+func (c *ClienteAPI) resolverDestino(ctx context.Context, destino string) (string, error) {
+	destino = strings.TrimSpace(destino)
+	if idConversa.MatchString(destino) && !strings.HasPrefix(destino, "U") && !strings.HasPrefix(destino, "W") {
+		return destino, nil
+	}
+	if canal, ok := c.conversaEmCache(destino); ok {
+		return canal, nil
+	}
+
+	var canal string
+	var err error
+	switch {
+	case idConversa.MatchString(destino):
+		canal = destino
+	case strings.HasPrefix(destino, "@"):
+		canal, err = c.buscarNome(ctx, &c.usuarios, "users.list", strings.TrimPrefix(destino, "@"))
+	default:
+		canal, err = c.buscarNome(ctx, &c.canais, "conversations.list", strings.TrimPrefix(destino, "#"))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Mensagens a usuários vão para a DM aberta com conversations.open
+	if strings.HasPrefix(canal, "U") || strings.HasPrefix(canal, "W") {
+		var resposta struct {
+			Channel struct {
+				ID string `json:"id"`
+			} `json:"channel"`
+		}
+		err := retentar(ctx, c.relogio, c.retentativas, OpNavegarDM, func() error {
+			return c.chamar(ctx, "conversations.open", url.Values{"users": {canal}}, &resposta)
+		})
+		if err != nil {
+			return "", fmt.Errorf("erro ao abrir conversa com %s: %w", destino, err)
+		}
+		canal = resposta.Channel.ID
+	}
+
+	c.mu.Lock()
+	c.conversas[destino] = conversaResolvida{Canal: canal, Resolvido: c.relogio.Agora()}
+	err = c.salvarConversas()
+	c.mu.Unlock()
+	if err != nil {
+		fmt.Printf("\n⚠️  Aviso: não foi possível salvar os destinos do Slack resolvidos: %v\n", err)
+	}
+	return canal, nil
+}
+
+// esquecerDestino descarta o ID salvo do destino e os índices de nomes, para que a próxima
+// resolução consulte a API novamente
+func (c *ClienteAPI) esquecerDestino(destino string) {
+	c.mu.Lock()
+	delete(c.conversas, strings.TrimSpace(destino))
+	c.canais, c.usuarios = nil, nil
+	err := c.salvarConversas()
+	c.mu.Unlock()
+	if err != nil {
+		fmt.Printf("\n⚠️  Aviso: não foi possível salvar os destinos do Slack resolvidos: %v\n", err)
+	}
+}
+
+// buscarNome procura o nome no índice, carregando-o com o método de listagem na primeira
+// consulta
+func (c *ClienteAPI) buscarNome(ctx context.Context, indice *map[string]string, metodo, nome string) (string, error) {
+	c.mu.Lock()
+	carregado := *indice != nil
+	c.mu.Unlock()
+
+	if !carregado {
+		novo, err := c.listar(ctx, metodo)
+		if err != nil {
+			return "", fmt.Errorf("erro ao listar %s: %w", metodo, err)
+		}
+		c.mu.Lock()
+		*indice = novo
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if id, ok := (*indice)[strings.ToLower(nome)]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("%q não encontrado no workspace", nome)
+}
+
+// listar percorre as páginas de conversations.list ou users.list e indexa os IDs pelos
+// nomes em minúsculas; usuários são indexados pelo nome, nome de exibição e nome real
+func (c *ClienteAPI) listar(ctx context.Context, metodo string) (map[string]string, error) {
+	indice := make(map[string]string)
+	parametros := url.Values{"limit": {"1000"}}
+	if metodo == "conversations.list" {
+		parametros.Set("types", "public_channel,private_channel")
+		parametros.Set("exclude_archived", "true")
+	}
+
+	for {
+		var pagina struct {
+			Channels []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"channels"`
+			Members []struct {
+				ID      string `json:"id"`
+				Name    string `json:"name"`
+				Deleted bool   `json:"deleted"`
+				Profile struct {
+					DisplayName string `json:"display_name"`
+					RealName    string `json:"real_name"`
+				} `json:"profile"`
+			} `json:"members"`
+			Metadata struct {
+				NextCursor string `json:"next_cursor"`
+			} `json:"response_metadata"`
+		}
+		err := retentar(ctx, c.relogio, c.retentativas, OpResolverDestino, func() error {
+			return c.chamar(ctx, metodo, parametros, &pagina)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, canal := range pagina.Channels {
+			indice[strings.ToLower(canal.Name)] = canal.ID
+		}
+		for _, membro := range pagina.Members {
+			if membro.Deleted {
+				continue
+			}
+			for _, nome := range []string{membro.Profile.RealName, membro.Profile.DisplayName, membro.Name} {
+				if nome != "" {
+					indice[strings.ToLower(nome)] = membro.ID
+				}
+			}
+		}
+
+		if pagina.Metadata.NextCursor == "" {
+			return indice, nil
+		}
+		parametros.Set("cursor", pagina.Metadata.NextCursor)
+	}
+}
@@ -0,0 +1,51 @@
+package slack
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestDestinosCarregar(t *testing.T) {
+	casos := []struct {
+		nome  string
+		json  string
+		falha bool
+	}{
+		{"tipos e mensagens", `{"por_tipo": {"entrada": ["#equipe"], "lembrete": ["@eu"]}, "por_mensagem": {"já volto": ["@eu"]}}`, false},
+		{"vazio", `{}`, false},
+		{"tipo desconhecido", `{"por_tipo": {"entrda": ["#equipe"]}}`, true},
+		{"formato antigo", `{"entrada": ["#equipe"]}`, true},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.nome, func(t *testing.T) {
+			var destinos Destinos
+			err := json.Unmarshal([]byte(caso.json), &destinos)
+			if (err != nil) != caso.falha {
+				t.Fatalf("Unmarshal(%s) = %v, falha esperada: %v", caso.json, err, caso.falha)
+			}
+		})
+	}
+}
+
+func TestDestinosPara(t *testing.T) {
+	destinos := Destinos{
+		PorTipo:     map[string][]string{TipoEntrada: {"#equipe"}},
+		PorMensagem: map[string][]string{"Já volto": {"@eu"}},
+	}
+
+	casos := []struct {
+		tipo, msg string
+		esperado  []string
+	}{
+		{TipoEntrada, "bom dia", []string{"#equipe"}},
+		{TipoRefeicao, " já volto ", []string{"@eu"}},
+		{TipoSaida, "até amanhã", []string{canalMensagens}},
+	}
+	for _, caso := range casos {
+		if obtido := destinos.Para(caso.tipo, caso.msg); !slices.Equal(obtido, caso.esperado) {
+			t.Errorf("Para(%q, %q) = %v, esperado %v", caso.tipo, caso.msg, obtido, caso.esperado)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+)
+
+// ExtrairStatus lê o status exibido no modal de status já aberto em ctx.
+// Retorna nil quando não há status definido.
+func ExtrairStatus(ctx context.Context, seletores selectors.Slack) (*Status, error) {
+	type resultadoStatus struct {
+		TemStatus bool   `json:"hasStatus"`
+		Emoji     string `json:"emoji"`
+		Mensagem  string `json:"message"`
+	}
+	var resultado resultadoStatus
+
+	err := common.Executar(ctx, chromedp.Evaluate(fmt.Sprintf(`
+		(() => {
+			// Função para extrair texto limpo
+			function extractCleanText(element) {
+				if (!element) return '';
+				// Remove espaços extras e quebras de linha
+				return element.textContent.replace(/\s+/g, ' ').trim();
+			}
+
+			// Busca o texto do status
+			const statusInput = document.querySelector(%s);
+			const statusText = statusInput ? statusInput.querySelector(%s) : null;
+			const message = extractCleanText(statusText);
+
+			// Busca o emoji
+			const emojiImg = document.querySelector(%s);
+			if (!emojiImg || !message) {
+				return { hasStatus: false };
+			}
+
+			// Extrai o código do emoji
+			const emojiAlt = emojiImg.alt || '';
+			const emoji = emojiAlt.replace(/:/g, '');
+
+			return {
+				hasStatus: true,
+				emoji: ':' + emoji + ':',
+				message: message
+			};
+		})()
+	`, selectors.JS(seletores.CorpoStatus), selectors.JS(seletores.TextoStatus),
+		selectors.JS(seletores.EmojiStatus)), &resultado))
+	if err != nil {
+		return nil, err
+	}
+
+	if !resultado.TemStatus {
+		return nil, nil
+	}
+	return &Status{
+		Emoji:    resultado.Emoji,
+		Mensagem: resultado.Mensagem,
+	}, nil
+}
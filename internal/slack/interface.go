@@ -3,6 +3,11 @@ package slack
 import (
 	"context"
 	"fmt"
+
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/snapshot"
 )
 
 // GerenciadorStatus manipula operações de status do Slack
@@ -19,8 +24,10 @@ type GerenciadorStatus interface {
 
 // GerenciadorMensagem manipula operações de mensagens do Slack
 type GerenciadorMensagem interface {
-	// EnviarMensagem envia uma mensagem para o canal configurado
-	EnviarMensagem(msg string) error
+	// EnviarMensagem envia a mensagem aos destinos configurados para o tipo (TipoEntrada,
+	// TipoRefeicao, TipoSaida, TipoLembrete) ou para o texto, informando o resultado de
+	// cada destino; o erro resume os destinos que falharam
+	EnviarMensagem(tipoMensagem, msg string) ([]ResultadoEnvio, error)
 
 	// PrepararMensagem prepara uma mensagem baseada no tipo
 	PrepararMensagem(tipoMensagem string) (bool, string, error)
@@ -64,10 +71,47 @@ type Configuracao struct {
 	// ModoSilencioso determina se o navegador deve rodar em modo silencioso
 	// Quando falso, o navegador será visível para autenticação manual
 	ModoSilencioso bool
+	// Navegador é a instância compartilhada do Chromium; se nil, uma instância própria é iniciada
+	Navegador *common.GerenciadorNavegador
+	// Retentativas define a política por operação (OpEnviarMensagem, OpObterStatus, ...);
+	// campos não informados usam PoliticaRetentativaPadrao
+	Retentativas map[string]common.PoliticaRetentativa
+	// Seletores contém os seletores da interface web do Slack; se nil, usa o mapa embutido
+	Seletores *selectors.Slack
+	// Gravador salva snapshots do modal de status lido; nil desabilita
+	Gravador *snapshot.Gravador
+	// Relogio mede esperas, tempos limite e retentativas; nil usa o relógio do sistema
+	Relogio common.Relogio
+	// Segredos guarda os cookies da sessão (cofre, keyring ou arquivo); nil usa slack_cookies.json
+	Segredos ArmazemSegredos
+	// Backend escolhe entre o navegador (BackendNavegador, padrão) e a Web API (BackendAPI)
+	Backend string
+	// URLBase é o endereço do workspace usado pelo backend da API; vazio usa o workspace do batedor
+	URLBase string
+	// Token é o token da API (xoxp ou xoxc); vazio usa SLACK_TOKEN, o token salvo em Segredos
+	// ou o token da sessão web obtido com os cookies salvos
+	Token string
+	// Destinos define as conversas de cada tipo ou texto de mensagem; vazio usa a conversa
+	// padrão do batedor
+	Destinos Destinos
+}
+
+// ArmazemSegredos persiste os cookies da sessão; auth.CredentialStore atende a interface
+type ArmazemSegredos interface {
+	LoadSecret(chave string) ([]byte, error)
+	SaveSecret(chave string, valor []byte) error
 }
 
 // NewModulo cria uma nova instância do módulo Slack
 func NewModulo(ctx context.Context, config Configuracao) (OperacoesSlack, error) {
+	switch config.Backend {
+	case "", BackendNavegador:
+	case BackendAPI:
+		return novoModuloAPI(ctx, config)
+	default:
+		return nil, fmt.Errorf("backend do Slack desconhecido %q (use %s ou %s)", config.Backend, BackendNavegador, BackendAPI)
+	}
+
 	// Primeiro tenta com modo silencioso
 	ops, err := NovoGerenciadorOperacoes(ctx, config)
 	if err != nil {
@@ -85,6 +129,12 @@ func NewModulo(ctx context.Context, config Configuracao) (OperacoesSlack, error)
 		ops, err = NovoGerenciadorOperacoes(ctx, Configuracao{
 			DiretorioConfig: config.DiretorioConfig,
 			ModoSilencioso:  false, // Força modo não-silencioso para autenticação interativa
+			Navegador:       config.Navegador,
+			Retentativas:    config.Retentativas,
+			Seletores:       config.Seletores,
+			Gravador:        config.Gravador,
+			Relogio:         config.Relogio,
+			Segredos:        config.Segredos,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("falha ao criar sessão interativa do slack: %w", err)
@@ -122,3 +172,28 @@ func NewModulo(ctx context.Context, config Configuracao) (OperacoesSlack, error)
 
 	return ops, nil
 }
+
+// ValidarSeletores abre o Slack com os cookies salvos e verifica os seletores da página do aplicativo
+func ValidarSeletores(ctx context.Context, config Configuracao, mapa *selectors.Mapa) ([]selectors.ResultadoValidacao, error) {
+	config.Seletores = &mapa.Slack
+	sessao := NovaSessaoSlack(ctx, config)
+	if sessao == nil {
+		return nil, fmt.Errorf("falha ao criar sessão do slack")
+	}
+	defer sessao.Close()
+
+	if err := sessao.CarregarCookies(config.DiretorioConfig); err != nil {
+		return nil, fmt.Errorf("falha ao carregar cookies do Slack: %w", err)
+	}
+
+	if err := sessao.ValidarSessao(); err != nil {
+		return nil, fmt.Errorf("falha ao validar sessão do Slack: %w", err)
+	}
+
+	// Aguarda a interface carregar antes de verificar os seletores
+	esperaCtx, cancelar := sessao.comTempoLimite(tempoLimiteOperacao)
+	defer cancelar()
+	_ = common.Executar(esperaCtx, chromedp.WaitVisible(mapa.Slack.BotaoUsuario))
+
+	return selectors.Validar(sessao.ctx, mapa.Filtrar("app"))
+}
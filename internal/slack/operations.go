@@ -14,7 +14,7 @@ type GerenciadorOperacoes struct {
 
 // NovoGerenciadorOperacoes cria uma nova instância de GerenciadorOperacoes
 func NovoGerenciadorOperacoes(ctx context.Context, config Configuracao) (*GerenciadorOperacoes, error) {
-	sessao := NovaSessaoSlack(ctx, config.ModoSilencioso)
+	sessao := NovaSessaoSlack(ctx, config)
 	if sessao == nil {
 		return nil, fmt.Errorf("falha ao criar sessão do slack")
 	}
@@ -68,10 +68,14 @@ func (o *GerenciadorOperacoes) ObterStatusAtual() (*Status, error) {
 }
 
 // EnviarMensagem implementa a interface GerenciadorMensagem
-func (o *GerenciadorOperacoes) EnviarMensagem(msg string) error {
-	return o.OperacaoMensagem(func() error {
-		return o.mensagem.EnviarMensagem(msg)
+func (o *GerenciadorOperacoes) EnviarMensagem(tipoMensagem, msg string) ([]ResultadoEnvio, error) {
+	var resultados []ResultadoEnvio
+	err := o.OperacaoMensagem(func() error {
+		var err error
+		resultados, err = o.mensagem.EnviarMensagem(tipoMensagem, msg)
+		return err
 	})
+	return resultados, err
 }
 
 // PrepararMensagem implementa a interface GerenciadorMensagem
@@ -43,17 +43,22 @@ var (
 
 // PrepararMensagem prepara uma mensagem baseada no tipo
 func (s *SessaoSlack) PrepararMensagem(tipoMensagem string) (bool, string, error) {
+	return prepararMensagem(tipoMensagem)
+}
+
+// prepararMensagem escolhe a mensagem do tipo informado e pede a confirmação do envio
+func prepararMensagem(tipoMensagem string) (bool, string, error) {
 	var (
 		mensagem string
 		err      error
 	)
 
 	switch tipoMensagem {
-	case "entrada":
+	case TipoEntrada:
 		mensagem, err = selecionarMensagemEntrada()
-	case "refeicao":
+	case TipoRefeicao:
 		mensagem = mensagemAlmoco
-	case "saida":
+	case TipoSaida:
 		mensagem, err = selecionarMensagemSaida()
 	default:
 		return false, "", fmt.Errorf("tipo de mensagem inválido: %s", tipoMensagem)
@@ -3,7 +3,9 @@ package slack
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,21 +13,29 @@ import (
 
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/cofre"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/snapshot"
 )
 
 const (
 	slackBaseURL     = "https://fintools-ot.slack.com"
-	slackDMURL       = "https://app.slack.com/client/TSAD5P1GB/C010LNL7KS9"
+	slackClientURL   = "https://app.slack.com/client/TSAD5P1GB/"
 	slackRedirectURL = "https://fintools-ot.slack.com/ssb/redirect"
 
 	tempoLimiteOperacao = 30 * time.Second
 	tempoLimiteAuth     = 2 * time.Minute
-	maxTentativas       = 3
-	atrasoTentativa     = time.Second
-	arquivoCookies      = "slack_cookies.json"
+	arquivoCookies      = ChaveCookies + ".json"
 	diretorioConfig     = ".batedorponto"
+
+	// canalMensagens é a conversa usada quando nenhum destino está configurado
+	canalMensagens = "C010LNL7KS9"
 )
 
+// ChaveCookies identifica os cookies da sessão no armazenamento de segredos
+const ChaveCookies = cofre.ItemCookiesSlack
+
 type Navegador interface {
 	Navegar(url string) error
 	ObterLocalizacao() (string, error)
@@ -38,9 +48,20 @@ type Navegador interface {
 }
 
 type SessaoSlack struct {
-	ctx       context.Context
-	cancelar  context.CancelFunc
-	navegador Navegador
+	ctx          context.Context
+	cancelar     context.CancelFunc
+	navegador    Navegador
+	retentativas map[string]common.PoliticaRetentativa
+	seletores    selectors.Slack
+	gravador     *snapshot.Gravador
+	relogio      common.Relogio
+	segredos     ArmazemSegredos
+	diretorio    string
+	destinos     Destinos
+
+	// resolvedor consulta pela Web API os IDs dos destinos informados por nome; é criado
+	// no primeiro envio que precisar dele
+	resolvedor *ClienteAPI
 }
 
 type NavegadorChrome struct {
@@ -48,34 +69,34 @@ type NavegadorChrome struct {
 }
 
 func (b *NavegadorChrome) Navegar(url string) error {
-	return chromedp.Run(b.ctx, chromedp.Navigate(url))
+	return common.Executar(b.ctx, chromedp.Navigate(url))
 }
 
 func (b *NavegadorChrome) ObterLocalizacao() (string, error) {
 	var url string
-	err := chromedp.Run(b.ctx, chromedp.Location(&url))
+	err := common.Executar(b.ctx, chromedp.Location(&url))
 	return url, err
 }
 
 func (b *NavegadorChrome) AguardarElemento(seletor string) error {
-	return chromedp.Run(b.ctx, chromedp.WaitVisible(seletor))
+	return common.Executar(b.ctx, chromedp.WaitVisible(seletor))
 }
 
 func (b *NavegadorChrome) Clicar(seletor string) error {
-	return chromedp.Run(b.ctx, chromedp.Click(seletor))
+	return common.Executar(b.ctx, common.Clicar(seletor))
 }
 
 func (b *NavegadorChrome) EnviarTeclas(seletor, texto string) error {
-	return chromedp.Run(b.ctx, chromedp.SendKeys(seletor, texto))
+	return common.Executar(b.ctx, chromedp.SendKeys(seletor, texto))
 }
 
 func (b *NavegadorChrome) PressionarEnter() error {
-	return chromedp.Run(b.ctx, chromedp.KeyEvent("\r"))
+	return common.Executar(b.ctx, chromedp.KeyEvent("\r"))
 }
 
 func (b *NavegadorChrome) ObterCookies() ([]*network.Cookie, error) {
 	var cookies []*network.Cookie
-	err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+	err := common.Executar(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
 		var err error
 		cookies, err = network.GetCookies().Do(ctx)
 		return err
@@ -84,7 +105,7 @@ func (b *NavegadorChrome) ObterCookies() ([]*network.Cookie, error) {
 }
 
 func (b *NavegadorChrome) DefinirCookies(cookies []*network.Cookie) error {
-	return chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+	return common.Executar(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
 		for _, cookie := range cookies {
 			err := network.SetCookie(cookie.Name, cookie.Value).
 				WithPath(cookie.Path).
@@ -98,26 +119,25 @@ func (b *NavegadorChrome) DefinirCookies(cookies []*network.Cookie) error {
 	}))
 }
 
-func obterOpcoesNavegador(modoSilencioso bool) []chromedp.ExecAllocatorOption {
-	return append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.Flag("headless", modoSilencioso),
-		chromedp.Flag("no-sandbox", true),
-		chromedp.Flag("disable-gpu", false),
-		chromedp.Flag("disable-extensions", true),
-		chromedp.Flag("disable-setuid-sandbox", true),
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.WindowSize(1280, 720),
-		chromedp.UserAgent("Mozilla/5.0 (X11; Linux x86_64) Chrome/120.0.0.0"),
-	)
-}
+func criarContextoNavegador(config Configuracao) (context.Context, context.CancelFunc, error) {
+	// Usa a instância compartilhada quando disponível
+	if config.Navegador != nil {
+		if config.ModoSilencioso {
+			return config.Navegador.NovaAba()
+		}
+		return config.Navegador.NovaJanelaInterativa()
+	}
 
-func criarContextoNavegador(modoSilencioso bool) (context.Context, context.CancelFunc, error) {
-	opts := obterOpcoesNavegador(modoSilencioso)
-	allocCtx, _ := chromedp.NewExecAllocator(context.Background(), opts...)
-	ctx, cancelar := chromedp.NewContext(allocCtx)
+	opts := common.OpcoesNavegador(config.ModoSilencioso)
+	allocCtx, cancelarAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	ctx, cancelarNavegador := chromedp.NewContext(allocCtx)
+	cancelar := func() {
+		cancelarNavegador()
+		cancelarAlloc()
+	}
 
 	// Aguarda o navegador iniciar
-	if err := chromedp.Run(ctx); err != nil {
+	if err := common.Executar(ctx); err != nil {
 		cancelar()
 		return nil, nil, fmt.Errorf("erro ao iniciar navegador: %w", err)
 	}
@@ -126,22 +146,48 @@ func criarContextoNavegador(modoSilencioso bool) (context.Context, context.Cance
 }
 
 // NovaSessaoSlack cria uma nova sessão do Slack com o modo de navegador especificado
-func NovaSessaoSlack(ctxPai context.Context, modoSilencioso bool) *SessaoSlack {
-	ctx, cancelar, err := criarContextoNavegador(modoSilencioso)
+func NovaSessaoSlack(ctxPai context.Context, config Configuracao) *SessaoSlack {
+	ctx, cancelar, err := criarContextoNavegador(config)
 	if err != nil {
 		fmt.Printf("\n⚠️  Erro ao criar sessão: %v\n", err)
 		return nil
 	}
 
+	seletores := config.Seletores
+	if seletores == nil {
+		seletores = &selectors.Padrao().Slack
+	}
+
 	navegador := &NavegadorChrome{ctx: ctx}
 	return &SessaoSlack{
-		ctx:       ctx,
-		cancelar:  cancelar,
-		navegador: navegador,
+		ctx:          ctx,
+		cancelar:     cancelar,
+		navegador:    navegador,
+		retentativas: config.Retentativas,
+		seletores:    *seletores,
+		gravador:     config.Gravador,
+		relogio:      common.RelogioOuSistema(config.Relogio),
+		segredos:     config.Segredos,
+		diretorio:    config.DiretorioConfig,
+		destinos:     config.Destinos,
+	}
+}
+
+// abrirModalStatus abre o modal de status personalizado a partir do menu do usuário
+func (s *SessaoSlack) abrirModalStatus() chromedp.Tasks {
+	return chromedp.Tasks{
+		common.Clicar(s.seletores.BotaoUsuario),
+		chromedp.WaitVisible(s.seletores.ItemStatus),
+		common.Clicar(s.seletores.ItemStatus),
+		chromedp.WaitVisible(s.seletores.ModalStatus),
 	}
 }
 
 func (s *SessaoSlack) Close() {
+	if s.resolvedor != nil {
+		s.resolvedor.Close()
+		s.resolvedor = nil
+	}
 	if s.cancelar != nil {
 		s.cancelar()
 		s.cancelar = nil
@@ -149,18 +195,42 @@ func (s *SessaoSlack) Close() {
 }
 
 func (s *SessaoSlack) comTempoLimite(d time.Duration) (context.Context, context.CancelFunc) {
-	return context.WithTimeout(s.ctx, d)
+	return common.ComTempoLimite(s.ctx, s.relogio, d)
 }
 
-func (s *SessaoSlack) tentarNovamente(fn func() error) error {
-	var err error
-	for i := 0; i < maxTentativas; i++ {
-		if err = fn(); err == nil {
-			return nil
-		}
-		time.Sleep(atrasoTentativa * time.Duration(i+1))
+// PoliticaRetentativaPadrao é usada nas operações sem política configurada
+var PoliticaRetentativaPadrao = common.PoliticaRetentativa{
+	MaxTentativas: 3,
+	EsperaInicial: time.Second,
+	EsperaMaxima:  4 * time.Second,
+	Multiplicador: 2,
+	Jitter:        common.Fracao(0.2),
+}
+
+// Nomes das operações usados na configuração de retentativas e nos logs
+const (
+	OpSalvarCookies   = "salvar_cookies"
+	OpCarregarCookies = "carregar_cookies"
+	OpValidarSessao   = "validar_sessao"
+	OpNavegarDM       = "navegar_dm"
+	OpEnviarMensagem  = "enviar_mensagem"
+	OpObterStatus     = "obter_status"
+)
+
+func (s *SessaoSlack) tentarNovamente(ctx context.Context, nome string, fn func() error) error {
+	return retentar(ctx, s.relogio, s.retentativas, nome, fn)
+}
+
+// retentar repete fn segundo a política configurada para a operação
+func retentar(ctx context.Context, relogio common.Relogio, retentativas map[string]common.PoliticaRetentativa, nome string, fn func() error) error {
+	politica := retentativas[nome].Mesclar(PoliticaRetentativaPadrao)
+	_, tentativas, err := common.Retentar(ctx, relogio, "slack:"+nome, politica, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	if err != nil {
+		return fmt.Errorf("falha após %d tentativas: %w", tentativas, err)
 	}
-	return fmt.Errorf("falha após %d tentativas: %v", maxTentativas, err)
+	return nil
 }
 
 func obterCaminhoCookies(diretorio string) string {
@@ -168,7 +238,7 @@ func obterCaminhoCookies(diretorio string) string {
 }
 
 func (s *SessaoSlack) SalvarCookies(diretorio string) error {
-	return s.tentarNovamente(func() error {
+	return s.tentarNovamente(s.ctx, OpSalvarCookies, func() error {
 		cookies, err := s.navegador.ObterCookies()
 		if err != nil {
 			return fmt.Errorf("erro ao obter cookies: %w", err)
@@ -178,13 +248,19 @@ func (s *SessaoSlack) SalvarCookies(diretorio string) error {
 			return fmt.Errorf("nenhum cookie encontrado")
 		}
 
-		caminho := obterCaminhoCookies(diretorio)
 		dados, err := json.Marshal(cookies)
 		if err != nil {
 			return fmt.Errorf("erro ao serializar cookies: %w", err)
 		}
 
-		if err := os.WriteFile(caminho, dados, 0600); err != nil {
+		if s.segredos != nil {
+			if err := s.segredos.SaveSecret(ChaveCookies, dados); err != nil {
+				return fmt.Errorf("erro ao salvar cookies: %w", err)
+			}
+			return nil
+		}
+
+		if err := os.WriteFile(obterCaminhoCookies(diretorio), dados, 0600); err != nil {
 			return fmt.Errorf("erro ao salvar cookies: %w", err)
 		}
 
@@ -193,10 +269,10 @@ func (s *SessaoSlack) SalvarCookies(diretorio string) error {
 }
 
 func (s *SessaoSlack) CarregarCookies(diretorio string) error {
-	return s.tentarNovamente(func() error {
-		dados, err := os.ReadFile(obterCaminhoCookies(diretorio))
+	return s.tentarNovamente(s.ctx, OpCarregarCookies, func() error {
+		dados, err := lerCookies(s.segredos, diretorio)
 		if err != nil {
-			return fmt.Errorf("erro ao ler cookies: %w", err)
+			return err
 		}
 
 		var cookies []*network.Cookie
@@ -216,9 +292,48 @@ func (s *SessaoSlack) CarregarCookies(diretorio string) error {
 	})
 }
 
+// lerCookies lê os cookies do armazenamento de segredos, quando configurado, ou do arquivo
+// slack_cookies.json
+func lerCookies(segredos ArmazemSegredos, diretorio string) ([]byte, error) {
+	if segredos != nil {
+		dados, err := segredos.LoadSecret(ChaveCookies)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler cookies: %w", err)
+		}
+		return dados, nil
+	}
+
+	dados, err := os.ReadFile(obterCaminhoCookies(diretorio))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cookies: %w", err)
+	}
+	return dados, nil
+}
+
+// MigrarCookies copia o slack_cookies.json para o cofre desbloqueado e apaga o arquivo.
+// Retorna false se não havia cookies salvos.
+func MigrarCookies(diretorio string, c *cofre.Cofre) (bool, error) {
+	caminho := obterCaminhoCookies(diretorio)
+	dados, err := os.ReadFile(caminho)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("erro ao ler cookies: %w", err)
+	}
+
+	if err := c.Definir(ChaveCookies, dados); err != nil {
+		return false, fmt.Errorf("erro ao salvar cookies no cofre: %w", err)
+	}
+	if err := os.Remove(caminho); err != nil {
+		return true, fmt.Errorf("cookies copiados para o cofre, mas %s não foi apagado: %w", arquivoCookies, err)
+	}
+	return true, nil
+}
+
 func (s *SessaoSlack) obterURLAtual(ctx context.Context) (string, error) {
 	var url string
-	if err := chromedp.Run(ctx, chromedp.Location(&url)); err != nil {
+	if err := common.Executar(ctx, chromedp.Location(&url)); err != nil {
 		return "", fmt.Errorf("erro ao obter URL: %w", err)
 	}
 	return url, nil
@@ -233,7 +348,7 @@ func (s *SessaoSlack) eSessaoValida(ctx context.Context) bool {
 }
 
 func (s *SessaoSlack) validarSessaoSomente() error {
-	ctx, cancelar := context.WithTimeout(s.ctx, 30*time.Second)
+	ctx, cancelar := s.comTempoLimite(30 * time.Second)
 	defer cancelar()
 
 	// Primeiro verifica se já estamos em uma página válida do Slack
@@ -242,8 +357,8 @@ func (s *SessaoSlack) validarSessaoSomente() error {
 	}
 
 	// Se não estiver, tenta navegar para a URL base
-	return s.tentarNovamente(func() error {
-		if err := chromedp.Run(ctx, chromedp.Navigate(slackBaseURL)); err != nil {
+	return s.tentarNovamente(ctx, OpValidarSessao, func() error {
+		if err := common.Executar(ctx, chromedp.Navigate(slackBaseURL)); err != nil {
 			return fmt.Errorf("erro ao navegar: %w", err)
 		}
 
@@ -255,29 +370,60 @@ func (s *SessaoSlack) validarSessaoSomente() error {
 	})
 }
 
-func (s *SessaoSlack) navegarParaDM() error {
-	ctx, cancelar := context.WithTimeout(s.ctx, 30*time.Second)
+func (s *SessaoSlack) navegarParaConversa(canal string) error {
+	ctx, cancelar := s.comTempoLimite(30 * time.Second)
 	defer cancelar()
 
-	// Verifica se já estamos na DM
+	// Verifica se já estamos na conversa
+	destino := slackClientURL + canal
 	url, err := s.obterURLAtual(ctx)
-	if err == nil && url == slackDMURL {
+	if err == nil && url == destino {
 		return nil
 	}
 
-	return s.tentarNovamente(func() error {
-		if err := chromedp.Run(ctx, chromedp.Navigate(slackDMURL)); err != nil {
-			return fmt.Errorf("erro ao navegar para DM: %w", err)
+	return s.tentarNovamente(ctx, OpNavegarDM, func() error {
+		if err := common.Executar(ctx, chromedp.Navigate(destino)); err != nil {
+			return fmt.Errorf("erro ao navegar para a conversa: %w", err)
 		}
 		return nil
 	})
 }
 
+// resolverDestino usa IDs de conversa diretamente; nomes e IDs de usuário vêm do cache de
+// destinos ou são resolvidos pela Web API com o token da sessão obtido dos cookies salvos
+func (s *SessaoSlack) resolverDestino(destino string) (string, error) {
+	destino = strings.TrimSpace(destino)
+	if idConversa.MatchString(destino) && !strings.HasPrefix(destino, "U") && !strings.HasPrefix(destino, "W") {
+		return destino, nil
+	}
+
+	if s.resolvedor == nil {
+		s.resolvedor = NovoClienteAPI(s.ctx, Configuracao{
+			Retentativas:    s.retentativas,
+			Relogio:         s.relogio,
+			Segredos:        s.segredos,
+			DiretorioConfig: s.diretorio,
+		})
+	}
+	if canal, ok := s.resolvedor.conversaEmCache(destino); ok {
+		return canal, nil
+	}
+	if s.resolvedor.token == "" {
+		if err := s.resolvedor.CarregarCookies(s.diretorio); err != nil {
+			return "", fmt.Errorf("não foi possível consultar os nomes no Slack: %w", err)
+		}
+	}
+
+	ctx, cancelar := s.comTempoLimite(tempoLimiteOperacao)
+	defer cancelar()
+	return s.resolvedor.resolverDestino(ctx, destino)
+}
+
 func (s *SessaoSlack) Autenticar() error {
 	ctx, cancelar := s.comTempoLimite(tempoLimiteAuth)
 	defer cancelar()
 
-	if err := chromedp.Run(ctx, chromedp.Navigate(slackBaseURL)); err != nil {
+	if err := common.Executar(ctx, chromedp.Navigate(slackBaseURL)); err != nil {
 		return fmt.Errorf("erro ao abrir Slack: %w", err)
 	}
 
@@ -289,9 +435,7 @@ func (s *SessaoSlack) Autenticar() error {
 	}
 
 	// Aguarda um pouco após o login para garantir que os cookies foram salvos
-	time.Sleep(2 * time.Second)
-
-	return nil
+	return common.Dormir(ctx, s.relogio, 2*time.Second)
 }
 
 func (s *SessaoSlack) aguardarLogin(ctx context.Context) error {
@@ -301,14 +445,16 @@ func (s *SessaoSlack) aguardarLogin(ctx context.Context) error {
 			return ctx.Err()
 		default:
 			var url string
-			if err := chromedp.Run(ctx, chromedp.Location(&url)); err != nil {
+			if err := common.Executar(ctx, chromedp.Location(&url)); err != nil {
 				return err
 			}
 
 			if strings.Contains(url, slackRedirectURL) {
 				return nil
 			}
-			time.Sleep(500 * time.Millisecond)
+			if err := common.Dormir(ctx, s.relogio, 500*time.Millisecond); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -319,35 +465,53 @@ func (s *SessaoSlack) ValidarSessao() error {
 		return err
 	}
 
-	// Se a sessão estiver válida, navega para DM
-	return s.navegarParaDM()
+	// Se a sessão estiver válida, navega para a conversa padrão
+	return s.navegarParaConversa(canalMensagens)
 }
 
-func (s *SessaoSlack) EnviarMensagem(msg string) error {
+// EnviarMensagem envia a mensagem a cada destino configurado para o tipo ou o texto da
+// mensagem, abrindo a conversa de cada um
+func (s *SessaoSlack) EnviarMensagem(tipoMensagem, msg string) ([]ResultadoEnvio, error) {
 	if msg == "" {
-		return fmt.Errorf("mensagem vazia")
+		return nil, fmt.Errorf("mensagem vazia")
 	}
 
-	ctx, cancelar := context.WithTimeout(s.ctx, 30*time.Second)
+	ctx, cancelar := s.comTempoLimite(30 * time.Second)
 	defer cancelar()
 
 	// Verifica se a sessão está válida sem navegar
 	if !s.eSessaoValida(ctx) {
 		if err := s.Autenticar(); err != nil {
-			return fmt.Errorf("erro na autenticação: %w", err)
+			return nil, fmt.Errorf("erro na autenticação: %w", err)
 		}
 	}
 
-	// Navega para DM apenas se necessário
-	if err := s.navegarParaDM(); err != nil {
-		return fmt.Errorf("erro ao navegar para DM: %w", err)
+	var resultados []ResultadoEnvio
+	for _, destino := range s.destinos.Para(tipoMensagem, msg) {
+		resultado := ResultadoEnvio{Destino: destino}
+		resultado.Canal, resultado.Erro = s.resolverDestino(destino)
+		if resultado.Erro == nil {
+			resultado.Erro = s.enviarNaConversa(resultado.Canal, msg)
+		}
+		resultados = append(resultados, resultado)
 	}
+	return resultados, erroEnvios(resultados)
+}
 
-	return s.tentarNovamente(func() error {
-		campoMensagem := `div[data-qa="message_input"] > div[contenteditable="true"]`
-		if err := chromedp.Run(ctx,
+// enviarNaConversa abre a conversa, se necessário, e envia a mensagem pelo campo de texto
+func (s *SessaoSlack) enviarNaConversa(canal, msg string) error {
+	if err := s.navegarParaConversa(canal); err != nil {
+		return err
+	}
+
+	ctx, cancelar := s.comTempoLimite(30 * time.Second)
+	defer cancelar()
+
+	return s.tentarNovamente(ctx, OpEnviarMensagem, func() error {
+		campoMensagem := s.seletores.CampoMensagem
+		if err := common.Executar(ctx,
 			chromedp.WaitVisible(campoMensagem),
-			chromedp.Click(campoMensagem),
+			common.Clicar(campoMensagem),
 			chromedp.SendKeys(campoMensagem, msg),
 			chromedp.KeyEvent("\r"),
 		); err != nil {
@@ -360,7 +524,7 @@ func (s *SessaoSlack) EnviarMensagem(msg string) error {
 
 // DefinirStatus define o status do usuário no Slack
 func (s *SessaoSlack) DefinirStatus(status Status) error {
-	ctx, cancelar := context.WithTimeout(s.ctx, 30*time.Second)
+	ctx, cancelar := s.comTempoLimite(30 * time.Second)
 	defer cancelar()
 
 	// Primeiro valida a sessão
@@ -369,44 +533,40 @@ func (s *SessaoSlack) DefinirStatus(status Status) error {
 	}
 
 	// Abre o menu de status
-	if err := chromedp.Run(ctx,
-		chromedp.Click(`button[data-qa="user-button"]`),
-		chromedp.WaitVisible(`button[data-qa="main-menu-custom-status-item"]`),
-		chromedp.Click(`button[data-qa="main-menu-custom-status-item"]`),
-		chromedp.WaitVisible(`div.p-custom_status_modal`),
-	); err != nil {
+	if err := common.Executar(ctx, s.abrirModalStatus()); err != nil {
 		return fmt.Errorf("erro ao abrir menu de status: %w", err)
 	}
 
 	// Aguarda o modal carregar e limpa o status atual
-	if err := chromedp.Run(ctx,
-		chromedp.Evaluate(`
+	if err := common.Executar(ctx,
+		chromedp.Evaluate(fmt.Sprintf(`
 			(() => {
-				const clearButton = document.querySelector('.p_custom_status_modal__input_clear_button');
+				const clearButton = document.querySelector(%s);
 				if (clearButton) clearButton.click();
 				return true;
 			})()
-		`, nil),
+		`, selectors.JS(s.seletores.LimparStatus)), nil),
 	); err != nil {
 		fmt.Printf("\n⚠️  Aviso: não foi possível limpar status atual: %v\n", err)
 	}
 
 	// Tenta encontrar e clicar no status pré-configurado
 	var statusDefinido bool
-	err := chromedp.Run(ctx,
-		chromedp.Evaluate(`
+	err := common.Executar(ctx,
+		chromedp.Evaluate(fmt.Sprintf(`
 			(() => {
-				const sections = document.querySelectorAll('.p-custom_status_modal__presets');
+				const mensagem = %s;
+				const sections = document.querySelectorAll(%s);
 				for (const section of sections) {
-					const containers = section.querySelectorAll('.p-custom_status_modal__preset_container');
+					const containers = section.querySelectorAll(%s);
 					for (const container of containers) {
-						const button = container.querySelector('button.p-custom_status_modal__preset');
+						const button = container.querySelector(%s);
 						if (!button) continue;
 
-						const statusText = button.querySelector('[data-qa="custom_status_text"]');
+						const statusText = button.querySelector(%s);
 						if (!statusText) continue;
 
-						if (statusText.textContent.trim() === '`+status.Mensagem+`') {
+						if (statusText.textContent.trim() === mensagem) {
 							button.click();
 							return true;
 						}
@@ -414,16 +574,17 @@ func (s *SessaoSlack) DefinirStatus(status Status) error {
 				}
 				return false;
 			})()
-		`, &statusDefinido))
+		`, selectors.JS(status.Mensagem), selectors.JS(s.seletores.SecaoPresets), selectors.JS(s.seletores.ContainerPreset),
+			selectors.JS(s.seletores.BotaoPreset), selectors.JS(s.seletores.TextoPreset)), &statusDefinido))
 
 	if err != nil {
 		return fmt.Errorf("erro ao selecionar status: %w", err)
 	}
 
 	// Aguarda o status ser selecionado e clica no botão de salvar
-	err = chromedp.Run(ctx,
-		chromedp.WaitVisible(`button[data-qa="custom_status_input_go"]`),
-		chromedp.Click(`button[data-qa="custom_status_input_go"]`),
+	err = common.Executar(ctx,
+		chromedp.WaitVisible(s.seletores.SalvarStatus),
+		common.Clicar(s.seletores.SalvarStatus),
 	)
 
 	if err != nil {
@@ -431,9 +592,9 @@ func (s *SessaoSlack) DefinirStatus(status Status) error {
 	}
 
 	// Aguarda o modal fechar e verifica se o status foi alterado
-	err = chromedp.Run(ctx,
+	err = common.Executar(ctx,
 		chromedp.Sleep(1*time.Second), // Dá um tempo para o modal fechar
-		chromedp.WaitNotPresent(`div.p-custom_status_modal`),
+		chromedp.WaitNotPresent(s.seletores.ModalStatus),
 	)
 
 	if err != nil {
@@ -455,7 +616,7 @@ func (s *SessaoSlack) DefinirStatus(status Status) error {
 
 // LimparStatus limpa o status do usuário no Slack
 func (s *SessaoSlack) LimparStatus() error {
-	ctx, cancelar := context.WithTimeout(s.ctx, 30*time.Second)
+	ctx, cancelar := s.comTempoLimite(30 * time.Second)
 	defer cancelar()
 
 	// Primeiro valida a sessão
@@ -464,35 +625,30 @@ func (s *SessaoSlack) LimparStatus() error {
 	}
 
 	// Abre o menu de status
-	if err := chromedp.Run(ctx,
-		chromedp.Click(`button[data-qa="user-button"]`),
-		chromedp.WaitVisible(`button[data-qa="main-menu-custom-status-item"]`),
-		chromedp.Click(`button[data-qa="main-menu-custom-status-item"]`),
-		chromedp.WaitVisible(`div.p-custom_status_modal`),
-	); err != nil {
+	if err := common.Executar(ctx, s.abrirModalStatus()); err != nil {
 		return fmt.Errorf("erro ao abrir menu de status: %w", err)
 	}
 
 	// Limpa o status atual
-	if err := chromedp.Run(ctx,
-		chromedp.Evaluate(`
+	if err := common.Executar(ctx,
+		chromedp.Evaluate(fmt.Sprintf(`
 			(() => {
-				const clearButton = document.querySelector('.p_custom_status_modal__input_clear_button');
+				const clearButton = document.querySelector(%s);
 				if (clearButton) {
 					clearButton.click();
 					return true;
 				}
 				return false;
 			})()
-		`, nil),
+		`, selectors.JS(s.seletores.LimparStatus)), nil),
 	); err != nil {
 		return fmt.Errorf("erro ao limpar status: %w", err)
 	}
 
 	// Aguarda e clica no botão de salvar
-	err := chromedp.Run(ctx,
-		chromedp.WaitVisible(`button[data-qa="custom_status_input_go"]`),
-		chromedp.Click(`button[data-qa="custom_status_input_go"]`),
+	err := common.Executar(ctx,
+		chromedp.WaitVisible(s.seletores.SalvarStatus),
+		common.Clicar(s.seletores.SalvarStatus),
 	)
 
 	if err != nil {
@@ -500,9 +656,9 @@ func (s *SessaoSlack) LimparStatus() error {
 	}
 
 	// Aguarda o modal fechar e verifica se o status foi alterado
-	err = chromedp.Run(ctx,
+	err = common.Executar(ctx,
 		chromedp.Sleep(1*time.Second), // Dá um tempo para o modal fechar
-		chromedp.WaitNotPresent(`div.p-custom_status_modal`),
+		chromedp.WaitNotPresent(s.seletores.ModalStatus),
 	)
 
 	if err != nil {
@@ -524,7 +680,7 @@ func (s *SessaoSlack) LimparStatus() error {
 
 // ObterStatusAtual obtém o status atual do usuário no Slack
 func (s *SessaoSlack) ObterStatusAtual() (*Status, error) {
-	ctx, cancelar := context.WithTimeout(s.ctx, 30*time.Second)
+	ctx, cancelar := s.comTempoLimite(30 * time.Second)
 	defer cancelar()
 
 	// Primeiro valida a sessão
@@ -533,74 +689,29 @@ func (s *SessaoSlack) ObterStatusAtual() (*Status, error) {
 	}
 
 	var status *Status
-	err := s.tentarNovamente(func() error {
+	err := s.tentarNovamente(ctx, OpObterStatus, func() error {
 		// Abre o menu de status
-		if err := chromedp.Run(ctx,
-			chromedp.Click(`button[data-qa="user-button"]`),
-			chromedp.WaitVisible(`button[data-qa="main-menu-custom-status-item"]`),
-			chromedp.Click(`button[data-qa="main-menu-custom-status-item"]`),
-			chromedp.WaitVisible(`div.p-custom_status_modal`),
-		); err != nil {
+		if err := common.Executar(ctx, s.abrirModalStatus()); err != nil {
 			return fmt.Errorf("erro ao abrir menu de status: %w", err)
 		}
 
 		// Obtém o status atual
-		type resultadoStatus struct {
-			TemStatus bool   `json:"hasStatus"`
-			Emoji     string `json:"emoji"`
-			Mensagem  string `json:"message"`
-		}
-		var resultado resultadoStatus
-
-		err := chromedp.Run(ctx, chromedp.Evaluate(`
-			(() => {
-				// Função para extrair texto limpo
-				function extractCleanText(element) {
-					if (!element) return '';
-					// Remove espaços extras e quebras de linha
-					return element.textContent.replace(/\s+/g, ' ').trim();
-				}
-
-				// Busca o texto do status
-				const statusInput = document.querySelector('div[data-qa="custom_status_input_body"]');
-				const statusText = statusInput ? statusInput.querySelector('.ql-editor p') : null;
-				const message = extractCleanText(statusText);
-
-				// Busca o emoji
-				const emojiImg = document.querySelector('.p_custom_status_modal__input_emoji_picker img.c-emoji');
-				if (!emojiImg || !message) {
-					return { hasStatus: false };
-				}
-
-				// Extrai o código do emoji
-				const emojiAlt = emojiImg.alt || '';
-				const emoji = emojiAlt.replace(/:/g, '');
-
-				return {
-					hasStatus: true,
-					emoji: ':' + emoji + ':',
-					message: message
-				};
-			})()
-		`, &resultado))
-
+		resultado, err := ExtrairStatus(ctx, s.seletores)
 		if err != nil {
 			return fmt.Errorf("erro ao obter status: %w", err)
 		}
+		if err := s.gravador.Gravar(ctx, snapshot.PaginaStatusSlack, resultado); err != nil {
+			log.Printf("falha ao gravar snapshot: %v", err)
+		}
 
 		// Fecha o modal
-		if err := chromedp.Run(ctx,
-			chromedp.Click(`button[data-qa="sk_close_modal_button"]`),
+		if err := common.Executar(ctx,
+			common.Clicar(s.seletores.FecharModal),
 		); err != nil {
 			return fmt.Errorf("erro ao fechar modal: %w", err)
 		}
 
-		if resultado.TemStatus {
-			status = &Status{
-				Emoji:    resultado.Emoji,
-				Mensagem: resultado.Mensagem,
-			}
-		}
+		status = resultado
 
 		return nil
 	})
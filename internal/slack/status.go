@@ -2,6 +2,7 @@ package slack
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
@@ -49,11 +50,22 @@ func ExibirStatusAtual(status *Status) {
 	fmt.Printf("\nStatus atual: %s\n", FormatStatus(status))
 }
 
-// DeterminarStatus determina o status com base no tipo de operação
-func DeterminarStatus(operacao clockin.TipoOperacao, localizacao string) Status {
+// DeterminarStatus determina o status com base no tipo de operação e, na entrada, na
+// modalidade da localização segundo o catálogo (nil usa clockin.CatalogoPadrao)
+func DeterminarStatus(operacao clockin.TipoOperacao, localizacao string, catalogo *clockin.Catalogo) Status {
 	switch operacao {
 	case clockin.Entrada:
-		if localizacao == "Home Office" {
+		conhecida, ok := catalogo.Buscar(localizacao)
+		if !ok {
+			if candidatas := catalogo.Candidatas(localizacao); len(candidatas) > 0 {
+				fmt.Printf("\n⚠️  Aviso: Localização %q é parecida com %s no catálogo (ponto.localizacoes); usando status presencial\n",
+					localizacao, strings.Join(candidatas, " e "))
+			} else if localizacao != "" {
+				fmt.Printf("\n⚠️  Aviso: Localização %q não está no catálogo (ponto.localizacoes); usando status presencial\n", localizacao)
+			}
+			return StatusTrabalhoPresencial
+		}
+		if conhecida.Remota() {
 			return StatusHomeOffice
 		}
 		return StatusTrabalhoPresencial
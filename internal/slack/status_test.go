@@ -0,0 +1,51 @@
+package slack
+
+import (
+	"testing"
+
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+)
+
+func TestDeterminarStatus(t *testing.T) {
+	catalogo, err := clockin.NovoCatalogo([]clockin.LocalizacaoConhecida{
+		{Nome: "Escritório SP"},
+		{Nome: "Escritório RJ"},
+		{Nome: "Coworking", Modalidade: clockin.ModalidadeRemota},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	casos := []struct {
+		operacao    clockin.TipoOperacao
+		localizacao string
+		esperado    Status
+	}{
+		{clockin.Entrada, "Home Office", StatusHomeOffice},
+		{clockin.Entrada, "HOME OFFICE", StatusHomeOffice},
+		{clockin.Entrada, "Home  Office ", StatusHomeOffice},
+		{clockin.Entrada, "Teletrabalho", StatusHomeOffice},
+		{clockin.Entrada, "Home Ofice", StatusHomeOffice},
+		{clockin.Entrada, "coworking", StatusHomeOffice},
+		{clockin.Entrada, "Escritorio SP", StatusTrabalhoPresencial},
+		{clockin.Entrada, "Escritorio SJ", StatusTrabalhoPresencial},
+		{clockin.Entrada, "Cliente X", StatusTrabalhoPresencial},
+		{clockin.Entrada, "", StatusTrabalhoPresencial},
+		{clockin.Almoco, "Home Office", StatusAlmoco},
+		{clockin.Saida, "Home Office", StatusFimExpediente},
+	}
+
+	for _, caso := range casos {
+		t.Run(caso.operacao.String()+"/"+caso.localizacao, func(t *testing.T) {
+			if obtido := DeterminarStatus(caso.operacao, caso.localizacao, catalogo); obtido != caso.esperado {
+				t.Errorf("DeterminarStatus(%s, %q) = %+v, esperado %+v", caso.operacao, caso.localizacao, obtido, caso.esperado)
+			}
+		})
+	}
+}
+
+func TestDeterminarStatusCatalogoPadrao(t *testing.T) {
+	if obtido := DeterminarStatus(clockin.Entrada, " home office", nil); obtido != StatusHomeOffice {
+		t.Fatalf("DeterminarStatus com catálogo nil = %+v, esperado %+v", obtido, StatusHomeOffice)
+	}
+}
@@ -0,0 +1,79 @@
+package slackfake
+
+import "time"
+
+// Cenario controla o comportamento do servidor falso
+type Cenario struct {
+	// Token é o token de usuário (xoxp) aceito sem cookie
+	Token string
+
+	// TokenSessao é o token xoxc embutido na página do workspace; só é aceito com o cookie d
+	TokenSessao string
+
+	// CookieD é o valor do cookie d da sessão web
+	CookieD string
+
+	// Usuario é o ID do usuário autenticado
+	Usuario string
+
+	// Canais são as conversas listadas por conversations.list, em que chat.postMessage é
+	// aceito além das DMs abertas com conversations.open
+	Canais []Canal
+
+	// Usuarios são os membros listados por users.list
+	Usuarios []Usuario
+
+	// TamanhoPagina limita os itens de cada página das listagens; zero devolve tudo
+	TamanhoPagina int
+
+	// LimitarTaxa é o número de chamadas respondidas com 429 antes de serem atendidas
+	LimitarTaxa int
+
+	// EsperaLimite é o Retry-After das respostas 429
+	EsperaLimite time.Duration
+}
+
+// CenarioPadrao retorna um cenário com os tokens "xoxp-fake" e "xoxc-fake", o cookie d
+// "xoxd-fake", a conversa usada pelo batedor, o canal #equipe e dois usuários
+func CenarioPadrao() Cenario {
+	return Cenario{
+		Token:       "xoxp-fake",
+		TokenSessao: "xoxc-fake",
+		CookieD:     "xoxd-fake",
+		Usuario:     "U0000000001",
+		Canais: []Canal{
+			{ID: "C010LNL7KS9", Nome: "batedor"},
+			{ID: "C0000000002", Nome: "equipe"},
+		},
+		Usuarios: []Usuario{
+			{ID: "U0000000001", Nome: "eu", NomeReal: "Eu Mesmo"},
+			{ID: "U0000000002", Nome: "gestor", NomeReal: "Gestor da Equipe"},
+		},
+	}
+}
+
+// Canal é uma conversa do workspace
+type Canal struct {
+	ID   string
+	Nome string
+}
+
+// Usuario é um membro do workspace
+type Usuario struct {
+	ID       string
+	Nome     string
+	NomeReal string
+}
+
+// Mensagem é uma mensagem recebida por chat.postMessage
+type Mensagem struct {
+	Canal   string
+	Texto   string
+	Momento time.Time
+}
+
+// Status é o status do perfil do usuário
+type Status struct {
+	Texto string
+	Emoji string
+}
@@ -0,0 +1,238 @@
+// Package slackfake implementa um servidor HTTP que imita a página do workspace e os métodos
+// da Web API do Slack usados pelo backend "api" (auth.test, users.profile.get/set,
+// conversations.list/open, users.list e chat.postMessage), permitindo exercitá-lo sem
+// acessar o Slack.
+package slackfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Servidor é o Slack falso. Pode ser usado como http.Handler ou iniciado com Iniciar.
+type Servidor struct {
+	mu        sync.Mutex
+	cenario   Cenario
+	status    Status
+	mensagens []Mensagem
+	dms       map[string]string
+	chamadas  map[string]int
+	limitar   int
+	teste     *httptest.Server
+}
+
+// Novo cria o servidor com o cenário informado, sem abrir porta
+func Novo(cenario Cenario) *Servidor {
+	return &Servidor{
+		cenario:  cenario,
+		dms:      make(map[string]string),
+		chamadas: make(map[string]int),
+		limitar:  cenario.LimitarTaxa,
+	}
+}
+
+// Iniciar cria o servidor e o coloca para escutar em uma porta local aleatória
+func Iniciar(cenario Cenario) *Servidor {
+	s := Novo(cenario)
+	s.teste = httptest.NewServer(s)
+	return s
+}
+
+// URL retorna o endereço do servidor iniciado com Iniciar
+func (s *Servidor) URL() string {
+	if s.teste == nil {
+		return ""
+	}
+	return s.teste.URL
+}
+
+// Close encerra o servidor iniciado com Iniciar
+func (s *Servidor) Close() {
+	if s.teste != nil {
+		s.teste.Close()
+	}
+}
+
+// Mensagens retorna as mensagens aceitas
+func (s *Servidor) Mensagens() []Mensagem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.mensagens)
+}
+
+// Chamadas retorna quantas vezes o método da API foi chamado
+func (s *Servidor) Chamadas(metodo string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.chamadas[metodo]
+}
+
+// Status retorna o status atual do perfil
+func (s *Servidor) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *Servidor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/":
+		s.workspace(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/"):
+		s.api(w, r, strings.TrimPrefix(r.URL.Path, "/api/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// workspace devolve a página do cliente web com o token xoxc quando o cookie d é válido
+func (s *Servidor) workspace(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	cenario := s.cenario
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	cookie, err := r.Cookie("d")
+	if err != nil || cookie.Value != cenario.CookieD {
+		fmt.Fprint(w, `<html><body><form id="signin_form"></form></body></html>`)
+		return
+	}
+	fmt.Fprintf(w, `<html><script>var boot_data = {"team_id":"T0000000001","api_token":%q};</script></html>`, cenario.TokenSessao)
+}
+
+// autenticado aceita o token de usuário ou o token da sessão acompanhado do cookie d
+func (s *Servidor) autenticado(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.PostForm.Get("token")
+	}
+	if token == "" {
+		return false
+	}
+	if token == s.cenario.Token {
+		return true
+	}
+	cookie, err := r.Cookie("d")
+	return token == s.cenario.TokenSessao && err == nil && cookie.Value == s.cenario.CookieD
+}
+
+func (s *Servidor) api(w http.ResponseWriter, r *http.Request, metodo string) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chamadas[metodo]++
+	if s.limitar > 0 {
+		s.limitar--
+		w.Header().Set("Retry-After", strconv.Itoa(int(s.cenario.EsperaLimite.Seconds())))
+		w.WriteHeader(http.StatusTooManyRequests)
+		responder(w, map[string]any{"ok": false, "error": "ratelimited"})
+		return
+	}
+	if !s.autenticado(r) {
+		responder(w, map[string]any{"ok": false, "error": "invalid_auth"})
+		return
+	}
+
+	switch metodo {
+	case "auth.test":
+		responder(w, map[string]any{"ok": true, "user_id": s.cenario.Usuario, "team_id": "T0000000001"})
+	case "users.profile.get":
+		responder(w, map[string]any{"ok": true, "profile": s.perfil()})
+	case "users.profile.set":
+		var perfil struct {
+			StatusText  *string `json:"status_text"`
+			StatusEmoji *string `json:"status_emoji"`
+		}
+		if err := json.Unmarshal([]byte(r.PostForm.Get("profile")), &perfil); err != nil {
+			responder(w, map[string]any{"ok": false, "error": "invalid_profile"})
+			return
+		}
+		if perfil.StatusText != nil {
+			s.status.Texto = *perfil.StatusText
+		}
+		if perfil.StatusEmoji != nil {
+			s.status.Emoji = *perfil.StatusEmoji
+		}
+		responder(w, map[string]any{"ok": true, "profile": s.perfil()})
+	case "conversations.open":
+		usuario := r.PostForm.Get("users")
+		if !strings.HasPrefix(usuario, "U") && !strings.HasPrefix(usuario, "W") {
+			responder(w, map[string]any{"ok": false, "error": "user_not_found"})
+			return
+		}
+		canal := "D" + strings.TrimLeft(usuario, "UW")
+		s.dms[canal] = usuario
+		responder(w, map[string]any{"ok": true, "channel": map[string]any{"id": canal}})
+	case "conversations.list":
+		var canais []map[string]any
+		for _, canal := range s.cenario.Canais {
+			canais = append(canais, map[string]any{"id": canal.ID, "name": canal.Nome})
+		}
+		pagina, cursor := s.paginar(len(canais), r.PostForm.Get("cursor"))
+		responder(w, map[string]any{"ok": true, "channels": canais[pagina[0]:pagina[1]], "response_metadata": map[string]any{"next_cursor": cursor}})
+	case "users.list":
+		var membros []map[string]any
+		for _, usuario := range s.cenario.Usuarios {
+			membros = append(membros, map[string]any{
+				"id":      usuario.ID,
+				"name":    usuario.Nome,
+				"profile": map[string]any{"real_name": usuario.NomeReal, "display_name": usuario.Nome},
+			})
+		}
+		pagina, cursor := s.paginar(len(membros), r.PostForm.Get("cursor"))
+		responder(w, map[string]any{"ok": true, "members": membros[pagina[0]:pagina[1]], "response_metadata": map[string]any{"next_cursor": cursor}})
+	case "chat.postMessage":
+		canal, texto := r.PostForm.Get("channel"), r.PostForm.Get("text")
+		existe := slices.ContainsFunc(s.cenario.Canais, func(c Canal) bool { return c.ID == canal })
+		if _, dm := s.dms[canal]; !dm && !existe {
+			responder(w, map[string]any{"ok": false, "error": "channel_not_found"})
+			return
+		}
+		if texto == "" {
+			responder(w, map[string]any{"ok": false, "error": "no_text"})
+			return
+		}
+		momento := time.Now()
+		s.mensagens = append(s.mensagens, Mensagem{Canal: canal, Texto: texto, Momento: momento})
+		responder(w, map[string]any{"ok": true, "channel": canal, "ts": fmt.Sprintf("%d.000100", momento.Unix())})
+	default:
+		responder(w, map[string]any{"ok": false, "error": "unknown_method"})
+	}
+}
+
+// paginar retorna o intervalo [início, fim) da página que começa no cursor e o cursor da
+// página seguinte, vazio na última
+func (s *Servidor) paginar(total int, cursor string) ([2]int, string) {
+	inicio, _ := strconv.Atoi(cursor)
+	inicio = min(max(inicio, 0), total)
+	fim := total
+	if s.cenario.TamanhoPagina > 0 {
+		fim = min(inicio+s.cenario.TamanhoPagina, total)
+	}
+	if fim == total {
+		return [2]int{inicio, fim}, ""
+	}
+	return [2]int{inicio, fim}, strconv.Itoa(fim)
+}
+
+// perfil monta a resposta de users.profile; deve ser chamado com s.mu travado
+func (s *Servidor) perfil() map[string]any {
+	return map[string]any{"status_text": s.status.Texto, "status_emoji": s.status.Emoji}
+}
+
+func responder(w http.ResponseWriter, corpo map[string]any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(corpo)
+}
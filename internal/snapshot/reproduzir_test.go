@@ -0,0 +1,100 @@
+package snapshot_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/common"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/selectors"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/slack"
+	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/snapshot"
+)
+
+// exigirChromium pula o teste quando não há Chromium instalado ou com -short
+func exigirChromium(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("teste com navegador ignorado em -short")
+	}
+	for _, nome := range []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless-shell", "headless_shell"} {
+		if _, err := exec.LookPath(nome); err == nil {
+			return
+		}
+	}
+	t.Skip("Chromium não instalado")
+}
+
+// extrair executa na página carregada em ctx o extrator correspondente ao snapshot
+func extrair(ctx context.Context, pagina string, mapa *selectors.Mapa) (any, error) {
+	switch pagina {
+	case snapshot.PaginaLocalizacaoAtual:
+		return clockin.ExtrairLocalizacaoAtual(ctx, mapa.Softtrade)
+	case snapshot.PaginaLocalizacoes:
+		return clockin.ExtrairLocalizacoes(ctx, mapa.Softtrade)
+	case snapshot.PaginaOperacoes:
+		return clockin.ExtrairOperacoes(ctx, mapa.Softtrade)
+	default:
+		return slack.ExtrairStatus(ctx, mapa.Slack)
+	}
+}
+
+// TestReproduzirSnapshots executa os extratores com os seletores padrão sobre os
+// snapshots sanitizados de testdata
+func TestReproduzirSnapshots(t *testing.T) {
+	exigirChromium(t)
+
+	snapshots, err := snapshot.Listar("testdata/snapshots")
+	if err != nil {
+		t.Fatalf("Listar: %v", err)
+	}
+	paginas := map[string]bool{}
+	for _, s := range snapshots {
+		paginas[s.Pagina] = true
+	}
+	for _, pagina := range []string{snapshot.PaginaLocalizacaoAtual, snapshot.PaginaLocalizacoes, snapshot.PaginaOperacoes, snapshot.PaginaStatusSlack} {
+		if !paginas[pagina] {
+			t.Errorf("nenhum snapshot de %s em testdata", pagina)
+		}
+	}
+
+	servidor := httptest.NewServer(snapshot.NovoServidor("testdata/snapshots"))
+	defer servidor.Close()
+
+	navegador := common.NovoGerenciadorNavegador(true)
+	defer navegador.Close()
+
+	mapa := selectors.Padrao()
+	for _, s := range snapshots {
+		t.Run(s.Caminho, func(t *testing.T) {
+			aba, fecharAba, err := navegador.NovaAba()
+			if err != nil {
+				t.Fatalf("NovaAba: %v", err)
+			}
+			defer fecharAba()
+
+			ctx, cancelar := context.WithTimeout(aba, 20*time.Second)
+			defer cancelar()
+
+			if err := chromedp.Run(ctx, chromedp.Navigate(servidor.URL+"/"+s.Caminho), chromedp.WaitReady("body")); err != nil {
+				t.Fatalf("carregar snapshot: %v", err)
+			}
+
+			obtido, err := extrair(ctx, s.Pagina, mapa)
+			if err != nil {
+				t.Fatalf("extrair: %v", err)
+			}
+			igual, atual, err := s.Comparar(obtido)
+			if err != nil {
+				t.Fatalf("Comparar: %v", err)
+			}
+			if !igual {
+				t.Errorf("esperado %s, obtido %s", s.Esperado, atual)
+			}
+		})
+	}
+}
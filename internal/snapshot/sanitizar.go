@@ -0,0 +1,189 @@
+package snapshot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// MarcaOculta substitui nos snapshots os textos ocultados
+const MarcaOculta = "[oculto]"
+
+// elementosRemovidos não são necessários para os extratores e podem executar código,
+// carregar recursos externos ou conter dados da sessão
+var elementosRemovidos = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Noscript: true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Link:     true,
+	atom.Base:     true,
+	atom.Meta:     true,
+}
+
+// atributosURL podem carregar o id da sessão (;jsessionid=) ou tokens na consulta
+var atributosURL = map[string]bool{"action": true, "href": true, "formaction": true}
+
+// atributoToken identifica atributos data-* que guardam tokens ou dados da sessão
+var atributoToken = regexp.MustCompile(`(?i)^data-.*(token|csrf|xsrf|sess|auth|nonce|secret|key|sig)`)
+
+// padroesPessoais são ocultados em todo snapshot: e-mails, CPFs e tokens do Slack
+var padroesPessoais = []*regexp.Regexp{
+	regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`),
+	regexp.MustCompile(`\b\d{3}\.\d{3}\.\d{3}-\d{2}\b`),
+	regexp.MustCompile(`\bxox[a-z]-[\w-]+`),
+}
+
+// Ocultacao substitui por MarcaOculta os textos que identificam o usuário, como o nome e
+// a matrícula exibidos no cabeçalho do Softtrade. Um *Ocultacao nil oculta apenas os
+// padrões pessoais comuns.
+type Ocultacao struct {
+	padroes []*regexp.Regexp
+}
+
+// NovaOcultacao cria a ocultação dos textos literais, comparados sem diferenciar
+// maiúsculas, e das expressões regulares informadas
+func NovaOcultacao(textos, padroes []string) (*Ocultacao, error) {
+	o := &Ocultacao{}
+	for _, texto := range textos {
+		if texto = strings.TrimSpace(texto); texto != "" {
+			o.padroes = append(o.padroes, regexp.MustCompile(`(?i)`+regexp.QuoteMeta(texto)))
+		}
+	}
+	for _, padrao := range padroes {
+		re, err := regexp.Compile(padrao)
+		if err != nil {
+			return nil, fmt.Errorf("padrão de ocultação inválido %q: %w", padrao, err)
+		}
+		o.padroes = append(o.padroes, re)
+	}
+	return o, nil
+}
+
+// Aplicar retorna o texto com os trechos ocultados substituídos por MarcaOculta
+func (o *Ocultacao) Aplicar(texto string) string {
+	for _, re := range padroesPessoais {
+		texto = re.ReplaceAllLiteralString(texto, MarcaOculta)
+	}
+	if o == nil {
+		return texto
+	}
+	for _, re := range o.padroes {
+		texto = re.ReplaceAllLiteralString(texto, MarcaOculta)
+	}
+	return texto
+}
+
+// aplicarValor oculta os textos de um valor JSON decodificado
+func (o *Ocultacao) aplicarValor(valor any) any {
+	switch v := valor.(type) {
+	case string:
+		return o.Aplicar(v)
+	case []any:
+		for i := range v {
+			v[i] = o.aplicarValor(v[i])
+		}
+	case map[string]any:
+		for chave := range v {
+			v[chave] = o.aplicarValor(v[chave])
+		}
+	}
+	return valor
+}
+
+// Sanitizar remove scripts, manipuladores de eventos, recursos externos, campos ocultos
+// (ViewState), atributos com tokens, parâmetros de URLs e valores de campos de formulário
+// do HTML, e oculta os textos pessoais, mantendo a estrutura e os textos usados pelos extratores
+func Sanitizar(conteudo string, ocultacao *Ocultacao) (string, error) {
+	raiz, err := html.Parse(strings.NewReader(conteudo))
+	if err != nil {
+		return "", fmt.Errorf("erro ao interpretar HTML: %w", err)
+	}
+
+	sanitizarNo(raiz, ocultacao)
+
+	var b strings.Builder
+	for c := raiz.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.DoctypeNode {
+			continue
+		}
+		if err := html.Render(&b, c); err != nil {
+			return "", fmt.Errorf("erro ao gerar HTML: %w", err)
+		}
+	}
+	return b.String(), nil
+}
+
+func sanitizarNo(n *html.Node, ocultacao *Ocultacao) {
+	for c := n.FirstChild; c != nil; {
+		proximo := c.NextSibling
+		if removerNo(c) {
+			n.RemoveChild(c)
+		} else {
+			sanitizarNo(c, ocultacao)
+		}
+		c = proximo
+	}
+
+	if n.Type == html.TextNode {
+		n.Data = ocultacao.Aplicar(n.Data)
+		return
+	}
+	if n.Type != html.ElementNode {
+		return
+	}
+
+	tipo := tipoCampo(n)
+	atributos := n.Attr[:0]
+	for _, attr := range n.Attr {
+		switch {
+		case strings.HasPrefix(attr.Key, "on"):
+			continue
+		case attr.Key == "src" || attr.Key == "srcset":
+			continue
+		case atributoToken.MatchString(attr.Key):
+			continue
+		case atributosURL[attr.Key] && strings.HasPrefix(strings.ToLower(strings.TrimSpace(attr.Val)), "javascript:"):
+			continue
+		case atributosURL[attr.Key]:
+			attr.Val = removerConsulta(attr.Val)
+		case attr.Key == "value" && n.DataAtom == atom.Input && tipo != "button" && tipo != "submit":
+			attr.Val = ""
+		}
+		attr.Val = ocultacao.Aplicar(attr.Val)
+		atributos = append(atributos, attr)
+	}
+	n.Attr = atributos
+
+	if n.DataAtom == atom.Textarea {
+		for c := n.FirstChild; c != nil; c = n.FirstChild {
+			n.RemoveChild(c)
+		}
+	}
+}
+
+// removerNo indica se o nó deve ser descartado: comentários, elementos perigosos e campos
+// ocultos, que guardam o ViewState e tokens da sessão
+func removerNo(n *html.Node) bool {
+	if n.Type == html.CommentNode {
+		return true
+	}
+	if n.Type != html.ElementNode {
+		return false
+	}
+	return elementosRemovidos[n.DataAtom] || (n.DataAtom == atom.Input && tipoCampo(n) == "hidden")
+}
+
+// tipoCampo retorna o atributo type do elemento em minúsculas
+func tipoCampo(n *html.Node) string {
+	for _, attr := range n.Attr {
+		if attr.Key == "type" {
+			return strings.ToLower(attr.Val)
+		}
+	}
+	return ""
+}
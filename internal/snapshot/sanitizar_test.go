@@ -0,0 +1,95 @@
+package snapshot
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// sanitizarArquivo sanitiza uma página de testdata com o nome e a matrícula do colaborador
+// fictício ocultados
+func sanitizarArquivo(t *testing.T, arquivo string) string {
+	t.Helper()
+	conteudo, err := os.ReadFile("testdata/" + arquivo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ocultacao, err := NovaOcultacao([]string{"Maria Fictícia da Silva", "004217"}, nil)
+	if err != nil {
+		t.Fatalf("NovaOcultacao: %v", err)
+	}
+	sanitizado, err := Sanitizar(string(conteudo), ocultacao)
+	if err != nil {
+		t.Fatalf("Sanitizar: %v", err)
+	}
+	return sanitizado
+}
+
+func TestSanitizarSofttrade(t *testing.T) {
+	sanitizado := sanitizarArquivo(t, "softtrade-marcacao.html")
+
+	removidos := []string{
+		"jsessionid", "A1B2C3D4E5F6", "cid=7", "token=", // sessão nas URLs e atributos
+		"javax.faces.ViewState", "-2938475610293847561", // ViewState
+		"c0ffee00", "eyJhbGciOi", "4f9d2c1e", // atributos e metas com tokens
+		"Maria Fictícia", "004217", "maria.ficticia@", "123.456.789-09", // dados pessoais
+		"Consulta médica", "Atraso no metrô", // valores de campos
+		"PrimeFaces", "analytics", "mfsilva", // scripts, iframes e comentários
+	}
+	for _, trecho := range removidos {
+		if strings.Contains(sanitizado, trecho) {
+			t.Errorf("snapshot sanitizado contém %q", trecho)
+		}
+	}
+
+	mantidos := []string{
+		`action="/softtrade/pages/marcacao.xhtml"`,
+		`<span class="loc-text">Escritório SP</span>`,
+		`data-rk="3"`,
+		"Saída refeição/descanso",
+		"Matrícula: " + MarcaOculta,
+	}
+	for _, trecho := range mantidos {
+		if !strings.Contains(sanitizado, trecho) {
+			t.Errorf("snapshot sanitizado não contém %q", trecho)
+		}
+	}
+}
+
+func TestSanitizarSlack(t *testing.T) {
+	sanitizado := sanitizarArquivo(t, "slack-status.html")
+
+	for _, trecho := range []string{"xoxc-", "crumb", "boot_data", "slack-edge.com", "Maria Fictícia", "maria.ficticia@"} {
+		if strings.Contains(sanitizado, trecho) {
+			t.Errorf("snapshot sanitizado contém %q", trecho)
+		}
+	}
+	for _, trecho := range []string{`data-qa="custom_status_input_body"`, `alt=":office:"`, "No escritório até 18h"} {
+		if !strings.Contains(sanitizado, trecho) {
+			t.Errorf("snapshot sanitizado não contém %q", trecho)
+		}
+	}
+}
+
+func TestOcultacao(t *testing.T) {
+	ocultacao, err := NovaOcultacao([]string{" Maria Fictícia ", ""}, []string{`\bMAT-\d+\b`})
+	if err != nil {
+		t.Fatalf("NovaOcultacao: %v", err)
+	}
+
+	casos := []struct{ texto, esperado string }{
+		{"Olá, MARIA FICTÍCIA", "Olá, " + MarcaOculta},
+		{"Registro MAT-1234 ativo", "Registro " + MarcaOculta + " ativo"},
+		{"contato: ana@empresa.example", "contato: " + MarcaOculta},
+		{"Escritório SP", "Escritório SP"},
+	}
+	for _, caso := range casos {
+		if obtido := ocultacao.Aplicar(caso.texto); obtido != caso.esperado {
+			t.Errorf("Aplicar(%q) = %q, esperado %q", caso.texto, obtido, caso.esperado)
+		}
+	}
+
+	if _, err := NovaOcultacao(nil, []string{"("}); err == nil {
+		t.Error("NovaOcultacao com expressão inválida não falhou")
+	}
+}
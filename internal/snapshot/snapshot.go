@@ -0,0 +1,205 @@
+// Package snapshot grava cópias sanitizadas das páginas do Softtrade e do Slack junto com
+// o resultado extraído delas, e as serve novamente para verificar os extratores quando a
+// marcação das páginas mudar.
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Páginas gravadas, uma por extrator
+const (
+	PaginaLocalizacaoAtual = "softtrade-localizacao-atual"
+	PaginaLocalizacoes     = "softtrade-localizacoes"
+	PaginaOperacoes        = "softtrade-operacoes"
+	PaginaStatusSlack      = "slack-status"
+)
+
+const formatoArquivo = "20060102-150405"
+
+// Metadados acompanha cada snapshot em um arquivo .json com o mesmo nome do .html
+type Metadados struct {
+	Pagina  string    `json:"pagina"`
+	Momento time.Time `json:"momento"`
+	URL     string    `json:"url"`
+	// Esperado é o resultado que o extrator produziu na página real no momento da gravação
+	Esperado json.RawMessage `json:"esperado"`
+}
+
+// Snapshot identifica um snapshot gravado
+type Snapshot struct {
+	Metadados
+	// Caminho é o caminho do arquivo HTML relativo ao diretório de snapshots
+	Caminho string
+}
+
+// Gravador salva snapshots em um diretório. Um *Gravador nil não grava nada,
+// o que permite chamá-lo sem verificar se a gravação está habilitada.
+type Gravador struct {
+	mu        sync.Mutex
+	diretorio string
+	ocultacao *Ocultacao
+}
+
+// NovoGravador cria um gravador que salva em diretorio, ocultando os textos de ocultacao
+// no HTML e no resultado esperado
+func NovoGravador(diretorio string, ocultacao *Ocultacao) *Gravador {
+	return &Gravador{diretorio: diretorio, ocultacao: ocultacao}
+}
+
+// Gravar salva o HTML sanitizado da página carregada em ctx e o resultado extraído dela.
+// Falhas de gravação não devem interromper a operação principal; quem chama decide se as registra.
+func (g *Gravador) Gravar(ctx context.Context, pagina string, resultado any) error {
+	if g == nil {
+		return nil
+	}
+
+	var conteudo, url string
+	if err := chromedp.Run(ctx,
+		chromedp.Location(&url),
+		chromedp.OuterHTML("html", &conteudo, chromedp.ByQuery),
+	); err != nil {
+		return fmt.Errorf("erro ao capturar página %s: %w", pagina, err)
+	}
+
+	sanitizado, err := Sanitizar(conteudo, g.ocultacao)
+	if err != nil {
+		return err
+	}
+
+	esperado, err := g.serializarResultado(resultado)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar resultado de %s: %w", pagina, err)
+	}
+
+	momento := time.Now()
+	metadados, err := json.MarshalIndent(Metadados{
+		Pagina:   pagina,
+		Momento:  momento,
+		URL:      removerConsulta(url),
+		Esperado: esperado,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("erro ao serializar metadados de %s: %w", pagina, err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	dir := filepath.Join(g.diretorio, pagina)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("erro ao criar diretório de snapshots: %w", err)
+	}
+
+	base := filepath.Join(dir, momento.Format(formatoArquivo))
+	if err := os.WriteFile(base+".html", []byte("<!DOCTYPE html>\n"+sanitizado), 0600); err != nil {
+		return fmt.Errorf("erro ao gravar snapshot: %w", err)
+	}
+	if err := os.WriteFile(base+".json", metadados, 0600); err != nil {
+		return fmt.Errorf("erro ao gravar snapshot: %w", err)
+	}
+	return nil
+}
+
+// serializarResultado converte o resultado em JSON com os mesmos textos ocultados do HTML,
+// para que a reprodução sobre o snapshot sanitizado o encontre igual
+func (g *Gravador) serializarResultado(resultado any) (json.RawMessage, error) {
+	dados, err := json.Marshal(resultado)
+	if err != nil {
+		return nil, err
+	}
+	var valor any
+	if err := json.Unmarshal(dados, &valor); err != nil {
+		return nil, err
+	}
+	return json.Marshal(g.ocultacao.aplicarValor(valor))
+}
+
+// removerConsulta descarta os parâmetros de caminho (;jsessionid=), a query string e o
+// fragmento, que podem conter o id da sessão ou tokens
+func removerConsulta(url string) string {
+	if i := strings.IndexAny(url, ";?#"); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
+
+// Listar retorna os snapshots do diretório, ordenados por página e momento
+func Listar(diretorio string) ([]Snapshot, error) {
+	arquivos, err := filepath.Glob(filepath.Join(diretorio, "*", "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar snapshots: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, arquivo := range arquivos {
+		dados, err := os.ReadFile(arquivo)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler %s: %w", arquivo, err)
+		}
+
+		var metadados Metadados
+		if err := json.Unmarshal(dados, &metadados); err != nil {
+			return nil, fmt.Errorf("erro ao interpretar %s: %w", arquivo, err)
+		}
+
+		html := strings.TrimSuffix(arquivo, ".json") + ".html"
+		if _, err := os.Stat(html); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("erro ao acessar %s: %w", html, err)
+		}
+
+		relativo, err := filepath.Rel(diretorio, html)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, Snapshot{Metadados: metadados, Caminho: filepath.ToSlash(relativo)})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Pagina != snapshots[j].Pagina {
+			return snapshots[i].Pagina < snapshots[j].Pagina
+		}
+		return snapshots[i].Momento.Before(snapshots[j].Momento)
+	})
+	return snapshots, nil
+}
+
+// NovoServidor retorna um http.Handler que serve os snapshots do diretório,
+// para serem carregados no navegador pelo caminho de Snapshot.Caminho
+func NovoServidor(diretorio string) http.Handler {
+	return http.FileServer(http.Dir(diretorio))
+}
+
+// Comparar verifica se o resultado obtido na reprodução equivale ao esperado
+// e retorna o resultado obtido serializado
+func (s Snapshot) Comparar(obtido any) (bool, string, error) {
+	dados, err := json.Marshal(obtido)
+	if err != nil {
+		return false, "", fmt.Errorf("erro ao serializar resultado: %w", err)
+	}
+
+	var esperado, atual any
+	if err := json.Unmarshal(s.Esperado, &esperado); err != nil {
+		return false, "", fmt.Errorf("resultado esperado inválido em %s: %w", s.Caminho, err)
+	}
+	if err := json.Unmarshal(dados, &atual); err != nil {
+		return false, "", err
+	}
+	return reflect.DeepEqual(esperado, atual), string(dados), nil
+}
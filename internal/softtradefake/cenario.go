@@ -0,0 +1,60 @@
+package softtradefake
+
+import "time"
+
+// Nomes das marcações registradas pelo servidor, iguais aos rótulos dos botões
+const (
+	Entrada = "Entrada"
+	Almoco  = "Saída refeição/descanso"
+	Saida   = "Saída"
+)
+
+// Cenario controla o comportamento do servidor falso
+type Cenario struct {
+	// Usuario e Senha são as únicas credenciais aceitas
+	Usuario string
+	Senha   string
+
+	// MensagemErroLogin é exibida quando as credenciais são recusadas
+	MensagemErroLogin string
+
+	// Localizacoes são as linhas da tabela de localizações
+	Localizacoes []string
+
+	// LocalizacaoInicial é a localização selecionada após o login; vazio exige seleção
+	// antes que os botões de marcação sejam exibidos
+	LocalizacaoInicial string
+
+	// ModalIntervalo exibe o diálogo "Intervalo Opcional" na Entrada após a saída para refeição
+	ModalIntervalo bool
+
+	// AtrasoAjax mantém o bloqueio AJAX visível por este tempo em cada requisição parcial
+	AtrasoAjax time.Duration
+
+	// FalhasMarcacao é o número de marcações que falham com erro 500 antes de serem aceitas
+	FalhasMarcacao int
+
+	// ExpirarView responde ViewExpiredException à primeira requisição parcial de cada sessão
+	ExpirarView bool
+}
+
+// CenarioPadrao retorna um cenário com credenciais "usuario"/"senha", três localizações
+// e o modal de intervalo habilitado
+func CenarioPadrao() Cenario {
+	return Cenario{
+		Usuario:           "usuario",
+		Senha:             "senha",
+		MensagemErroLogin: "Usuário ou senha incorretos",
+		Localizacoes:      []string{"Home Office", "Escritório RJ", "Escritório SP"},
+		ModalIntervalo:    true,
+	}
+}
+
+// Marcacao é uma marcação recebida pelo servidor
+type Marcacao struct {
+	Operacao    string
+	Localizacao string
+	Momento     time.Time
+	// RespostaIntervalo é preenchida quando o modal "Intervalo Opcional" foi respondido
+	RespostaIntervalo *bool
+}
@@ -0,0 +1,172 @@
+package softtradefake
+
+import "html/template"
+
+// Identificadores dos elementos, iguais aos usados pelo Softtrade e pelo mapa de seletores
+const (
+	idFormulario  = "formMarc"
+	idTabela      = "formMarc:dtLoc"
+	idDialogo     = "dlgIntervalo"
+	idRespostaSim = "formDlg:sim"
+	idRespostaNao = "formDlg:nao"
+	idViewState   = "j_id1:javax.faces.ViewState:0"
+	nomeCookie    = "JSESSIONID"
+)
+
+// idsBotoes associa cada marcação ao id do seu botão
+var idsBotoes = map[string]string{
+	Entrada: "formMarc:btnEntrada",
+	Almoco:  "formMarc:btnRefeicao",
+	Saida:   "formMarc:btnSaida",
+}
+
+type linhaMarcacao struct {
+	Rotulo  string
+	Horario string
+}
+
+type botao struct {
+	ID     string
+	Rotulo string
+}
+
+type dadosLogin struct {
+	ViewState string
+	Erro      string
+}
+
+type dadosMarcacao struct {
+	ViewState    string
+	Localizacao  string
+	Localizacoes []string
+	Operacoes    []botao
+	Marcacoes    []linhaMarcacao
+	Modal        string
+}
+
+var paginas = template.Must(template.New("").Parse(`
+{{define "login"}}<!DOCTYPE html>
+<html>
+<head><title>Softtrade</title></head>
+<body>
+{{if .Erro}}<div class="ui-messages ui-messages-error"><span class="ui-messages-error-detail">{{.Erro}}</span></div>{{end}}
+<form id="loginForm" action="/login" method="post">
+<input id="username" name="loginForm:username" type="text">
+<input id="password" name="loginForm:password" type="password">
+<button id="verifqUsu" name="verifqUsu" type="submit">Entrar</button>
+<input type="hidden" name="javax.faces.ViewState" value="{{.ViewState}}">
+</form>
+</body>
+</html>{{end}}
+
+{{define "marcacao"}}<!DOCTYPE html>
+<html>
+<head><title>Softtrade - Marcação</title></head>
+<body>
+{{template "formulario" .}}
+{{template "dialogo" .}}
+<div id="j_idt113_blocker" class="ui-blockui" style="display: none"></div>
+<script src="/softtrade.js"></script>
+</body>
+</html>{{end}}
+
+{{define "formulario"}}<form id="formMarc" action="/marcacao" method="post">
+<button id="formMarc:btnLoc" type="button"><span class="loc-text">{{if .Localizacao}}{{.Localizacao}}{{else}}Selecione a localização{{end}}</span></button>
+<div id="painelLoc" style="display:none">
+<div id="formMarc:dtLoc" class="ui-datatable"><table><tbody>
+{{range $i, $nome := .Localizacoes}}<tr data-rk="{{$i}}"><td>{{$nome}}</td></tr>
+{{end}}</tbody></table></div>
+</div>
+<div class="botoes">
+{{range .Operacoes}}<button id="{{.ID}}" name="{{.ID}}" type="button">{{.Rotulo}}</button>
+{{end}}</div>
+<div id="formMarc:dtMarcacoes" class="ui-datatable"><table><thead><tr><th>Marcação</th><th>Horário</th></tr></thead><tbody>
+{{range .Marcacoes}}<tr><td>{{.Rotulo}}</td><td>{{.Horario}}</td></tr>
+{{else}}<tr class="ui-datatable-empty-message"><td colspan="2">Nenhuma marcação hoje</td></tr>
+{{end}}</tbody></table></div>
+<input type="hidden" name="javax.faces.ViewState" value="{{.ViewState}}">
+</form>{{end}}
+
+{{define "dialogo"}}<div id="dlgIntervalo" class="ui-dialog" role="dialog" {{if .Modal}}aria-hidden="false" style="display: block"{{else}}aria-hidden="true" style="display: none"{{end}}>
+<div class="ui-dialog-titlebar"><span class="ui-dialog-title">Intervalo Opcional</span></div>
+<div class="ui-dialog-content">{{.Modal}}</div>
+<form id="formDlg" action="/marcacao" method="post">
+<button id="formDlg:sim" name="formDlg:sim" type="button">Sim</button>
+<button id="formDlg:nao" name="formDlg:nao" type="button">Não</button>
+<input type="hidden" name="javax.faces.ViewState" value="{{.ViewState}}">
+</form>
+</div>{{end}}
+`))
+
+// script é servido em /softtrade.js e reproduz o mínimo do PrimeFaces necessário para a
+// página funcionar no navegador: envia as requisições parciais, exibe o bloqueio AJAX e
+// aplica as atualizações recebidas
+const script = `
+(function() {
+	function bloqueio(visivel) {
+		document.getElementById('j_idt113_blocker').style.cssText = visivel ? 'display: block' : 'display: none';
+	}
+
+	function enviar(form, fonte, extras) {
+		var dados = new URLSearchParams(new FormData(form));
+		dados.set(form.id, form.id);
+		dados.set('javax.faces.partial.ajax', 'true');
+		dados.set('javax.faces.source', fonte);
+		dados.set('javax.faces.partial.execute', fonte);
+		dados.set('javax.faces.partial.render', '@all');
+		for (var chave in extras) dados.set(chave, extras[chave]);
+
+		bloqueio(true);
+		fetch(form.action, {
+			method: 'POST',
+			credentials: 'same-origin',
+			headers: {'Faces-Request': 'partial/ajax', 'Content-Type': 'application/x-www-form-urlencoded'},
+			body: dados.toString()
+		}).then(function(r) { return r.text(); }).then(function(texto) {
+			var xml = new DOMParser().parseFromString(texto, 'text/xml');
+			var redirect = xml.querySelector('redirect');
+			if (redirect) {
+				window.location = redirect.getAttribute('url');
+				return;
+			}
+			xml.querySelectorAll('update').forEach(function(u) {
+				var id = u.getAttribute('id');
+				if (id.indexOf('javax.faces.ViewState') >= 0) {
+					document.querySelectorAll('input[name="javax.faces.ViewState"]').forEach(function(i) { i.value = u.textContent; });
+					return;
+				}
+				var alvo = document.getElementById(id);
+				if (alvo) alvo.outerHTML = u.textContent;
+			});
+		}).finally(function() { bloqueio(false); });
+	}
+
+	document.addEventListener('click', function(e) {
+		var celula = e.target.closest('[id="formMarc:dtLoc"] td');
+		if (celula) {
+			var rk = celula.parentNode.getAttribute('data-rk');
+			var extras = {
+				'javax.faces.behavior.event': 'rowSelect',
+				'javax.faces.partial.event': 'rowSelect'
+			};
+			extras['formMarc:dtLoc_instantSelection'] = rk;
+			extras['formMarc:dtLoc_selection'] = rk;
+			enviar(celula.closest('form'), 'formMarc:dtLoc', extras);
+			return;
+		}
+
+		var botao = e.target.closest('button');
+		if (!botao || !botao.closest('[id="formMarc"], [id="formDlg"]')) return;
+		e.preventDefault();
+
+		if (botao.id === 'formMarc:btnLoc') {
+			document.getElementById('painelLoc').style.display = 'block';
+			return;
+		}
+
+		var extras = {};
+		extras[botao.id] = botao.id;
+		enviar(botao.closest('form'), botao.id, extras);
+	});
+})();
+`
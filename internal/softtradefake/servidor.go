@@ -0,0 +1,352 @@
+// Package softtradefake implementa um servidor HTTP que imita as páginas do Softtrade usadas
+// pelo batedor (login, marcação, tabelas de localizações e de marcações do dia, bloqueio AJAX
+// e modal "Intervalo Opcional"), permitindo exercitar os módulos auth e clockin sem acessar
+// produção.
+package softtradefake
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type sessao struct {
+	viewState   int
+	localizacao string
+	marcacoes   []Marcacao
+	modal       string
+	expirada    bool
+
+	// encerrada faz a sessão deixar de ser reconhecida, mantendo suas marcações
+	encerrada bool
+}
+
+// Servidor é o Softtrade falso. Pode ser usado como http.Handler ou iniciado com Iniciar.
+type Servidor struct {
+	mu              sync.Mutex
+	cenario         Cenario
+	sessoes         map[string]*sessao
+	falhasRestantes int
+	logins          int
+	teste           *httptest.Server
+}
+
+// Novo cria o servidor com o cenário informado, sem abrir porta
+func Novo(cenario Cenario) *Servidor {
+	return &Servidor{
+		cenario:         cenario,
+		sessoes:         make(map[string]*sessao),
+		falhasRestantes: cenario.FalhasMarcacao,
+	}
+}
+
+// Iniciar cria o servidor e o coloca para escutar em uma porta local aleatória
+func Iniciar(cenario Cenario) *Servidor {
+	s := Novo(cenario)
+	s.teste = httptest.NewServer(s)
+	return s
+}
+
+// URL retorna o endereço do servidor iniciado com Iniciar
+func (s *Servidor) URL() string {
+	if s.teste == nil {
+		return ""
+	}
+	return s.teste.URL
+}
+
+// Close encerra o servidor iniciado com Iniciar
+func (s *Servidor) Close() {
+	if s.teste != nil {
+		s.teste.Close()
+	}
+}
+
+// DefinirCenario troca o cenário; sessões abertas são mantidas
+func (s *Servidor) DefinirCenario(cenario Cenario) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cenario = cenario
+	s.falhasRestantes = cenario.FalhasMarcacao
+}
+
+// ExpirarSessoes encerra todas as sessões abertas, como o Softtrade faz após o tempo de
+// inatividade; as próximas requisições voltam à página de login
+func (s *Servidor) ExpirarSessoes() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sessao := range s.sessoes {
+		sessao.encerrada = true
+	}
+}
+
+// Logins retorna o número de logins aceitos
+func (s *Servidor) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// Marcacoes retorna as marcações aceitas em todas as sessões
+func (s *Servidor) Marcacoes() []Marcacao {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var marcacoes []Marcacao
+	for _, sessao := range s.sessoes {
+		marcacoes = append(marcacoes, sessao.marcacoes...)
+	}
+	return marcacoes
+}
+
+func (s *Servidor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/softtrade.js":
+		w.Header().Set("Content-Type", "application/javascript")
+		fmt.Fprint(w, script)
+	case r.URL.Path == "/login" && r.Method == http.MethodPost:
+		s.login(w, r)
+	case r.URL.Path == "/marcacao" && r.Method == http.MethodPost:
+		s.ajax(w, r)
+	case r.URL.Path == "/marcacao":
+		s.marcacao(w, r)
+	case r.URL.Path == "/":
+		if s.sessaoDe(r) != nil {
+			http.Redirect(w, r, "/marcacao", http.StatusFound)
+			return
+		}
+		s.renderizar(w, "login", dadosLogin{ViewState: "login"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Servidor) sessaoDe(r *http.Request) *sessao {
+	cookie, err := r.Cookie(nomeCookie)
+	if err != nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessao := s.sessoes[cookie.Value]
+	if sessao == nil || sessao.encerrada {
+		return nil
+	}
+	return sessao
+}
+
+func (s *Servidor) renderizar(w http.ResponseWriter, nome string, dados any) {
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	if err := paginas.ExecuteTemplate(w, nome, dados); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Servidor) login(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	cenario := s.cenario
+	s.mu.Unlock()
+
+	if r.PostForm.Get("loginForm:username") != cenario.Usuario || r.PostForm.Get("loginForm:password") != cenario.Senha {
+		s.renderizar(w, "login", dadosLogin{ViewState: "login", Erro: cenario.MensagemErroLogin})
+		return
+	}
+
+	bytesID := make([]byte, 16)
+	if _, err := rand.Read(bytesID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	id := hex.EncodeToString(bytesID)
+
+	s.mu.Lock()
+	s.logins++
+	s.sessoes[id] = &sessao{
+		localizacao: cenario.LocalizacaoInicial,
+		expirada:    cenario.ExpirarView,
+	}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: nomeCookie, Value: id, Path: "/", HttpOnly: true})
+	http.Redirect(w, r, "/marcacao", http.StatusSeeOther)
+}
+
+func (s *Servidor) marcacao(w http.ResponseWriter, r *http.Request) {
+	sessao := s.sessaoDe(r)
+	if sessao == nil {
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	// Cada GET cria uma view nova; como no JSF com o limite de views da sessão atingido,
+	// a página aberta antes dela deixa de ser aceita
+	s.mu.Lock()
+	sessao.viewState++
+	dados := s.dados(sessao)
+	s.mu.Unlock()
+	s.renderizar(w, "marcacao", dados)
+}
+
+// dados monta o estado exibido na página; deve ser chamado com s.mu travado
+func (s *Servidor) dados(sessao *sessao) dadosMarcacao {
+	dados := dadosMarcacao{
+		ViewState:    strconv.Itoa(sessao.viewState),
+		Localizacao:  sessao.localizacao,
+		Localizacoes: s.cenario.Localizacoes,
+		Modal:        sessao.modal,
+	}
+	for _, op := range sessao.operacoes() {
+		dados.Operacoes = append(dados.Operacoes, botao{ID: idsBotoes[op], Rotulo: op})
+	}
+	for _, m := range sessao.marcacoes {
+		dados.Marcacoes = append(dados.Marcacoes, linhaMarcacao{Rotulo: m.Operacao, Horario: m.Momento.Format("15:04")})
+	}
+	return dados
+}
+
+// operacoes retorna as marcações permitidas a partir da última marcação da sessão
+func (s *sessao) operacoes() []string {
+	if s.localizacao == "" || s.modal != "" {
+		return nil
+	}
+
+	ultima := ""
+	if len(s.marcacoes) > 0 {
+		ultima = s.marcacoes[len(s.marcacoes)-1].Operacao
+	}
+
+	switch ultima {
+	case Entrada:
+		return []string{Almoco, Saida}
+	default:
+		return []string{Entrada}
+	}
+}
+
+func (s *Servidor) ajax(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sessao := s.sessaoDe(r)
+	if sessao == nil {
+		escreverParcial(w, `<redirect url="/"></redirect>`)
+		return
+	}
+
+	s.mu.Lock()
+	atraso := s.cenario.AtrasoAjax
+	s.mu.Unlock()
+	time.Sleep(atraso)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sessao.expirada {
+		sessao.expirada = false
+		sessao.viewState++
+		escreverParcial(w, `<error><error-name>javax.faces.application.ViewExpiredException</error-name><error-message>View could not be restored.</error-message></error>`)
+		return
+	}
+
+	if r.PostForm.Get("javax.faces.ViewState") != strconv.Itoa(sessao.viewState) {
+		escreverParcial(w, `<error><error-name>javax.faces.application.ViewExpiredException</error-name><error-message>ViewState inválido.</error-message></error>`)
+		return
+	}
+
+	var avaliacoes []string
+	fonte := r.PostForm.Get("javax.faces.source")
+	switch fonte {
+	case idTabela:
+		indice, err := strconv.Atoi(r.PostForm.Get(idTabela + "_instantSelection"))
+		if err != nil || indice < 0 || indice >= len(s.cenario.Localizacoes) {
+			http.Error(w, "linha inválida", http.StatusBadRequest)
+			return
+		}
+		sessao.localizacao = s.cenario.Localizacoes[indice]
+
+	case idRespostaSim, idRespostaNao:
+		if sessao.modal == "" || len(sessao.marcacoes) == 0 {
+			http.Error(w, "nenhum modal aberto", http.StatusBadRequest)
+			return
+		}
+		resposta := fonte == idRespostaSim
+		sessao.marcacoes[len(sessao.marcacoes)-1].RespostaIntervalo = &resposta
+		sessao.modal = ""
+
+	default:
+		operacao := ""
+		for _, op := range sessao.operacoes() {
+			if idsBotoes[op] == fonte {
+				operacao = op
+			}
+		}
+		if operacao == "" {
+			break
+		}
+
+		if s.falhasRestantes > 0 {
+			s.falhasRestantes--
+			http.Error(w, "falha simulada", http.StatusInternalServerError)
+			return
+		}
+
+		agora := time.Now()
+		if operacao == Entrada && s.cenario.ModalIntervalo && len(sessao.marcacoes) > 0 {
+			anterior := sessao.marcacoes[len(sessao.marcacoes)-1]
+			if anterior.Operacao == Almoco {
+				duracao := agora.Sub(anterior.Momento)
+				sessao.modal = fmt.Sprintf("Intervalo de %02d:%02d. Deseja considerar este período como intervalo opcional?",
+					int(duracao.Hours()), int(duracao.Minutes())%60)
+				avaliacoes = append(avaliacoes, "PF('"+idDialogo+"').show();")
+			}
+		}
+		sessao.marcacoes = append(sessao.marcacoes, Marcacao{
+			Operacao:    operacao,
+			Localizacao: sessao.localizacao,
+			Momento:     agora,
+		})
+	}
+
+	sessao.viewState++
+	dados := s.dados(sessao)
+
+	var formulario, dialogo bytes.Buffer
+	if err := paginas.ExecuteTemplate(&formulario, "formulario", dados); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := paginas.ExecuteTemplate(&dialogo, "dialogo", dados); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var corpo bytes.Buffer
+	corpo.WriteString("<changes>")
+	fmt.Fprintf(&corpo, `<update id="%s"><![CDATA[%s]]></update>`, idFormulario, formulario.String())
+	fmt.Fprintf(&corpo, `<update id="%s"><![CDATA[%s]]></update>`, idDialogo, dialogo.String())
+	fmt.Fprintf(&corpo, `<update id="%s"><![CDATA[%s]]></update>`, idViewState, dados.ViewState)
+	for _, avaliacao := range avaliacoes {
+		fmt.Fprintf(&corpo, `<eval><![CDATA[%s]]></eval>`, avaliacao)
+	}
+	corpo.WriteString("</changes>")
+	escreverParcial(w, corpo.String())
+}
+
+func escreverParcial(w http.ResponseWriter, conteudo string) {
+	w.Header().Set("Content-Type", "text/xml; charset=UTF-8")
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><partial-response id="j_id1">%s</partial-response>`, conteudo)
+}
@@ -18,6 +18,12 @@ type Module interface {
 
 	// ExibirConfirmacao displays a confirmation prompt for an operation
 	ExibirConfirmacao(operacao clockin.TipoOperacao) (bool, error)
+
+	// ConfirmarIntervaloOpcional asks whether the "Intervalo Opcional" period should be accepted
+	ConfirmarIntervaloOpcional(modal clockin.ModalIntervalo) (bool, error)
+
+	// SolicitarCodigoVerificacao asks for the second factor code of the Softtrade login
+	SolicitarCodigoVerificacao() (string, error)
 }
 
 // NewModule creates a new instance of the UI module
@@ -43,3 +49,11 @@ func (u *UIManager) ExibirMenuOperacao(operacoes []clockin.TipoOperacao) (clocki
 func (u *UIManager) ExibirConfirmacao(operacao clockin.TipoOperacao) (bool, error) {
 	return ExibirConfirmacao(operacao)
 }
+
+func (u *UIManager) ConfirmarIntervaloOpcional(modal clockin.ModalIntervalo) (bool, error) {
+	return ConfirmarIntervaloOpcional(modal)
+}
+
+func (u *UIManager) SolicitarCodigoVerificacao() (string, error) {
+	return SolicitarCodigoVerificacao()
+}
@@ -38,11 +38,13 @@ func NewLoadingSpinner(message string) common.LoadingSpinner {
 // Start starts the spinner animation
 func (l *LoadingSpinner) Start() {
 	l.spinner.Start()
+	common.MarcarSpinnerAtivo(l)
 }
 
 // Stop stops the spinner animation
 func (l *LoadingSpinner) Stop() {
 	l.spinner.Stop()
+	common.DesmarcarSpinnerAtivo(l)
 }
 
 // Success stops the spinner and shows a success message
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gabrieltorresdev/batedor-automatico-ponto/internal/clockin"
 	"github.com/manifoldco/promptui"
@@ -162,3 +163,44 @@ func ExibirConfirmacao(operacao clockin.TipoOperacao) (bool, error) {
 
 	return resultado == "y" || resultado == "Y", nil
 }
+
+func ConfirmarIntervaloOpcional(modal clockin.ModalIntervalo) (bool, error) {
+	fmt.Printf("\n⏰ Intervalo Opcional Detectado\n%s\n", modal.Conteudo)
+
+	prompt := promptui.Prompt{
+		Label:     "Deseja considerar este período como intervalo opcional",
+		IsConfirm: true,
+	}
+
+	resultado, err := prompt.Run()
+	if err != nil {
+		if err == promptui.ErrAbort {
+			return false, nil
+		}
+		return false, fmt.Errorf("erro na confirmação: %w", err)
+	}
+
+	return resultado == "y" || resultado == "Y", nil
+}
+
+// SolicitarCodigoVerificacao pede o código do segundo fator exibido pelo aplicativo autenticador
+func SolicitarCodigoVerificacao() (string, error) {
+	fmt.Println("\n🔐 O Softtrade pediu um código de verificação.")
+
+	prompt := promptui.Prompt{
+		Label: "Código",
+		Validate: func(input string) error {
+			input = strings.TrimSpace(input)
+			if len(input) < 6 || len(input) > 8 || strings.Trim(input, "0123456789") != "" {
+				return fmt.Errorf("o código tem de 6 a 8 dígitos")
+			}
+			return nil
+		},
+	}
+
+	codigo, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler código: %w", err)
+	}
+	return strings.TrimSpace(codigo), nil
+}